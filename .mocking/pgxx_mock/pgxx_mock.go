@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx (interfaces: RDBMS,ReadQuery,WriterCommand,Tx)
+// Source: github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx (interfaces: RDBMS,ReadQuery,WriterCommand,BulkCommand,Notifier,AdvisoryLocker,Tx)
 //
 // Generated by this command:
 //
-//	mockgen -destination=../../.mocking/pgxx_mock/pgxx_mock.go -package=pgxx_mock . RDBMS,ReadQuery,WriterCommand,Tx
+//	mockgen -destination=../../.mocking/pgxx_mock/pgxx_mock.go -package=pgxx_mock . RDBMS,ReadQuery,WriterCommand,BulkCommand,Notifier,AdvisoryLocker,Tx
 //
 
 // Package pgxx_mock is a generated GoMock package.
@@ -14,10 +14,12 @@ import (
 	reflect "reflect"
 
 	squirrel "github.com/Masterminds/squirrel"
+	databases "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	pgxx "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx"
 	primitive "github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 	pgx "github.com/jackc/pgx/v5"
 	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pgxpool "github.com/jackc/pgx/v5/pgxpool"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -45,6 +47,50 @@ func (m *MockRDBMS) EXPECT() *MockRDBMSMockRecorder {
 	return m.recorder
 }
 
+// AdvisoryLock mocks base method.
+func (m *MockRDBMS) AdvisoryLock(ctx context.Context, key string) (*pgxx.AdvisoryLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvisoryLock", ctx, key)
+	ret0, _ := ret[0].(*pgxx.AdvisoryLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvisoryLock indicates an expected call of AdvisoryLock.
+func (mr *MockRDBMSMockRecorder) AdvisoryLock(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvisoryLock", reflect.TypeOf((*MockRDBMS)(nil).AdvisoryLock), ctx, key)
+}
+
+// CopyFrom mocks base method.
+func (m *MockRDBMS) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFrom", ctx, tableName, columnNames, rowSrc)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFrom indicates an expected call of CopyFrom.
+func (mr *MockRDBMSMockRecorder) CopyFrom(ctx, tableName, columnNames, rowSrc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFrom", reflect.TypeOf((*MockRDBMS)(nil).CopyFrom), ctx, tableName, columnNames, rowSrc)
+}
+
+// Dialect mocks base method.
+func (m *MockRDBMS) Dialect() databases.Dialect {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dialect")
+	ret0, _ := ret[0].(databases.Dialect)
+	return ret0
+}
+
+// Dialect indicates an expected call of Dialect.
+func (mr *MockRDBMSMockRecorder) Dialect() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dialect", reflect.TypeOf((*MockRDBMS)(nil).Dialect))
+}
+
 // Exec mocks base method.
 func (m *MockRDBMS) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +126,34 @@ func (mr *MockRDBMSMockRecorder) ExecSq(ctx, query any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecSq", reflect.TypeOf((*MockRDBMS)(nil).ExecSq), ctx, query)
 }
 
+// GetDB mocks base method.
+func (m *MockRDBMS) GetDB() *pgxpool.Pool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDB")
+	ret0, _ := ret[0].(*pgxpool.Pool)
+	return ret0
+}
+
+// GetDB indicates an expected call of GetDB.
+func (mr *MockRDBMSMockRecorder) GetDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockRDBMS)(nil).GetDB))
+}
+
+// Notify mocks base method.
+func (m *MockRDBMS) Notify(ctx context.Context, channel, payload string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, channel, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockRDBMSMockRecorder) Notify(ctx, channel, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockRDBMS)(nil).Notify), ctx, channel, payload)
+}
+
 // Query mocks base method.
 func (m *MockRDBMS) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
 	m.ctrl.T.Helper()
@@ -148,6 +222,21 @@ func (mr *MockRDBMSMockRecorder) QuerySq(ctx, query, fn any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySq", reflect.TypeOf((*MockRDBMS)(nil).QuerySq), ctx, query, fn)
 }
 
+// QuerySqCursorPagination mocks base method.
+func (m *MockRDBMS) QuerySqCursorPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, keyset primitive.Keyset, paginationInput primitive.CursorPaginationInput, fn func(pgx.Rows) ([]any, error)) (primitive.CursorPaginationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuerySqCursorPagination", ctx, countQuery, query, keyset, paginationInput, fn)
+	ret0, _ := ret[0].(primitive.CursorPaginationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuerySqCursorPagination indicates an expected call of QuerySqCursorPagination.
+func (mr *MockRDBMSMockRecorder) QuerySqCursorPagination(ctx, countQuery, query, keyset, paginationInput, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqCursorPagination", reflect.TypeOf((*MockRDBMS)(nil).QuerySqCursorPagination), ctx, countQuery, query, keyset, paginationInput, fn)
+}
+
 // QuerySqPagination mocks base method.
 func (m *MockRDBMS) QuerySqPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, paginationInput primitive.PaginationInput, fn func(pgx.Rows) error) (primitive.PaginationOutput, error) {
 	m.ctrl.T.Helper()
@@ -163,6 +252,80 @@ func (mr *MockRDBMSMockRecorder) QuerySqPagination(ctx, countQuery, query, pagin
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqPagination", reflect.TypeOf((*MockRDBMS)(nil).QuerySqPagination), ctx, countQuery, query, paginationInput, fn)
 }
 
+// SendBatch mocks base method.
+func (m *MockRDBMS) SendBatch(ctx context.Context, batch *pgx.Batch) *pgxx.BatchReader {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", ctx, batch)
+	ret0, _ := ret[0].(*pgxx.BatchReader)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch.
+func (mr *MockRDBMSMockRecorder) SendBatch(ctx, batch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*MockRDBMS)(nil).SendBatch), ctx, batch)
+}
+
+// TryAdvisoryLock mocks base method.
+func (m *MockRDBMS) TryAdvisoryLock(ctx context.Context, key string) (*pgxx.AdvisoryLock, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryAdvisoryLock", ctx, key)
+	ret0, _ := ret[0].(*pgxx.AdvisoryLock)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TryAdvisoryLock indicates an expected call of TryAdvisoryLock.
+func (mr *MockRDBMSMockRecorder) TryAdvisoryLock(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryAdvisoryLock", reflect.TypeOf((*MockRDBMS)(nil).TryAdvisoryLock), ctx, key)
+}
+
+// TryXactAdvisoryLock mocks base method.
+func (m *MockRDBMS) TryXactAdvisoryLock(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryXactAdvisoryLock", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryXactAdvisoryLock indicates an expected call of TryXactAdvisoryLock.
+func (mr *MockRDBMSMockRecorder) TryXactAdvisoryLock(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryXactAdvisoryLock", reflect.TypeOf((*MockRDBMS)(nil).TryXactAdvisoryLock), ctx, key)
+}
+
+// UpdateWithVersion mocks base method.
+func (m *MockRDBMS) UpdateWithVersion(ctx context.Context, update databases.VersionedUpdate) (databases.Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWithVersion", ctx, update)
+	ret0, _ := ret[0].(databases.Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWithVersion indicates an expected call of UpdateWithVersion.
+func (mr *MockRDBMSMockRecorder) UpdateWithVersion(ctx, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithVersion", reflect.TypeOf((*MockRDBMS)(nil).UpdateWithVersion), ctx, update)
+}
+
+// XactAdvisoryLock mocks base method.
+func (m *MockRDBMS) XactAdvisoryLock(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XactAdvisoryLock", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// XactAdvisoryLock indicates an expected call of XactAdvisoryLock.
+func (mr *MockRDBMSMockRecorder) XactAdvisoryLock(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XactAdvisoryLock", reflect.TypeOf((*MockRDBMS)(nil).XactAdvisoryLock), ctx, key)
+}
+
 // MockReadQuery is a mock of ReadQuery interface.
 type MockReadQuery struct {
 	ctrl     *gomock.Controller
@@ -216,6 +379,21 @@ func (mr *MockReadQueryMockRecorder) QuerySq(ctx, query, fn any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySq", reflect.TypeOf((*MockReadQuery)(nil).QuerySq), ctx, query, fn)
 }
 
+// QuerySqCursorPagination mocks base method.
+func (m *MockReadQuery) QuerySqCursorPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, keyset primitive.Keyset, paginationInput primitive.CursorPaginationInput, fn func(pgx.Rows) ([]any, error)) (primitive.CursorPaginationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuerySqCursorPagination", ctx, countQuery, query, keyset, paginationInput, fn)
+	ret0, _ := ret[0].(primitive.CursorPaginationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuerySqCursorPagination indicates an expected call of QuerySqCursorPagination.
+func (mr *MockReadQueryMockRecorder) QuerySqCursorPagination(ctx, countQuery, query, keyset, paginationInput, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqCursorPagination", reflect.TypeOf((*MockReadQuery)(nil).QuerySqCursorPagination), ctx, countQuery, query, keyset, paginationInput, fn)
+}
+
 // QuerySqPagination mocks base method.
 func (m *MockReadQuery) QuerySqPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, paginationInput primitive.PaginationInput, fn func(pgx.Rows) error) (primitive.PaginationOutput, error) {
 	m.ctrl.T.Helper()
@@ -270,6 +448,196 @@ func (mr *MockWriterCommandMockRecorder) ExecSq(ctx, query any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecSq", reflect.TypeOf((*MockWriterCommand)(nil).ExecSq), ctx, query)
 }
 
+// UpdateWithVersion mocks base method.
+func (m *MockWriterCommand) UpdateWithVersion(ctx context.Context, update databases.VersionedUpdate) (databases.Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWithVersion", ctx, update)
+	ret0, _ := ret[0].(databases.Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWithVersion indicates an expected call of UpdateWithVersion.
+func (mr *MockWriterCommandMockRecorder) UpdateWithVersion(ctx, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithVersion", reflect.TypeOf((*MockWriterCommand)(nil).UpdateWithVersion), ctx, update)
+}
+
+// MockBulkCommand is a mock of BulkCommand interface.
+type MockBulkCommand struct {
+	ctrl     *gomock.Controller
+	recorder *MockBulkCommandMockRecorder
+	isgomock struct{}
+}
+
+// MockBulkCommandMockRecorder is the mock recorder for MockBulkCommand.
+type MockBulkCommandMockRecorder struct {
+	mock *MockBulkCommand
+}
+
+// NewMockBulkCommand creates a new mock instance.
+func NewMockBulkCommand(ctrl *gomock.Controller) *MockBulkCommand {
+	mock := &MockBulkCommand{ctrl: ctrl}
+	mock.recorder = &MockBulkCommandMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBulkCommand) EXPECT() *MockBulkCommandMockRecorder {
+	return m.recorder
+}
+
+// CopyFrom mocks base method.
+func (m *MockBulkCommand) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFrom", ctx, tableName, columnNames, rowSrc)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFrom indicates an expected call of CopyFrom.
+func (mr *MockBulkCommandMockRecorder) CopyFrom(ctx, tableName, columnNames, rowSrc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFrom", reflect.TypeOf((*MockBulkCommand)(nil).CopyFrom), ctx, tableName, columnNames, rowSrc)
+}
+
+// SendBatch mocks base method.
+func (m *MockBulkCommand) SendBatch(ctx context.Context, batch *pgx.Batch) *pgxx.BatchReader {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", ctx, batch)
+	ret0, _ := ret[0].(*pgxx.BatchReader)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch.
+func (mr *MockBulkCommandMockRecorder) SendBatch(ctx, batch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*MockBulkCommand)(nil).SendBatch), ctx, batch)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, channel, payload string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, channel, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, channel, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, channel, payload)
+}
+
+// MockAdvisoryLocker is a mock of AdvisoryLocker interface.
+type MockAdvisoryLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdvisoryLockerMockRecorder
+	isgomock struct{}
+}
+
+// MockAdvisoryLockerMockRecorder is the mock recorder for MockAdvisoryLocker.
+type MockAdvisoryLockerMockRecorder struct {
+	mock *MockAdvisoryLocker
+}
+
+// NewMockAdvisoryLocker creates a new mock instance.
+func NewMockAdvisoryLocker(ctrl *gomock.Controller) *MockAdvisoryLocker {
+	mock := &MockAdvisoryLocker{ctrl: ctrl}
+	mock.recorder = &MockAdvisoryLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdvisoryLocker) EXPECT() *MockAdvisoryLockerMockRecorder {
+	return m.recorder
+}
+
+// AdvisoryLock mocks base method.
+func (m *MockAdvisoryLocker) AdvisoryLock(ctx context.Context, key string) (*pgxx.AdvisoryLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvisoryLock", ctx, key)
+	ret0, _ := ret[0].(*pgxx.AdvisoryLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvisoryLock indicates an expected call of AdvisoryLock.
+func (mr *MockAdvisoryLockerMockRecorder) AdvisoryLock(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvisoryLock", reflect.TypeOf((*MockAdvisoryLocker)(nil).AdvisoryLock), ctx, key)
+}
+
+// TryAdvisoryLock mocks base method.
+func (m *MockAdvisoryLocker) TryAdvisoryLock(ctx context.Context, key string) (*pgxx.AdvisoryLock, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryAdvisoryLock", ctx, key)
+	ret0, _ := ret[0].(*pgxx.AdvisoryLock)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TryAdvisoryLock indicates an expected call of TryAdvisoryLock.
+func (mr *MockAdvisoryLockerMockRecorder) TryAdvisoryLock(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryAdvisoryLock", reflect.TypeOf((*MockAdvisoryLocker)(nil).TryAdvisoryLock), ctx, key)
+}
+
+// TryXactAdvisoryLock mocks base method.
+func (m *MockAdvisoryLocker) TryXactAdvisoryLock(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryXactAdvisoryLock", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryXactAdvisoryLock indicates an expected call of TryXactAdvisoryLock.
+func (mr *MockAdvisoryLockerMockRecorder) TryXactAdvisoryLock(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryXactAdvisoryLock", reflect.TypeOf((*MockAdvisoryLocker)(nil).TryXactAdvisoryLock), ctx, key)
+}
+
+// XactAdvisoryLock mocks base method.
+func (m *MockAdvisoryLocker) XactAdvisoryLock(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XactAdvisoryLock", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// XactAdvisoryLock indicates an expected call of XactAdvisoryLock.
+func (mr *MockAdvisoryLockerMockRecorder) XactAdvisoryLock(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XactAdvisoryLock", reflect.TypeOf((*MockAdvisoryLocker)(nil).XactAdvisoryLock), ctx, key)
+}
+
 // MockTx is a mock of Tx interface.
 type MockTx struct {
 	ctrl     *gomock.Controller
@@ -15,6 +15,7 @@ import (
 	reflect "reflect"
 
 	squirrel "github.com/Masterminds/squirrel"
+	databases "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	sqlx "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/sqlx"
 	primitive "github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 	gomock "go.uber.org/mock/gomock"
@@ -44,6 +45,34 @@ func (m *MockRDBMS) EXPECT() *MockRDBMSMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockRDBMS) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockRDBMSMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRDBMS)(nil).Close))
+}
+
+// Dialect mocks base method.
+func (m *MockRDBMS) Dialect() databases.Dialect {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dialect")
+	ret0, _ := ret[0].(databases.Dialect)
+	return ret0
+}
+
+// Dialect indicates an expected call of Dialect.
+func (mr *MockRDBMSMockRecorder) Dialect() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dialect", reflect.TypeOf((*MockRDBMS)(nil).Dialect))
+}
+
 // ExecContext mocks base method.
 func (m *MockRDBMS) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -65,38 +94,32 @@ func (mr *MockRDBMSMockRecorder) ExecContext(ctx, query any, args ...any) *gomoc
 }
 
 // ExecSq mocks base method.
-func (m *MockRDBMS) ExecSq(ctx context.Context, query squirrel.Sqlizer, useStmt bool) (sql.Result, error) {
+func (m *MockRDBMS) ExecSq(ctx context.Context, query squirrel.Sqlizer) (sql.Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecSq", ctx, query, useStmt)
+	ret := m.ctrl.Call(m, "ExecSq", ctx, query)
 	ret0, _ := ret[0].(sql.Result)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ExecSq indicates an expected call of ExecSq.
-func (mr *MockRDBMSMockRecorder) ExecSq(ctx, query, useStmt any) *gomock.Call {
+func (mr *MockRDBMSMockRecorder) ExecSq(ctx, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecSq", reflect.TypeOf((*MockRDBMS)(nil).ExecSq), ctx, query, useStmt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecSq", reflect.TypeOf((*MockRDBMS)(nil).ExecSq), ctx, query)
 }
 
-// ExecStmtContext mocks base method.
-func (m *MockRDBMS) ExecStmtContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+// Ping mocks base method.
+func (m *MockRDBMS) Ping(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, query}
-	for _, a := range args {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "ExecStmtContext", varargs...)
-	ret0, _ := ret[0].(sql.Result)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ExecStmtContext indicates an expected call of ExecStmtContext.
-func (mr *MockRDBMSMockRecorder) ExecStmtContext(ctx, query any, args ...any) *gomock.Call {
+// Ping indicates an expected call of Ping.
+func (mr *MockRDBMSMockRecorder) Ping(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, query}, args...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecStmtContext", reflect.TypeOf((*MockRDBMS)(nil).ExecStmtContext), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRDBMS)(nil).Ping), ctx)
 }
 
 // PrepareContext mocks base method.
@@ -154,87 +177,77 @@ func (mr *MockRDBMSMockRecorder) QueryRowContext(ctx, query any, args ...any) *g
 }
 
 // QueryRowSq mocks base method.
-func (m *MockRDBMS) QueryRowSq(ctx context.Context, query squirrel.Sqlizer, useStmt bool) (*sql.Row, error) {
+func (m *MockRDBMS) QueryRowSq(ctx context.Context, query squirrel.Sqlizer) (*sql.Row, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryRowSq", ctx, query, useStmt)
+	ret := m.ctrl.Call(m, "QueryRowSq", ctx, query)
 	ret0, _ := ret[0].(*sql.Row)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryRowSq indicates an expected call of QueryRowSq.
-func (mr *MockRDBMSMockRecorder) QueryRowSq(ctx, query, useStmt any) *gomock.Call {
+func (mr *MockRDBMSMockRecorder) QueryRowSq(ctx, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRowSq", reflect.TypeOf((*MockRDBMS)(nil).QueryRowSq), ctx, query, useStmt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRowSq", reflect.TypeOf((*MockRDBMS)(nil).QueryRowSq), ctx, query)
 }
 
-// QueryRowStmtContext mocks base method.
-func (m *MockRDBMS) QueryRowStmtContext(ctx context.Context, query string, args ...any) (*sql.Row, error) {
+// QuerySq mocks base method.
+func (m *MockRDBMS) QuerySq(ctx context.Context, query squirrel.Sqlizer, fn func(*sql.Rows) error) error {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, query}
-	for _, a := range args {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "QueryRowStmtContext", varargs...)
-	ret0, _ := ret[0].(*sql.Row)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "QuerySq", ctx, query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// QueryRowStmtContext indicates an expected call of QueryRowStmtContext.
-func (mr *MockRDBMSMockRecorder) QueryRowStmtContext(ctx, query any, args ...any) *gomock.Call {
+// QuerySq indicates an expected call of QuerySq.
+func (mr *MockRDBMSMockRecorder) QuerySq(ctx, query, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, query}, args...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRowStmtContext", reflect.TypeOf((*MockRDBMS)(nil).QueryRowStmtContext), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySq", reflect.TypeOf((*MockRDBMS)(nil).QuerySq), ctx, query, fn)
 }
 
-// QuerySq mocks base method.
-func (m *MockRDBMS) QuerySq(ctx context.Context, query squirrel.Sqlizer, useStmt bool, fn func(*sql.Rows) error) error {
+// QuerySqCursorPagination mocks base method.
+func (m *MockRDBMS) QuerySqCursorPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, keyset primitive.Keyset, paginationInput primitive.CursorPaginationInput, fn func(*sql.Rows) ([]any, error)) (primitive.CursorPaginationOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QuerySq", ctx, query, useStmt, fn)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "QuerySqCursorPagination", ctx, countQuery, query, keyset, paginationInput, fn)
+	ret0, _ := ret[0].(primitive.CursorPaginationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// QuerySq indicates an expected call of QuerySq.
-func (mr *MockRDBMSMockRecorder) QuerySq(ctx, query, useStmt, fn any) *gomock.Call {
+// QuerySqCursorPagination indicates an expected call of QuerySqCursorPagination.
+func (mr *MockRDBMSMockRecorder) QuerySqCursorPagination(ctx, countQuery, query, keyset, paginationInput, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySq", reflect.TypeOf((*MockRDBMS)(nil).QuerySq), ctx, query, useStmt, fn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqCursorPagination", reflect.TypeOf((*MockRDBMS)(nil).QuerySqCursorPagination), ctx, countQuery, query, keyset, paginationInput, fn)
 }
 
 // QuerySqPagination mocks base method.
-func (m *MockRDBMS) QuerySqPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, useStmt bool, paginationInput primitive.PaginationInput, fn func(*sql.Rows) error) (primitive.PaginationOutput, error) {
+func (m *MockRDBMS) QuerySqPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, paginationInput primitive.PaginationInput, fn func(*sql.Rows) error) (primitive.PaginationOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QuerySqPagination", ctx, countQuery, query, useStmt, paginationInput, fn)
+	ret := m.ctrl.Call(m, "QuerySqPagination", ctx, countQuery, query, paginationInput, fn)
 	ret0, _ := ret[0].(primitive.PaginationOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QuerySqPagination indicates an expected call of QuerySqPagination.
-func (mr *MockRDBMSMockRecorder) QuerySqPagination(ctx, countQuery, query, useStmt, paginationInput, fn any) *gomock.Call {
+func (mr *MockRDBMSMockRecorder) QuerySqPagination(ctx, countQuery, query, paginationInput, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqPagination", reflect.TypeOf((*MockRDBMS)(nil).QuerySqPagination), ctx, countQuery, query, useStmt, paginationInput, fn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqPagination", reflect.TypeOf((*MockRDBMS)(nil).QuerySqPagination), ctx, countQuery, query, paginationInput, fn)
 }
 
-// QueryStmtContext mocks base method.
-func (m *MockRDBMS) QueryStmtContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+// UpdateWithVersion mocks base method.
+func (m *MockRDBMS) UpdateWithVersion(ctx context.Context, update databases.VersionedUpdate) (databases.Version, error) {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, query}
-	for _, a := range args {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "QueryStmtContext", varargs...)
-	ret0, _ := ret[0].(*sql.Rows)
+	ret := m.ctrl.Call(m, "UpdateWithVersion", ctx, update)
+	ret0, _ := ret[0].(databases.Version)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// QueryStmtContext indicates an expected call of QueryStmtContext.
-func (mr *MockRDBMSMockRecorder) QueryStmtContext(ctx, query any, args ...any) *gomock.Call {
+// UpdateWithVersion indicates an expected call of UpdateWithVersion.
+func (mr *MockRDBMSMockRecorder) UpdateWithVersion(ctx, update any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, query}, args...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryStmtContext", reflect.TypeOf((*MockRDBMS)(nil).QueryStmtContext), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithVersion", reflect.TypeOf((*MockRDBMS)(nil).UpdateWithVersion), ctx, update)
 }
 
 // MockReadQuery is a mock of ReadQuery interface.
@@ -262,47 +275,62 @@ func (m *MockReadQuery) EXPECT() *MockReadQueryMockRecorder {
 }
 
 // QueryRowSq mocks base method.
-func (m *MockReadQuery) QueryRowSq(ctx context.Context, query squirrel.Sqlizer, useStmt bool) (*sql.Row, error) {
+func (m *MockReadQuery) QueryRowSq(ctx context.Context, query squirrel.Sqlizer) (*sql.Row, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryRowSq", ctx, query, useStmt)
+	ret := m.ctrl.Call(m, "QueryRowSq", ctx, query)
 	ret0, _ := ret[0].(*sql.Row)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryRowSq indicates an expected call of QueryRowSq.
-func (mr *MockReadQueryMockRecorder) QueryRowSq(ctx, query, useStmt any) *gomock.Call {
+func (mr *MockReadQueryMockRecorder) QueryRowSq(ctx, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRowSq", reflect.TypeOf((*MockReadQuery)(nil).QueryRowSq), ctx, query, useStmt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRowSq", reflect.TypeOf((*MockReadQuery)(nil).QueryRowSq), ctx, query)
 }
 
 // QuerySq mocks base method.
-func (m *MockReadQuery) QuerySq(ctx context.Context, query squirrel.Sqlizer, useStmt bool, fn func(*sql.Rows) error) error {
+func (m *MockReadQuery) QuerySq(ctx context.Context, query squirrel.Sqlizer, fn func(*sql.Rows) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QuerySq", ctx, query, useStmt, fn)
+	ret := m.ctrl.Call(m, "QuerySq", ctx, query, fn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // QuerySq indicates an expected call of QuerySq.
-func (mr *MockReadQueryMockRecorder) QuerySq(ctx, query, useStmt, fn any) *gomock.Call {
+func (mr *MockReadQueryMockRecorder) QuerySq(ctx, query, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySq", reflect.TypeOf((*MockReadQuery)(nil).QuerySq), ctx, query, useStmt, fn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySq", reflect.TypeOf((*MockReadQuery)(nil).QuerySq), ctx, query, fn)
+}
+
+// QuerySqCursorPagination mocks base method.
+func (m *MockReadQuery) QuerySqCursorPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, keyset primitive.Keyset, paginationInput primitive.CursorPaginationInput, fn func(*sql.Rows) ([]any, error)) (primitive.CursorPaginationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuerySqCursorPagination", ctx, countQuery, query, keyset, paginationInput, fn)
+	ret0, _ := ret[0].(primitive.CursorPaginationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuerySqCursorPagination indicates an expected call of QuerySqCursorPagination.
+func (mr *MockReadQueryMockRecorder) QuerySqCursorPagination(ctx, countQuery, query, keyset, paginationInput, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqCursorPagination", reflect.TypeOf((*MockReadQuery)(nil).QuerySqCursorPagination), ctx, countQuery, query, keyset, paginationInput, fn)
 }
 
 // QuerySqPagination mocks base method.
-func (m *MockReadQuery) QuerySqPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, useStmt bool, paginationInput primitive.PaginationInput, fn func(*sql.Rows) error) (primitive.PaginationOutput, error) {
+func (m *MockReadQuery) QuerySqPagination(ctx context.Context, countQuery, query squirrel.SelectBuilder, paginationInput primitive.PaginationInput, fn func(*sql.Rows) error) (primitive.PaginationOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QuerySqPagination", ctx, countQuery, query, useStmt, paginationInput, fn)
+	ret := m.ctrl.Call(m, "QuerySqPagination", ctx, countQuery, query, paginationInput, fn)
 	ret0, _ := ret[0].(primitive.PaginationOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QuerySqPagination indicates an expected call of QuerySqPagination.
-func (mr *MockReadQueryMockRecorder) QuerySqPagination(ctx, countQuery, query, useStmt, paginationInput, fn any) *gomock.Call {
+func (mr *MockReadQueryMockRecorder) QuerySqPagination(ctx, countQuery, query, paginationInput, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqPagination", reflect.TypeOf((*MockReadQuery)(nil).QuerySqPagination), ctx, countQuery, query, useStmt, paginationInput, fn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySqPagination", reflect.TypeOf((*MockReadQuery)(nil).QuerySqPagination), ctx, countQuery, query, paginationInput, fn)
 }
 
 // MockWriterCommand is a mock of WriterCommand interface.
@@ -330,18 +358,33 @@ func (m *MockWriterCommand) EXPECT() *MockWriterCommandMockRecorder {
 }
 
 // ExecSq mocks base method.
-func (m *MockWriterCommand) ExecSq(ctx context.Context, query squirrel.Sqlizer, useStmt bool) (sql.Result, error) {
+func (m *MockWriterCommand) ExecSq(ctx context.Context, query squirrel.Sqlizer) (sql.Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecSq", ctx, query, useStmt)
+	ret := m.ctrl.Call(m, "ExecSq", ctx, query)
 	ret0, _ := ret[0].(sql.Result)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ExecSq indicates an expected call of ExecSq.
-func (mr *MockWriterCommandMockRecorder) ExecSq(ctx, query, useStmt any) *gomock.Call {
+func (mr *MockWriterCommandMockRecorder) ExecSq(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecSq", reflect.TypeOf((*MockWriterCommand)(nil).ExecSq), ctx, query)
+}
+
+// UpdateWithVersion mocks base method.
+func (m *MockWriterCommand) UpdateWithVersion(ctx context.Context, update databases.VersionedUpdate) (databases.Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWithVersion", ctx, update)
+	ret0, _ := ret[0].(databases.Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWithVersion indicates an expected call of UpdateWithVersion.
+func (mr *MockWriterCommandMockRecorder) UpdateWithVersion(ctx, update any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecSq", reflect.TypeOf((*MockWriterCommand)(nil).ExecSq), ctx, query, useStmt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithVersion", reflect.TypeOf((*MockWriterCommand)(nil).UpdateWithVersion), ctx, update)
 }
 
 // MockTx is a mock of Tx interface.
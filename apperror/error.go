@@ -29,6 +29,15 @@ type Error struct {
 	Stack string
 
 	Cause error // <- tambah
+
+	// per-field errors, aman ditampilkan ke user
+	Violations []Violation
+}
+
+// Violation describes why a single input field was rejected.
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 func (e *Error) Unwrap() error { return e.Cause }
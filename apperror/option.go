@@ -18,3 +18,10 @@ func WithStack() Option {
 func WithCause(err error) Option {
 	return func(e *Error) { e.Cause = err }
 }
+
+// WithViolations menambahkan daftar field yang tidak valid ke error.
+func WithViolations(violations ...Violation) Option {
+	return func(e *Error) {
+		e.Violations = append(e.Violations, violations...)
+	}
+}
@@ -0,0 +1,18 @@
+package databases
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the identity of the user or service performing
+// the operation (e.g. a user ID). Table builders stamp it into the created_by /
+// updated_by audit columns. Typically installed by an authentication middleware.
+func WithActor(ctx context.Context, actor any) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor.
+func ActorFromContext(ctx context.Context) (any, bool) {
+	actor := ctx.Value(actorKey{})
+	return actor, actor != nil
+}
@@ -0,0 +1,419 @@
+package databases
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// MaxPlaceholders is the maximum number of bind parameters of a single statement,
+// in PostgreSQL and in MySQL prepared statements.
+const MaxPlaceholders = 65535
+
+// DefaultMaxPacketSize is the MySQL max_allowed_packet assumed by BatchInsert unless
+// set with MaxPacketSize: 4 MiB, the lowest default across MySQL and MariaDB versions.
+const DefaultMaxPacketSize = 4 << 20
+
+// BatchInsert builds dialect-aware INSERT statements for one or many rows, optionally
+// as upserts (PostgreSQL ON CONFLICT, MySQL ON DUPLICATE KEY UPDATE). Rows are split
+// into batches staying within MaxPlaceholders bind parameters and, on MySQL, within
+// max_allowed_packet.
+//
+// Errors (mismatched rows, unsupported clauses) are reported by Batches and ToSql.
+//
+// Example:
+//
+//	b := databases.NewBatchInsert(db.Dialect(), "stock").
+//		Columns("sku", "warehouse", "qty").
+//		OnConflict("sku", "warehouse")
+//	b.DoUpdateSet("qty", "stock.qty + "+b.Excluded("qty"))
+//	for _, s := range items {
+//		b.Values(s.SKU, s.Warehouse, s.Qty)
+//	}
+//	batches, err := b.Batches()
+//	for _, batch := range batches {
+//		_, err = db.ExecSq(ctx, batch)
+//	}
+type BatchInsert struct {
+	dialect     Dialect
+	table       string
+	columns     []string
+	rows        [][]any
+	conflict    []string
+	hasConflict bool
+	updateAll   bool
+	updates     []upsertSet
+	doNothing   bool
+	returning   []string
+	maxRows     int
+	maxPacket   int
+	err         error
+}
+
+// upsertSet is a "column = expr" assignment of the update part of an upsert.
+type upsertSet struct {
+	column string
+	expr   string
+	args   []any
+}
+
+// NewBatchInsert creates an INSERT builder for table.
+func NewBatchInsert(d Dialect, table string) *BatchInsert {
+	return &BatchInsert{dialect: d, table: table, maxPacket: DefaultMaxPacketSize}
+}
+
+// Columns sets the inserted columns. It is optional with SetMap and SetStruct,
+// whose first row defines the columns.
+func (b *BatchInsert) Columns(columns ...string) *BatchInsert {
+	b.columns = columns
+	return b
+}
+
+// Values adds a row, with one value per column.
+func (b *BatchInsert) Values(values ...any) *BatchInsert {
+	if len(values) != len(b.columns) {
+		b.fail(fmt.Errorf("row %d has %d values for %d columns", len(b.rows), len(values), len(b.columns)))
+		return b
+	}
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// SetMap adds a row from a column -> value map. Without Columns, the columns are the
+// sorted keys of the first map; every row must then hold exactly the same keys.
+func (b *BatchInsert) SetMap(row map[string]any) *BatchInsert {
+	if len(b.columns) == 0 {
+		b.columns = make([]string, 0, len(row))
+		for column := range row {
+			b.columns = append(b.columns, column)
+		}
+		sort.Strings(b.columns)
+	}
+	if len(row) != len(b.columns) {
+		b.fail(fmt.Errorf("row %d has %d values for %d columns", len(b.rows), len(row), len(b.columns)))
+		return b
+	}
+
+	values := make([]any, len(b.columns))
+	for i, column := range b.columns {
+		value, ok := row[column]
+		if !ok {
+			b.fail(fmt.Errorf("row %d has no value for column %q", len(b.rows), column))
+			return b
+		}
+		values[i] = value
+	}
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// SetStruct adds a row from a struct (or pointer to it) mapped with `db` tags, see
+// StructFields. Fields tagged with the readonly option (`db:"id,readonly"`, generated
+// by the database) are not inserted. Without Columns, the columns are the struct's;
+// otherwise the struct must map every column.
+func (b *BatchInsert) SetStruct(v any) *BatchInsert {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			b.fail(fmt.Errorf("row %d is a nil pointer", len(b.rows)))
+			return b
+		}
+		rv = rv.Elem()
+	}
+	fields, err := StructFields(rv.Type())
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+
+	row := make(map[string]any, len(fields))
+	columns := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.HasOption("readonly") {
+			continue
+		}
+		if fv, err := rv.FieldByIndexErr(f.Index); err == nil {
+			row[f.Column] = fv.Interface()
+		} else {
+			row[f.Column] = nil // promoted through a nil embedded pointer
+		}
+		columns = append(columns, f.Column)
+	}
+	if len(b.columns) == 0 {
+		b.columns = columns
+	}
+
+	values := make([]any, len(b.columns))
+	for i, column := range b.columns {
+		value, ok := row[column]
+		if !ok {
+			b.fail(fmt.Errorf("row %d (%s) has no field for column %q", len(b.rows), rv.Type(), column))
+			return b
+		}
+		values[i] = value
+	}
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// OnConflict turns the statement into an upsert on the given conflict target columns,
+// completed with DoUpdate, DoUpdateSet or DoNothing. PostgreSQL requires a target for
+// DO UPDATE; MySQL ignores it and reacts to a conflict on any unique key.
+func (b *BatchInsert) OnConflict(target ...string) *BatchInsert {
+	b.hasConflict = true
+	b.conflict = target
+	return b
+}
+
+// DoUpdate overwrites the given columns of the conflicting row with the values that
+// were proposed for insertion (EXCLUDED.col / VALUES(col)). Without columns, every
+// inserted column that is not part of the conflict target is overwritten.
+func (b *BatchInsert) DoUpdate(columns ...string) *BatchInsert {
+	b.hasConflict = true
+	if len(columns) == 0 {
+		b.updateAll = true
+	}
+	for _, column := range columns {
+		b.updates = append(b.updates, upsertSet{column: column, expr: b.Excluded(column)})
+	}
+	return b
+}
+
+// DoUpdateSet sets column of the conflicting row to the SQL expression expr, with ?
+// placeholders for args. Use Excluded to reference the proposed values, e.g.
+// DoUpdateSet("qty", "stock.qty + "+b.Excluded("qty")).
+func (b *BatchInsert) DoUpdateSet(column, expr string, args ...any) *BatchInsert {
+	b.hasConflict = true
+	b.updates = append(b.updates, upsertSet{column: column, expr: expr, args: args})
+	return b
+}
+
+// DoNothing keeps the conflicting row unchanged.
+func (b *BatchInsert) DoNothing() *BatchInsert {
+	b.hasConflict = true
+	b.doNothing = true
+	return b
+}
+
+// Returning adds a RETURNING clause, so the statements must be run as queries.
+// It is only supported by PostgreSQL.
+func (b *BatchInsert) Returning(columns ...string) *BatchInsert {
+	b.returning = columns
+	return b
+}
+
+// MaxRows limits the number of rows per statement. Default: 0, no limit besides the
+// placeholder and packet limits.
+func (b *BatchInsert) MaxRows(n int) *BatchInsert {
+	b.maxRows = n
+	return b
+}
+
+// MaxPacketSize sets the MySQL max_allowed_packet, in bytes, that statements must fit in.
+// Default: DefaultMaxPacketSize. The statement size is estimated from its values.
+func (b *BatchInsert) MaxPacketSize(n int) *BatchInsert {
+	if n > 0 {
+		b.maxPacket = n
+	}
+	return b
+}
+
+// Excluded returns the reference to the value proposed for insertion of column, for use
+// in DoUpdateSet: EXCLUDED.column on PostgreSQL, VALUES(column) on MySQL.
+func (b *BatchInsert) Excluded(column string) string {
+	if b.dialect == DialectMySQL {
+		return "VALUES(" + column + ")"
+	}
+	return "EXCLUDED." + column
+}
+
+// Batches builds the INSERT statements, each holding as many rows as the limits allow.
+// It returns no statement when no row was added.
+func (b *BatchInsert) Batches() ([]squirrel.Sqlizer, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.columns) == 0 && len(b.rows) > 0 {
+		return nil, errors.New("batch insert: no columns")
+	}
+
+	suffix, suffixArgs, err := b.suffix()
+	if err != nil {
+		return nil, err
+	}
+
+	maxArgs := MaxPlaceholders - len(suffixArgs)
+	base := len("INSERT INTO  () VALUES ") + len(b.table) + len(strings.Join(b.columns, ",")) + len(suffix) + 1
+	for _, arg := range suffixArgs {
+		base += argSize(arg)
+	}
+	checkPacket := b.dialect == DialectMySQL
+
+	batches := make([]squirrel.Sqlizer, 0, 1)
+	start, args, size := 0, 0, base
+	for i, row := range b.rows {
+		rowSize := 2 + 2*len(row)
+		for _, v := range row {
+			rowSize += argSize(v)
+		}
+		if len(row) > maxArgs || (checkPacket && base+rowSize > b.maxPacket) {
+			return nil, fmt.Errorf("batch insert: row %d alone exceeds the statement limits", i)
+		}
+
+		full := (b.maxRows > 0 && i-start >= b.maxRows) ||
+			args+len(row) > maxArgs ||
+			(checkPacket && size+rowSize > b.maxPacket)
+		if full {
+			batches = append(batches, b.statement(b.rows[start:i], suffix, suffixArgs))
+			start, args, size = i, 0, base
+		}
+		args += len(row)
+		size += rowSize
+	}
+	if start < len(b.rows) {
+		batches = append(batches, b.statement(b.rows[start:], suffix, suffixArgs))
+	}
+	return batches, nil
+}
+
+// ToSql builds the rows as a single statement, implementing squirrel.Sqlizer.
+// It fails if the rows do not fit in one statement: use Batches for large inserts.
+func (b *BatchInsert) ToSql() (string, []any, error) {
+	batches, err := b.Batches()
+	if err != nil {
+		return "", nil, err
+	}
+	switch len(batches) {
+	case 0:
+		return "", nil, errors.New("batch insert: no rows")
+	case 1:
+		return batches[0].ToSql()
+	default:
+		return "", nil, fmt.Errorf("batch insert: rows need %d statements, use Batches", len(batches))
+	}
+}
+
+func (b *BatchInsert) statement(rows [][]any, suffix string, suffixArgs []any) squirrel.Sqlizer {
+	query := squirrel.Insert(b.table).Columns(b.columns...).PlaceholderFormat(b.dialect.Placeholder())
+	for _, row := range rows {
+		query = query.Values(row...)
+	}
+	if suffix != "" {
+		query = query.Suffix(suffix, suffixArgs...)
+	}
+	return query
+}
+
+// suffix builds the upsert and RETURNING clauses of the dialect.
+func (b *BatchInsert) suffix() (string, []any, error) {
+	if len(b.returning) > 0 && b.dialect == DialectMySQL {
+		return "", nil, errors.New("batch insert: RETURNING is not supported by mysql")
+	}
+	if !b.hasConflict {
+		return b.returningClause(), nil, nil
+	}
+
+	updates := b.updates
+	if b.updateAll {
+		for _, column := range b.columns {
+			if !slices.Contains(b.conflict, column) && !slices.ContainsFunc(updates, func(s upsertSet) bool { return s.column == column }) {
+				updates = append(updates, upsertSet{column: column, expr: b.Excluded(column)})
+			}
+		}
+	}
+	if len(updates) == 0 && !b.doNothing && !b.updateAll {
+		return "", nil, errors.New("batch insert: OnConflict requires DoUpdate, DoUpdateSet or DoNothing")
+	}
+
+	set := make([]string, 0, len(updates))
+	args := make([]any, 0)
+	for _, s := range updates {
+		set = append(set, s.column+" = "+s.expr)
+		args = append(args, s.args...)
+	}
+
+	var clause string
+	switch b.dialect {
+	case DialectMySQL:
+		if len(set) == 0 || b.doNothing {
+			// MySQL has no DO NOTHING: a no-op assignment keeps the row unchanged
+			// without ignoring other errors like INSERT IGNORE would.
+			column := b.columns[0]
+			if len(b.conflict) > 0 {
+				column = b.conflict[0]
+			}
+			set, args = []string{column + " = " + column}, nil
+		}
+		clause = "ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+	default:
+		clause = "ON CONFLICT"
+		if len(b.conflict) > 0 {
+			clause += " (" + strings.Join(b.conflict, ", ") + ")"
+		}
+		if len(set) == 0 || b.doNothing {
+			clause += " DO NOTHING"
+			args = nil
+		} else {
+			if len(b.conflict) == 0 {
+				return "", nil, errors.New("batch insert: ON CONFLICT DO UPDATE requires a conflict target")
+			}
+			clause += " DO UPDATE SET " + strings.Join(set, ", ")
+		}
+	}
+
+	if returning := b.returningClause(); returning != "" {
+		clause += " " + returning
+	}
+	return clause, args, nil
+}
+
+func (b *BatchInsert) returningClause() string {
+	if len(b.returning) == 0 {
+		return ""
+	}
+	return "RETURNING " + strings.Join(b.returning, ", ")
+}
+
+// fail records the first error of the builder.
+func (b *BatchInsert) fail(err error) {
+	if b.err == nil {
+		b.err = fmt.Errorf("batch insert: %w", err)
+	}
+}
+
+// argSize estimates the size of a bind parameter in a MySQL packet.
+func argSize(v any) int {
+	switch v := v.(type) {
+	case nil:
+		return 1
+	case string:
+		return len(v) + 9
+	case []byte:
+		return len(v) + 9
+	case driver.Valuer:
+		// Size the encoded value, e.g. the JSON document of a databases.JSON.
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return 1
+		}
+		value, err := v.Value()
+		if err != nil {
+			return 16
+		}
+		return argSize(value)
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Len() + 9
+		}
+		if rv.Kind() == reflect.String {
+			return rv.Len() + 9
+		}
+		return 16
+	}
+}
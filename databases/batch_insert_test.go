@@ -0,0 +1,230 @@
+package databases
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBatchInsert_Upsert(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() *BatchInsert
+		wantSQL  string
+		wantArgs []any
+		wantErr  bool
+	}{
+		{
+			name: "postgres do update with returning",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectPostgres, "users").
+					Columns("id", "email", "name").
+					Values(1, "a@b.c", "rama").
+					OnConflict("id").
+					DoUpdate("email").
+					Returning("id", "updated_at")
+			},
+			wantSQL:  "INSERT INTO users (id,email,name) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email RETURNING id, updated_at",
+			wantArgs: []any{1, "a@b.c", "rama"},
+		},
+		{
+			name: "postgres update every non target column",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectPostgres, "stock").
+					SetMap(map[string]any{"sku": "A", "warehouse": 1, "qty": 5}).
+					OnConflict("sku", "warehouse").
+					DoUpdate()
+			},
+			wantSQL:  "INSERT INTO stock (qty,sku,warehouse) VALUES ($1,$2,$3) ON CONFLICT (sku, warehouse) DO UPDATE SET qty = EXCLUDED.qty",
+			wantArgs: []any{5, "A", 1},
+		},
+		{
+			name: "postgres expression with args",
+			build: func() *BatchInsert {
+				b := NewBatchInsert(DialectPostgres, "stock").Columns("sku", "qty").Values("A", 5).OnConflict("sku")
+				return b.DoUpdateSet("qty", "stock.qty + "+b.Excluded("qty")+" * ?", 2)
+			},
+			wantSQL:  "INSERT INTO stock (sku,qty) VALUES ($1,$2) ON CONFLICT (sku) DO UPDATE SET qty = stock.qty + EXCLUDED.qty * $3",
+			wantArgs: []any{"A", 5, 2},
+		},
+		{
+			name: "postgres do nothing without target",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectPostgres, "tags").Columns("name").Values("go").OnConflict().DoNothing()
+			},
+			wantSQL:  "INSERT INTO tags (name) VALUES ($1) ON CONFLICT DO NOTHING",
+			wantArgs: []any{"go"},
+		},
+		{
+			name: "postgres do update without target",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectPostgres, "tags").Columns("name").Values("go").DoUpdate("name")
+			},
+			wantErr: true,
+		},
+		{
+			name: "on conflict without action",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectPostgres, "tags").Columns("name").Values("go").OnConflict("name")
+			},
+			wantErr: true,
+		},
+		{
+			name: "mysql on duplicate key update",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectMySQL, "users").
+					Columns("id", "email", "name").
+					Values(1, "a@b.c", "rama").
+					OnConflict("id").
+					DoUpdate("email", "name")
+			},
+			wantSQL:  "INSERT INTO users (id,email,name) VALUES (?,?,?) ON DUPLICATE KEY UPDATE email = VALUES(email), name = VALUES(name)",
+			wantArgs: []any{1, "a@b.c", "rama"},
+		},
+		{
+			name: "mysql do nothing",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectMySQL, "tags").Columns("name").Values("go").OnConflict("name").DoNothing()
+			},
+			wantSQL:  "INSERT INTO tags (name) VALUES (?) ON DUPLICATE KEY UPDATE name = name",
+			wantArgs: []any{"go"},
+		},
+		{
+			name: "mysql returning",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectMySQL, "tags").Columns("name").Values("go").Returning("id")
+			},
+			wantErr: true,
+		},
+		{
+			name: "mismatched row",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectPostgres, "tags").Columns("id", "name").Values("go")
+			},
+			wantErr: true,
+		},
+		{
+			name: "map missing column",
+			build: func() *BatchInsert {
+				return NewBatchInsert(DialectPostgres, "tags").
+					SetMap(map[string]any{"id": 1, "name": "go"}).
+					SetMap(map[string]any{"id": 2, "label": "db"})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.build().ToSql()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ToSql() = %q, want error", sql)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Fatalf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBatchInsert_SetStruct(t *testing.T) {
+	type user struct {
+		ID    int64 `db:"id,readonly"`
+		Email string
+		Name  string `db:"name"`
+		Note  string `db:"-"`
+	}
+
+	sql, args, err := NewBatchInsert(DialectPostgres, "users").
+		SetStruct(user{ID: 1, Email: "a@b.c", Name: "rama"}).
+		SetStruct(&user{Email: "d@e.f", Name: "ahmad"}).
+		ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	if want := "INSERT INTO users (email,name) VALUES ($1,$2),($3,$4)"; sql != want {
+		t.Fatalf("sql = %q, want %q", sql, want)
+	}
+	if want := []any{"a@b.c", "rama", "d@e.f", "ahmad"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+
+	_, _, err = NewBatchInsert(DialectPostgres, "users").Columns("id", "name").SetStruct(user{}).ToSql()
+	if err == nil {
+		t.Fatalf("SetStruct() of a readonly column: want error")
+	}
+}
+
+func TestBatchInsert_Batches(t *testing.T) {
+	rows := func(b *BatchInsert, n int) *BatchInsert {
+		for i := range n {
+			b.Values(i, strings.Repeat("x", 100))
+		}
+		return b
+	}
+	count := func(t *testing.T, b *BatchInsert) int {
+		t.Helper()
+		batches, err := b.Batches()
+		if err != nil {
+			t.Fatalf("Batches() error = %v", err)
+		}
+		total := 0
+		for _, batch := range batches {
+			_, args, err := batch.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			total += len(args)
+		}
+		if total != len(b.rows)*len(b.columns) {
+			t.Fatalf("args = %d, want %d", total, len(b.rows)*len(b.columns))
+		}
+		return len(batches)
+	}
+
+	if n := count(t, rows(NewBatchInsert(DialectPostgres, "t").Columns("id", "v"), 40000)); n != 2 {
+		t.Fatalf("postgres batches = %d, want 2 within 65535 placeholders", n)
+	}
+	if n := count(t, rows(NewBatchInsert(DialectPostgres, "t").Columns("id", "v").MaxRows(10), 25)); n != 3 {
+		t.Fatalf("MaxRows batches = %d, want 3", n)
+	}
+	if n := count(t, rows(NewBatchInsert(DialectMySQL, "t").Columns("id", "v").MaxPacketSize(1000), 20)); n < 3 {
+		t.Fatalf("mysql batches = %d, want at least 3 within a 1000 bytes packet", n)
+	}
+	if n := count(t, NewBatchInsert(DialectPostgres, "t").Columns("id")); n != 0 {
+		t.Fatalf("empty batches = %d, want 0", n)
+	}
+
+	_, err := rows(NewBatchInsert(DialectMySQL, "t").Columns("id", "v").MaxPacketSize(100), 1).Batches()
+	if err == nil {
+		t.Fatalf("Batches() of a row larger than the packet: want error")
+	}
+	_, _, err = rows(NewBatchInsert(DialectPostgres, "t").Columns("id", "v").MaxRows(1), 2).ToSql()
+	if err == nil {
+		t.Fatalf("ToSql() of several batches: want error")
+	}
+}
+
+func TestBatchInsert_BatchesValuerSize(t *testing.T) {
+	doc := JSON[map[string]string]{V: map[string]string{"body": strings.Repeat("x", 400)}, Valid: true}
+	b := NewBatchInsert(DialectMySQL, "docs").Columns("id", "doc").MaxPacketSize(1000)
+	for i := range 10 {
+		b.Values(i, doc)
+	}
+
+	batches, err := b.Batches()
+	if err != nil {
+		t.Fatalf("Batches() error = %v", err)
+	}
+	if len(batches) < 5 {
+		t.Fatalf("batches = %d, want at least 5: two 400 bytes documents per 1000 bytes packet", len(batches))
+	}
+}
@@ -0,0 +1,31 @@
+package databases
+
+import "github.com/Masterminds/squirrel"
+
+// Dialect identifies the SQL dialect spoken by a database.
+type Dialect string
+
+const (
+	// DialectPostgres is PostgreSQL.
+	DialectPostgres Dialect = "postgres"
+	// DialectMySQL is MySQL / MariaDB.
+	DialectMySQL Dialect = "mysql"
+)
+
+// Placeholder returns the squirrel placeholder format of the dialect:
+// squirrel.Dollar for PostgreSQL, squirrel.Question otherwise.
+func (d Dialect) Placeholder() squirrel.PlaceholderFormat {
+	if d == DialectPostgres {
+		return squirrel.Dollar
+	}
+	return squirrel.Question
+}
+
+// Rebind rewrites the ? placeholders of sql to the dialect's format, see QuestionToDollar.
+// The SQL is returned unchanged for dialects using ? placeholders.
+func (d Dialect) Rebind(sql string) string {
+	if d == DialectPostgres {
+		return QuestionToDollar(sql)
+	}
+	return sql
+}
@@ -0,0 +1,230 @@
+package databases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultExplainLimit   = 10
+	defaultExplainWindow  = time.Minute
+	defaultExplainTimeout = 5 * time.Second
+)
+
+// ExplainConfig configures the EXPLAIN capture of slow queries by the sqlx and pgxx
+// ObservabilityHook. Only single SELECT statements (or read-only WITH queries) are
+// explained, without ANALYZE: the query itself is never run again.
+type ExplainConfig struct {
+	SampleRate    float64          // Fraction of the slow SELECTs explained, in (0, 1]. Default: 1
+	Limit         int              // Maximum EXPLAINs per Window. Default: 10
+	Window        time.Duration    // Default: 1m
+	MaxConcurrent int              // Maximum EXPLAINs running at once; others are skipped. Default: 1
+	Timeout       time.Duration    // Timeout of an EXPLAIN. Default: 5s
+	Deny          []*regexp.Regexp // Statements matching any pattern are never explained
+}
+
+// ExplainSampler decides which slow queries are explained, enforcing the sampling,
+// rate limit, concurrency and denylist of an ExplainConfig. It is safe for concurrent use.
+type ExplainSampler struct {
+	cfg     ExplainConfig
+	budget  *QueryBudget
+	running chan struct{}
+}
+
+// NewExplainSampler creates an ExplainSampler, applying the defaults of cfg.
+func NewExplainSampler(cfg ExplainConfig) *ExplainSampler {
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = 1
+	}
+	if cfg.Limit <= 0 {
+		cfg.Limit = defaultExplainLimit
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultExplainWindow
+	}
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = 1
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultExplainTimeout
+	}
+	return &ExplainSampler{
+		cfg:     cfg,
+		budget:  NewQueryBudget(cfg.Limit, cfg.Window),
+		running: make(chan struct{}, cfg.MaxConcurrent),
+	}
+}
+
+// Timeout returns the timeout of an EXPLAIN.
+func (s *ExplainSampler) Timeout() time.Duration {
+	return s.cfg.Timeout
+}
+
+// Acquire reports whether query, of dialect d, should be explained now. When it does,
+// done must be called once the EXPLAIN finished, to free its concurrency slot.
+func (s *ExplainSampler) Acquire(d Dialect, query string) (done func(), ok bool) {
+	if !IsExplainable(d, query) {
+		return nil, false
+	}
+	for _, re := range s.cfg.Deny {
+		if re.MatchString(query) {
+			return nil, false
+		}
+	}
+	if s.cfg.SampleRate < 1 && rand.Float64() >= s.cfg.SampleRate {
+		return nil, false
+	}
+
+	select {
+	case s.running <- struct{}{}:
+	default:
+		return nil, false
+	}
+	if !s.budget.Allow("") {
+		<-s.running
+		return nil, false
+	}
+	return func() { <-s.running }, true
+}
+
+// IsExplainable reports whether query is a single read-only SELECT (or WITH query)
+// of dialect d that can safely be explained.
+func IsExplainable(d Dialect, query string) bool {
+	stmts := splitStatements(query, d)
+	if len(stmts) != 1 {
+		return false
+	}
+	switch leadingKeyword(stmts[0]) {
+	case "SELECT", "WITH":
+		return !IsWriteStatement(d, query)
+	default:
+		return false
+	}
+}
+
+// ExplainQuery returns the EXPLAIN statement producing the JSON plan of query for the
+// dialect: EXPLAIN (FORMAT JSON) on PostgreSQL, EXPLAIN FORMAT=JSON on MySQL.
+func ExplainQuery(d Dialect, query string) (string, error) {
+	switch d {
+	case DialectPostgres:
+		return "EXPLAIN (FORMAT JSON) " + query, nil
+	case DialectMySQL:
+		return "EXPLAIN FORMAT=JSON " + query, nil
+	default:
+		return "", fmt.Errorf("explain: unsupported dialect %q", d)
+	}
+}
+
+// PlanSummary is the digest of a query plan attached to slow query logs and spans.
+type PlanSummary struct {
+	Cost     float64         // Estimated total cost, in planner units
+	Rows     float64         // Estimated rows: returned by the plan on PostgreSQL, examined on MySQL
+	SeqScans []string        // Tables read with a sequential (full table) scan
+	Plan     json.RawMessage // The JSON plan
+}
+
+// ParsePlan summarizes the JSON plan of an EXPLAIN built with ExplainQuery.
+func ParsePlan(d Dialect, plan []byte) (PlanSummary, error) {
+	switch d {
+	case DialectPostgres:
+		return parsePostgresPlan(plan)
+	case DialectMySQL:
+		return parseMySQLPlan(plan)
+	default:
+		return PlanSummary{}, fmt.Errorf("explain: unsupported dialect %q", d)
+	}
+}
+
+type postgresPlanNode struct {
+	NodeType     string             `json:"Node Type"`
+	RelationName string             `json:"Relation Name"`
+	TotalCost    float64            `json:"Total Cost"`
+	PlanRows     float64            `json:"Plan Rows"`
+	Plans        []postgresPlanNode `json:"Plans"`
+}
+
+func parsePostgresPlan(plan []byte) (PlanSummary, error) {
+	var root []struct {
+		Plan postgresPlanNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &root); err != nil {
+		return PlanSummary{}, fmt.Errorf("explain: parse plan: %w", err)
+	}
+	if len(root) == 0 {
+		return PlanSummary{}, errors.New("explain: empty plan")
+	}
+
+	s := PlanSummary{Cost: root[0].Plan.TotalCost, Rows: root[0].Plan.PlanRows, Plan: plan}
+	var walk func(n postgresPlanNode)
+	walk = func(n postgresPlanNode) {
+		if n.NodeType == "Seq Scan" || n.NodeType == "Parallel Seq Scan" {
+			s.SeqScans = append(s.SeqScans, n.RelationName)
+		}
+		for _, child := range n.Plans {
+			walk(child)
+		}
+	}
+	walk(root[0].Plan)
+	return s, nil
+}
+
+func parseMySQLPlan(plan []byte) (PlanSummary, error) {
+	var root struct {
+		QueryBlock map[string]any `json:"query_block"`
+	}
+	if err := json.Unmarshal(plan, &root); err != nil {
+		return PlanSummary{}, fmt.Errorf("explain: parse plan: %w", err)
+	}
+	if root.QueryBlock == nil {
+		return PlanSummary{}, errors.New("explain: empty plan")
+	}
+
+	s := PlanSummary{Plan: plan}
+	if costInfo, ok := root.QueryBlock["cost_info"].(map[string]any); ok {
+		s.Cost = jsonNumber(costInfo["query_cost"])
+	}
+
+	// Tables are nested under nested_loop, ordering_operation, subqueries... at any depth.
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if table, ok := v["table"].(map[string]any); ok {
+				s.Rows += jsonNumber(table["rows_examined_per_scan"])
+				if table["access_type"] == "ALL" {
+					name, _ := table["table_name"].(string)
+					s.SeqScans = append(s.SeqScans, name)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(root.QueryBlock)
+	sort.Strings(s.SeqScans)
+	return s, nil
+}
+
+// jsonNumber reads a MySQL plan number, which is encoded either as a number or a string.
+func jsonNumber(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	default:
+		return 0
+	}
+}
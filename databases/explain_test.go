@@ -0,0 +1,106 @@
+package databases
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestIsExplainable(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT * FROM users WHERE id = $1", true},
+		{"  select 1;", true},
+		{"(SELECT 1) UNION (SELECT 2)", true},
+		{"WITH t AS (SELECT 1) SELECT * FROM t", true},
+		{"WITH t AS (SELECT 1) DELETE FROM users", false},
+		{"UPDATE users SET name = 'x'", false},
+		{"SELECT 1; DROP TABLE users", false},
+		{"EXPLAIN SELECT 1", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsExplainable(DialectPostgres, tt.query); got != tt.want {
+			t.Errorf("IsExplainable(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestExplainSampler_Acquire(t *testing.T) {
+	s := NewExplainSampler(ExplainConfig{
+		Limit:         2,
+		MaxConcurrent: 1,
+		Deny:          []*regexp.Regexp{regexp.MustCompile(`(?i)\bpg_sleep\b`)},
+	})
+
+	if _, ok := s.Acquire(DialectPostgres, "SELECT pg_sleep(1)"); ok {
+		t.Fatalf("Acquire() of a denied statement: want false")
+	}
+	if _, ok := s.Acquire(DialectPostgres, "DELETE FROM users"); ok {
+		t.Fatalf("Acquire() of a write: want false")
+	}
+
+	done, ok := s.Acquire(DialectPostgres, "SELECT 1")
+	if !ok {
+		t.Fatalf("Acquire() = false, want true")
+	}
+	if _, ok := s.Acquire(DialectPostgres, "SELECT 2"); ok {
+		t.Fatalf("Acquire() beyond MaxConcurrent: want false")
+	}
+	done()
+
+	done, ok = s.Acquire(DialectPostgres, "SELECT 2")
+	if !ok {
+		t.Fatalf("Acquire() after done = false, want true")
+	}
+	done()
+	if _, ok := s.Acquire(DialectPostgres, "SELECT 3"); ok {
+		t.Fatalf("Acquire() beyond Limit: want false")
+	}
+}
+
+func TestExplainQuery(t *testing.T) {
+	if got, _ := ExplainQuery(DialectPostgres, "SELECT 1"); got != "EXPLAIN (FORMAT JSON) SELECT 1" {
+		t.Fatalf("ExplainQuery(postgres) = %q", got)
+	}
+	if got, _ := ExplainQuery(DialectMySQL, "SELECT 1"); got != "EXPLAIN FORMAT=JSON SELECT 1" {
+		t.Fatalf("ExplainQuery(mysql) = %q", got)
+	}
+	if _, err := ExplainQuery(Dialect("sqlite"), "SELECT 1"); err == nil {
+		t.Fatalf("ExplainQuery(sqlite): want error")
+	}
+}
+
+func TestParsePlan(t *testing.T) {
+	pg := `[{"Plan": {"Node Type": "Hash Join", "Total Cost": 245.5, "Plan Rows": 120,
+		"Plans": [
+			{"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 200, "Plan Rows": 5000},
+			{"Node Type": "Hash", "Plans": [{"Node Type": "Index Scan", "Relation Name": "users"}]}
+		]}}]`
+	got, err := ParsePlan(DialectPostgres, []byte(pg))
+	if err != nil {
+		t.Fatalf("ParsePlan(postgres) error = %v", err)
+	}
+	if got.Cost != 245.5 || got.Rows != 120 || !reflect.DeepEqual(got.SeqScans, []string{"orders"}) {
+		t.Fatalf("ParsePlan(postgres) = %+v", got)
+	}
+
+	my := `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "1204.50"},
+		"nested_loop": [
+			{"table": {"table_name": "orders", "access_type": "ALL", "rows_examined_per_scan": 1000}},
+			{"table": {"table_name": "users", "access_type": "eq_ref", "rows_examined_per_scan": 1}}
+		]}}`
+	got, err = ParsePlan(DialectMySQL, []byte(my))
+	if err != nil {
+		t.Fatalf("ParsePlan(mysql) error = %v", err)
+	}
+	if got.Cost != 1204.5 || got.Rows != 1001 || !reflect.DeepEqual(got.SeqScans, []string{"orders"}) {
+		t.Fatalf("ParsePlan(mysql) = %+v", got)
+	}
+
+	if _, err = ParsePlan(DialectPostgres, []byte(`[]`)); err == nil {
+		t.Fatalf("ParsePlan() of an empty plan: want error")
+	}
+}
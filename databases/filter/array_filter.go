@@ -5,11 +5,14 @@ import (
 	"strings"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 )
 
 // ArrayFilter represents a fluent filter builder for PostgreSQL array columns.
 // It uses Go generics to support any type for array elements.
 // Use NewArrayFilter() to create an instance and chain methods to build filter conditions.
+// With Dialect(databases.DialectMySQL) the column is a JSON array and the operators are
+// rendered with JSON_ARRAY, JSON_CONTAINS, JSON_OVERLAPS and JSON_LENGTH.
 //
 // Supported PostgreSQL array operators:
 //   - @> (contains) - Does the array contain all the specified elements?
@@ -32,7 +35,8 @@ import (
 //	    Overlaps("electronics", "gadgets")
 //	condition, args := filter.Build()
 type ArrayFilter[T any] struct {
-	column string
+	column  string
+	dialect databases.Dialect
 
 	// Equality operators
 	eqValues  []T // Array equality
@@ -100,6 +104,29 @@ func (f *ArrayFilter[T]) Column(column string) *ArrayFilter[T] {
 	return f
 }
 
+// Dialect sets the SQL dialect the filter is rendered for. Default: PostgreSQL.
+//
+// Parameters:
+//   - d: the dialect; MySQL expects a JSON array column
+//
+// Returns:
+//   - *ArrayFilter[T]: the filter instance for method chaining
+//
+// Example:
+//
+//	filter.Column("tags").Dialect(databases.DialectMySQL).Contains("go")
+//	// SQL: JSON_CONTAINS(tags, JSON_ARRAY(?))
+func (f *ArrayFilter[T]) Dialect(d databases.Dialect) *ArrayFilter[T] {
+	f.dialect = d
+	return f
+}
+
+func (f *ArrayFilter[T]) withDialect(d databases.Dialect) Filter {
+	c := *f
+	c.dialect = d
+	return &c
+}
+
 // Eq sets an array equality condition (array = ARRAY[...]).
 //
 // Parameters:
@@ -343,7 +370,8 @@ func (f *ArrayFilter[T]) IsNotNull() *ArrayFilter[T] {
 
 // Build returns the SQL condition string and arguments for use with prepared statements.
 // Multiple conditions are combined with AND.
-// Returns "1=1" if no conditions are set (always true condition).
+// Returns "1=1" if no conditions are set (always true condition), and "1=0" if the
+// filter cannot be rendered for its dialect (use BuildSquirrel to get the error).
 //
 // Returns:
 //   - condition: the SQL WHERE condition string with ? placeholders
@@ -359,6 +387,10 @@ func (f *ArrayFilter[T]) IsNotNull() *ArrayFilter[T] {
 //	// condition: "tags @> ARRAY[?, ?] AND tags IS NOT NULL"
 //	// args: []any{1, 2}
 func (f *ArrayFilter[T]) Build() (condition string, args []any) {
+	if !isPostgres(f.dialect) {
+		return buildFromSqlizer(f.BuildSquirrel())
+	}
+
 	var conditions []string
 	args = []any{}
 
@@ -492,7 +524,7 @@ func (f *ArrayFilter[T]) Build() (condition string, args []any) {
 //
 // Returns:
 //   - squirrel.Sqlizer: a squirrel condition that can be used with Where()
-//   - error: always nil in current implementation
+//   - error: *UnsupportedOperatorError if an operator has no equivalent in the dialect
 //
 // Example:
 //
@@ -512,99 +544,66 @@ func (f *ArrayFilter[T]) BuildSquirrel() (squirrel.Sqlizer, error) {
 		where = append(where, squirrel.Expr(f.column+" IS NOT NULL"))
 	}
 
-	// Array equality
-	if len(f.eqValues) > 0 {
-		vals := make([]any, len(f.eqValues))
-		for i, v := range f.eqValues {
-			vals[i] = v
-		}
-		placeholders := make([]string, len(vals))
-		for i := range placeholders {
-			placeholders[i] = "?"
-		}
-		arrayStr := fmt.Sprintf("ARRAY[%s]", strings.Join(placeholders, ", "))
-		where = append(where, squirrel.Expr(f.column+" = "+arrayStr, vals...))
+	// Array comparisons: = / <> / @> / <@ / &&
+	comparisons := []struct {
+		op     arrayOp
+		values []T
+	}{
+		{arrayEq, f.eqValues},
+		{arrayNeq, f.neqValues},
+		{arrayContains, f.containsValues},
+		{arrayContainedBy, f.containedByValues},
+		{arrayOverlaps, f.overlapsValues},
 	}
-
-	// Array inequality
-	if len(f.neqValues) > 0 {
-		vals := make([]any, len(f.neqValues))
-		for i, v := range f.neqValues {
-			vals[i] = v
-		}
-		placeholders := make([]string, len(vals))
-		for i := range placeholders {
-			placeholders[i] = "?"
+	for _, c := range comparisons {
+		if len(c.values) == 0 {
+			continue
 		}
-		arrayStr := fmt.Sprintf("ARRAY[%s]", strings.Join(placeholders, ", "))
-		where = append(where, squirrel.Expr(f.column+" <> "+arrayStr, vals...))
-	}
-
-	// @> (contains)
-	if len(f.containsValues) > 0 {
-		vals := make([]any, len(f.containsValues))
-		for i, v := range f.containsValues {
+		vals := make([]any, len(c.values))
+		for i, v := range c.values {
 			vals[i] = v
 		}
-		placeholders := make([]string, len(vals))
-		for i := range placeholders {
-			placeholders[i] = "?"
+		pred, err := arraySqlizer(f.dialect, f.column, c.op, vals)
+		if err != nil {
+			return nil, err
 		}
-		arrayStr := fmt.Sprintf("ARRAY[%s]", strings.Join(placeholders, ", "))
-		where = append(where, squirrel.Expr(f.column+" @> "+arrayStr, vals...))
+		where = append(where, pred)
 	}
 
-	// <@ (contained by)
-	if len(f.containedByValues) > 0 {
-		vals := make([]any, len(f.containedByValues))
-		for i, v := range f.containedByValues {
-			vals[i] = v
-		}
-		placeholders := make([]string, len(vals))
-		for i := range placeholders {
-			placeholders[i] = "?"
-		}
-		arrayStr := fmt.Sprintf("ARRAY[%s]", strings.Join(placeholders, ", "))
-		where = append(where, squirrel.Expr(f.column+" <@ "+arrayStr, vals...))
+	// Length conditions
+	lengths := []struct {
+		op    string
+		value *int
+	}{
+		{"=", f.lengthEq},
+		{">", f.lengthGt},
+		{"<", f.lengthLt},
+		{">=", f.lengthGte},
+		{"<=", f.lengthLte},
 	}
-
-	// && (overlaps)
-	if len(f.overlapsValues) > 0 {
-		vals := make([]any, len(f.overlapsValues))
-		for i, v := range f.overlapsValues {
-			vals[i] = v
+	for _, l := range lengths {
+		if l.value == nil {
+			continue
 		}
-		placeholders := make([]string, len(vals))
-		for i := range placeholders {
-			placeholders[i] = "?"
+		length, err := arrayLength(f.dialect, f.column)
+		if err != nil {
+			return nil, err
 		}
-		arrayStr := fmt.Sprintf("ARRAY[%s]", strings.Join(placeholders, ", "))
-		where = append(where, squirrel.Expr(f.column+" && "+arrayStr, vals...))
-	}
-
-	// Length conditions
-	if f.lengthEq != nil {
-		where = append(where, squirrel.Expr(fmt.Sprintf("array_length(%s, 1) = ?", f.column), *f.lengthEq))
-	}
-	if f.lengthGt != nil {
-		where = append(where, squirrel.Expr(fmt.Sprintf("array_length(%s, 1) > ?", f.column), *f.lengthGt))
-	}
-	if f.lengthLt != nil {
-		where = append(where, squirrel.Expr(fmt.Sprintf("array_length(%s, 1) < ?", f.column), *f.lengthLt))
-	}
-	if f.lengthGte != nil {
-		where = append(where, squirrel.Expr(fmt.Sprintf("array_length(%s, 1) >= ?", f.column), *f.lengthGte))
-	}
-	if f.lengthLte != nil {
-		where = append(where, squirrel.Expr(fmt.Sprintf("array_length(%s, 1) <= ?", f.column), *f.lengthLte))
+		where = append(where, squirrel.Expr(fmt.Sprintf("%s %s ?", length, l.op), *l.value))
 	}
 
 	// Empty/not empty checks
-	if f.isEmpty {
-		where = append(where, squirrel.Expr(fmt.Sprintf("(array_length(%s, 1) IS NULL OR array_length(%s, 1) = 0)", f.column, f.column)))
-	}
-	if f.isNotEmpty {
-		where = append(where, squirrel.Expr(fmt.Sprintf("(array_length(%s, 1) IS NOT NULL AND array_length(%s, 1) > 0)", f.column, f.column)))
+	if f.isEmpty || f.isNotEmpty {
+		length, err := arrayLength(f.dialect, f.column)
+		if err != nil {
+			return nil, err
+		}
+		if f.isEmpty {
+			where = append(where, squirrel.Expr(fmt.Sprintf("(%s IS NULL OR %s = 0)", length, length)))
+		}
+		if f.isNotEmpty {
+			where = append(where, squirrel.Expr(fmt.Sprintf("(%s IS NOT NULL AND %s > 0)", length, length)))
+		}
 	}
 
 	// Return nil if no conditions (caller can skip Where)
@@ -0,0 +1,127 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// ErrUnsupportedOperator is matched (errors.Is) by every *UnsupportedOperatorError.
+var ErrUnsupportedOperator = errors.New("filter: operator not supported by dialect")
+
+// UnsupportedOperatorError is returned by BuildSquirrel when a filter uses an
+// operator that has no equivalent in the configured dialect.
+type UnsupportedOperatorError struct {
+	Operator string
+	Dialect  databases.Dialect
+}
+
+func (e *UnsupportedOperatorError) Error() string {
+	return fmt.Sprintf("filter: operator %s is not supported by dialect %q", e.Operator, e.Dialect)
+}
+
+func (e *UnsupportedOperatorError) Unwrap() error { return ErrUnsupportedOperator }
+
+// dialectFilter is implemented by the filters whose rendering depends on the dialect.
+// withDialect returns a shallow copy rendered for d, so that a group never changes
+// the children its caller owns.
+type dialectFilter interface {
+	withDialect(d databases.Dialect) Filter
+}
+
+// isPostgres reports whether d renders PostgreSQL syntax; an unset dialect defaults to PostgreSQL.
+func isPostgres(d databases.Dialect) bool {
+	return d == "" || d == databases.DialectPostgres
+}
+
+// iLikeSqlizer builds a case-insensitive (NOT) LIKE condition.
+// MySQL has no ILIKE, so it is emulated with LOWER(column) LIKE LOWER(?).
+func iLikeSqlizer(d databases.Dialect, column, pattern string, not bool) (squirrel.Sqlizer, error) {
+	switch {
+	case isPostgres(d):
+		if not {
+			return squirrel.NotILike{column: pattern}, nil
+		}
+		return squirrel.ILike{column: pattern}, nil
+	case d == databases.DialectMySQL:
+		op := "LIKE"
+		if not {
+			op = "NOT LIKE"
+		}
+		return squirrel.Expr(fmt.Sprintf("LOWER(%s) %s LOWER(?)", column, op), pattern), nil
+	default:
+		op := "ILIKE"
+		if not {
+			op = "NOT ILIKE"
+		}
+		return nil, &UnsupportedOperatorError{Operator: op, Dialect: d}
+	}
+}
+
+type arrayOp string
+
+const (
+	arrayEq          arrayOp = "="
+	arrayNeq         arrayOp = "<>"
+	arrayContains    arrayOp = "@>"
+	arrayContainedBy arrayOp = "<@"
+	arrayOverlaps    arrayOp = "&&"
+)
+
+// arraySqlizer builds an array comparison. PostgreSQL compares against ARRAY[...];
+// MySQL stores arrays as JSON and uses JSON_ARRAY with JSON_CONTAINS / JSON_OVERLAPS
+// (MySQL 8.0.17+).
+func arraySqlizer(d databases.Dialect, column string, op arrayOp, vals []any) (squirrel.Sqlizer, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(vals)), ", ")
+
+	switch {
+	case isPostgres(d):
+		return squirrel.Expr(fmt.Sprintf("%s %s ARRAY[%s]", column, op, placeholders), vals...), nil
+	case d == databases.DialectMySQL:
+		array := "JSON_ARRAY(" + placeholders + ")"
+		switch op {
+		case arrayContains:
+			return squirrel.Expr(fmt.Sprintf("JSON_CONTAINS(%s, %s)", column, array), vals...), nil
+		case arrayContainedBy:
+			return squirrel.Expr(fmt.Sprintf("JSON_CONTAINS(%s, %s)", array, column), vals...), nil
+		case arrayOverlaps:
+			return squirrel.Expr(fmt.Sprintf("JSON_OVERLAPS(%s, %s)", column, array), vals...), nil
+		default:
+			return squirrel.Expr(fmt.Sprintf("%s %s %s", column, op, array), vals...), nil
+		}
+	default:
+		return nil, &UnsupportedOperatorError{Operator: string(op), Dialect: d}
+	}
+}
+
+// arrayLength returns the expression computing the number of elements of an array column.
+func arrayLength(d databases.Dialect, column string) (string, error) {
+	switch {
+	case isPostgres(d):
+		return fmt.Sprintf("array_length(%s, 1)", column), nil
+	case d == databases.DialectMySQL:
+		return fmt.Sprintf("JSON_LENGTH(%s)", column), nil
+	default:
+		return "", &UnsupportedOperatorError{Operator: "array length", Dialect: d}
+	}
+}
+
+// buildFromSqlizer renders the result of BuildSquirrel for Build, which cannot report
+// errors: a filter that cannot be rendered for its dialect matches nothing (1=0).
+func buildFromSqlizer(sqlizer squirrel.Sqlizer, err error) (string, []any) {
+	if err != nil {
+		return "1=0", nil
+	}
+	if sqlizer == nil {
+		return "1=1", nil
+	}
+
+	condition, args, err := sqlizer.ToSql()
+	if err != nil {
+		return "1=0", nil
+	}
+	return condition, args
+}
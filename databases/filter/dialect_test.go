@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+func TestFilter_Dialect(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  databases.Dialect
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "postgres",
+			dialect:  databases.DialectPostgres,
+			wantSQL:  "((name ILIKE ?) AND (tags @> ARRAY[?, ?] AND array_length(tags, 1) > ?))",
+			wantArgs: []any{"%rama%", "go", "sql", 1},
+		},
+		{
+			name:     "mysql",
+			dialect:  databases.DialectMySQL,
+			wantSQL:  "((LOWER(name) LIKE LOWER(?)) AND (JSON_CONTAINS(tags, JSON_ARRAY(?, ?)) AND JSON_LENGTH(tags) > ?))",
+			wantArgs: []any{"%rama%", "go", "sql", 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := And(
+				NewStringFilter().Column("name").ILike("%rama%"),
+				NewArrayFilter[string]().Column("tags").Contains("go", "sql").LengthGt(1),
+			).Dialect(tt.dialect)
+
+			sql, args := group.Build()
+			if sql != tt.wantSQL {
+				t.Fatalf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFilter_UnsupportedDialect(t *testing.T) {
+	f := NewArrayFilter[int]().Column("ids").Overlaps(1, 2).Dialect("sqlite")
+
+	if _, err := f.BuildSquirrel(); !errors.Is(err, ErrUnsupportedOperator) {
+		t.Fatalf("BuildSquirrel() error = %v, want ErrUnsupportedOperator", err)
+	}
+	if cond, _ := f.Build(); cond != "1=0" {
+		t.Fatalf("Build() = %q, want 1=0", cond)
+	}
+}
@@ -26,6 +26,10 @@
 // NOT ILIKE       Case-insensitive not like              NotILike   WHERE email NOT ILIKE '%gmail%'
 // = ANY()         Equal any (Postgres array)              EqAny      WHERE 5 = ANY(numbers)
 // = ALL()         Equal all                               EqAll      WHERE score = ALL(scores)
+// @>              JSON contains                           Contains   WHERE metadata @> '{"plan":"pro"}'
+// ? / ?| / ?&     JSON key exists (any / all)             HasKey     WHERE metadata ? 'trial'
+// ->> / #>>       JSON path comparison                    PathEq     WHERE metadata->>'plan' = 'pro'
+// @@              Full-text search                        Search     WHERE to_tsvector(body) @@ websearch_to_tsquery('go')
 
 package filter
 
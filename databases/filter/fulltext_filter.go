@@ -0,0 +1,199 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// DefaultTextSearchConfig is the PostgreSQL text search configuration used when none is set.
+const DefaultTextSearchConfig = "simple"
+
+var textSearchConfigPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// FullTextFilter represents a fluent filter builder for full-text search.
+// Use NewFullTextFilter() to create an instance and chain methods to build filter conditions.
+//
+// On PostgreSQL it renders to_tsvector(config, doc) @@ websearch_to_tsquery(config, ?),
+// where doc concatenates the columns; set Vector to use a stored or generated tsvector
+// column instead. On MySQL it renders MATCH (columns) AGAINST (? IN NATURAL LANGUAGE MODE),
+// which requires a FULLTEXT index over exactly those columns.
+//
+// Example:
+//
+//	fts := filter.NewFullTextFilter().Columns("title", "body").Config("english").Search(q)
+//	sqlizer, err := fts.BuildSquirrel()
+//	query := squirrel.Select("*").From("articles").Where(sqlizer)
+//	query, err = fts.OrderByRank(query)
+type FullTextFilter struct {
+	columns     []string
+	vector      string
+	config      string
+	dialect     databases.Dialect
+	booleanMode bool
+
+	query *string
+}
+
+// NewFullTextFilter creates a new FullTextFilter.
+func NewFullTextFilter() *FullTextFilter {
+	return &FullTextFilter{}
+}
+
+// Columns sets the text columns searched.
+func (f *FullTextFilter) Columns(columns ...string) *FullTextFilter {
+	f.columns = columns
+	return f
+}
+
+// Vector sets a PostgreSQL tsvector column to search instead of computing
+// to_tsvector over Columns. Ignored on MySQL.
+func (f *FullTextFilter) Vector(column string) *FullTextFilter {
+	f.vector = column
+	return f
+}
+
+// Config sets the PostgreSQL text search configuration, e.g. "english".
+// It is rendered as a literal so expression indexes can match. Default: "simple".
+func (f *FullTextFilter) Config(config string) *FullTextFilter {
+	f.config = config
+	return f
+}
+
+// BooleanMode switches MySQL to IN BOOLEAN MODE. Ignored on PostgreSQL,
+// where websearch_to_tsquery already understands quotes, OR and -.
+func (f *FullTextFilter) BooleanMode() *FullTextFilter {
+	f.booleanMode = true
+	return f
+}
+
+// Dialect sets the SQL dialect the filter is rendered for. Default: PostgreSQL.
+func (f *FullTextFilter) Dialect(d databases.Dialect) *FullTextFilter {
+	f.dialect = d
+	return f
+}
+
+func (f *FullTextFilter) withDialect(d databases.Dialect) Filter {
+	c := *f
+	c.dialect = d
+	return &c
+}
+
+// Search sets the search query. A blank query sets no condition.
+func (f *FullTextFilter) Search(query string) *FullTextFilter {
+	if strings.TrimSpace(query) == "" {
+		f.query = nil
+		return f
+	}
+	f.query = &query
+	return f
+}
+
+// Build returns the SQL condition string and arguments for use with prepared statements.
+// Returns "1=1" if no conditions are set, and "1=0" if the filter cannot be rendered
+// for its dialect (use BuildSquirrel to get the error).
+func (f *FullTextFilter) Build() (condition string, args []any) {
+	return buildFromSqlizer(f.BuildSquirrel())
+}
+
+// BuildSquirrel returns a squirrel.Sqlizer for use with the squirrel query builder.
+// nil is returned if no search query is set.
+func (f *FullTextFilter) BuildSquirrel() (squirrel.Sqlizer, error) {
+	if f.query == nil {
+		return nil, nil
+	}
+
+	switch {
+	case isPostgres(f.dialect):
+		doc, tsquery, err := f.pgExprs()
+		if err != nil {
+			return nil, err
+		}
+		return squirrel.Expr(fmt.Sprintf("%s @@ %s", doc, tsquery), *f.query), nil
+	case f.dialect == databases.DialectMySQL:
+		return f.mysqlMatch()
+	default:
+		return nil, &UnsupportedOperatorError{Operator: "full-text search", Dialect: f.dialect}
+	}
+}
+
+// Rank returns the relevance expression of the search for use in Select or OrderBy
+// (ts_rank on PostgreSQL, MATCH ... AGAINST on MySQL). Higher is more relevant.
+// nil is returned if no search query is set.
+func (f *FullTextFilter) Rank() (squirrel.Sqlizer, error) {
+	if f.query == nil {
+		return nil, nil
+	}
+
+	switch {
+	case isPostgres(f.dialect):
+		doc, tsquery, err := f.pgExprs()
+		if err != nil {
+			return nil, err
+		}
+		return squirrel.Expr(fmt.Sprintf("ts_rank(%s, %s)", doc, tsquery), *f.query), nil
+	case f.dialect == databases.DialectMySQL:
+		return f.mysqlMatch()
+	default:
+		return nil, &UnsupportedOperatorError{Operator: "full-text rank", Dialect: f.dialect}
+	}
+}
+
+// OrderByRank appends the rank of the search, most relevant first, to the ORDER BY
+// clause of query. query is returned unchanged if no search query is set.
+func (f *FullTextFilter) OrderByRank(query squirrel.SelectBuilder) (squirrel.SelectBuilder, error) {
+	rank, err := f.Rank()
+	if err != nil || rank == nil {
+		return query, err
+	}
+
+	sql, args, err := rank.ToSql()
+	if err != nil {
+		return query, err
+	}
+	return query.OrderByClause(sql+" DESC", args...), nil
+}
+
+// pgExprs returns the tsvector document and the tsquery expressions.
+func (f *FullTextFilter) pgExprs() (doc, tsquery string, err error) {
+	config := f.config
+	if config == "" {
+		config = DefaultTextSearchConfig
+	}
+	if !textSearchConfigPattern.MatchString(config) {
+		return "", "", fmt.Errorf("filter: invalid text search config %q", config)
+	}
+	config = sqlLiteral(config)
+
+	switch {
+	case f.vector != "":
+		doc = f.vector
+	case len(f.columns) == 1:
+		doc = fmt.Sprintf("to_tsvector(%s, %s)", config, f.columns[0])
+	case len(f.columns) > 1:
+		parts := make([]string, 0, len(f.columns))
+		for _, column := range f.columns {
+			parts = append(parts, fmt.Sprintf("coalesce(%s, '')", column))
+		}
+		doc = fmt.Sprintf("to_tsvector(%s, %s)", config, strings.Join(parts, " || ' ' || "))
+	default:
+		return "", "", fmt.Errorf("filter: full-text search requires Columns or Vector")
+	}
+
+	return doc, fmt.Sprintf("websearch_to_tsquery(%s, ?)", config), nil
+}
+
+// mysqlMatch returns the MATCH ... AGAINST expression, used both as condition and rank.
+func (f *FullTextFilter) mysqlMatch() (squirrel.Sqlizer, error) {
+	if len(f.columns) == 0 {
+		return nil, fmt.Errorf("filter: full-text search requires Columns")
+	}
+	mode := "IN NATURAL LANGUAGE MODE"
+	if f.booleanMode {
+		mode = "IN BOOLEAN MODE"
+	}
+	return squirrel.Expr(fmt.Sprintf("MATCH (%s) AGAINST (? %s)", strings.Join(f.columns, ", "), mode), *f.query), nil
+}
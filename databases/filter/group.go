@@ -0,0 +1,269 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// Filter is implemented by every filter builder in this package (StringFilter,
+// DateFilter, ArrayFilter[T], ...) and by Group, so groups can be nested.
+type Filter interface {
+	BuildSquirrel() (squirrel.Sqlizer, error)
+}
+
+type groupOp string
+
+const (
+	groupAnd groupOp = "AND"
+	groupOr  groupOp = "OR"
+	groupNot groupOp = "NOT"
+)
+
+// Group combines filters and nested groups with AND, OR or NOT.
+// Use And(), Or() or Not() to create an instance.
+//
+// Filters without conditions (and nil filters) are skipped, so optional request
+// parameters can be added unconditionally. A group whose children are all empty
+// is itself empty.
+//
+// Example:
+//
+//	// (status = 'a' OR priority > 3) AND NOT archived
+//	group := filter.And(
+//	    filter.Or(
+//	        filter.NewStringFilter().Column("status").Eq("a"),
+//	        filter.NewIntegerFilter[int]().Column("priority").Gt(3),
+//	    ),
+//	    filter.Not(filter.NewBooleanFilter().Column("archived").Eq(true)),
+//	)
+//	sqlizer, err := group.BuildSquirrel()
+//	query := squirrel.Select("*").From("tasks").Where(sqlizer)
+type Group struct {
+	op      groupOp
+	filters []Filter
+	dialect databases.Dialect
+}
+
+// And creates a group matching rows that satisfy all filters.
+func And(filters ...Filter) *Group {
+	return &Group{op: groupAnd, filters: filters}
+}
+
+// Or creates a group matching rows that satisfy at least one of filters.
+func Or(filters ...Filter) *Group {
+	return &Group{op: groupOr, filters: filters}
+}
+
+// Not creates a group matching rows that do not satisfy f.
+func Not(f Filter) *Group {
+	return &Group{op: groupNot, filters: []Filter{f}}
+}
+
+// Add appends filters to the group.
+// For a Not group the filters are combined with AND before negation.
+func (g *Group) Add(filters ...Filter) *Group {
+	g.filters = append(g.filters, filters...)
+	return g
+}
+
+// Dialect sets the SQL dialect every dialect-aware filter in the group, including
+// nested groups, is rendered for. The filters themselves are left unchanged, so they
+// can be shared between groups. Default: each filter's own dialect (PostgreSQL).
+func (g *Group) Dialect(d databases.Dialect) *Group {
+	g.dialect = d
+	return g
+}
+
+func (g *Group) withDialect(d databases.Dialect) Filter {
+	c := *g
+	c.dialect = d
+	return &c
+}
+
+// BuildSquirrel returns a single parameterized squirrel.Sqlizer for the group.
+// Returns nil if the group has no conditions (caller can skip Where).
+func (g *Group) BuildSquirrel() (squirrel.Sqlizer, error) {
+	parts, err := g.parts()
+	if err != nil || len(parts) == 0 {
+		return nil, err
+	}
+
+	switch g.op {
+	case groupOr:
+		return squirrel.Or(parts), nil
+	case groupNot:
+		if len(parts) == 1 {
+			return notExpr{parts[0]}, nil
+		}
+		return notExpr{squirrel.And(parts)}, nil
+	default:
+		return squirrel.And(parts), nil
+	}
+}
+
+// Build returns the SQL condition string and arguments for use with prepared statements.
+// Returns "1=1" if the group has no conditions (always true condition).
+func (g *Group) Build() (condition string, args []any) {
+	sqlizer, err := g.BuildSquirrel()
+	if err != nil || sqlizer == nil {
+		return "1=1", nil
+	}
+
+	condition, args, err = sqlizer.ToSql()
+	if err != nil {
+		return "1=1", nil
+	}
+	return condition, args
+}
+
+// Canonical returns a deterministic string representation of the group with
+// arguments inlined, suitable as a cache key. Children of AND/OR groups are
+// sorted, so equivalent groups built in a different order share the same key.
+// Returns an empty string if the group has no conditions.
+//
+// The result is not safe to execute as SQL; use BuildSquirrel for queries.
+func (g *Group) Canonical() (string, error) {
+	keys := make([]string, 0, len(g.filters))
+	for _, f := range g.filters {
+		if isNilFilter(f) {
+			continue
+		}
+		if df, ok := f.(dialectFilter); ok && g.dialect != "" {
+			f = df.withDialect(g.dialect)
+		}
+
+		var (
+			key string
+			err error
+		)
+		if child, ok := f.(*Group); ok {
+			key, err = child.Canonical()
+		} else {
+			key, err = canonicalFilter(f)
+		}
+		if err != nil {
+			return "", err
+		}
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return "", nil
+	}
+	if g.op == groupNot {
+		return "NOT(" + strings.Join(keys, " AND ") + ")", nil
+	}
+	slices.Sort(keys)
+	return string(g.op) + "(" + strings.Join(keys, ", ") + ")", nil
+}
+
+// String returns the canonical representation of the group, see Canonical.
+func (g *Group) String() string {
+	key, err := g.Canonical()
+	if err != nil {
+		return "!ERR(" + err.Error() + ")"
+	}
+	return key
+}
+
+// parts builds the non-empty children of the group.
+func (g *Group) parts() ([]squirrel.Sqlizer, error) {
+	parts := make([]squirrel.Sqlizer, 0, len(g.filters))
+	for _, f := range g.filters {
+		if isNilFilter(f) {
+			continue
+		}
+		if df, ok := f.(dialectFilter); ok && g.dialect != "" {
+			f = df.withDialect(g.dialect)
+		}
+		sqlizer, err := f.BuildSquirrel()
+		if err != nil {
+			return nil, err
+		}
+		if sqlizer != nil {
+			parts = append(parts, sqlizer)
+		}
+	}
+	return parts, nil
+}
+
+// notExpr negates a squirrel.Sqlizer.
+type notExpr struct {
+	pred squirrel.Sqlizer
+}
+
+func (n notExpr) ToSql() (string, []any, error) {
+	sql, args, err := n.pred.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return "NOT (" + sql + ")", args, nil
+}
+
+// isNilFilter reports whether f is nil or a typed nil pointer such as (*StringFilter)(nil).
+func isNilFilter(f Filter) bool {
+	if f == nil {
+		return true
+	}
+	v := reflect.ValueOf(f)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// canonicalFilter renders a single filter with its arguments inlined in place of
+// the ? placeholders. The escaped ?? (jsonb ??, ??| and ??&) is kept as written.
+func canonicalFilter(f Filter) (string, error) {
+	sqlizer, err := f.BuildSquirrel()
+	if err != nil || sqlizer == nil {
+		return "", err
+	}
+	sql, args, err := sqlizer.ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case sql[i] != '?':
+			b.WriteByte(sql[i])
+		case i+1 < len(sql) && sql[i+1] == '?':
+			b.WriteString("??")
+			i++
+		case len(args) == 0:
+			b.WriteByte('?')
+		default:
+			b.WriteString(canonicalValue(args[0]))
+			args = args[1:]
+		}
+	}
+	if len(args) > 0 {
+		return "", fmt.Errorf("filter: more arguments than placeholders in %q", sql)
+	}
+	return b.String(), nil
+}
+
+func canonicalValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return strconv.Quote(val)
+	case []byte:
+		return strconv.Quote(string(val))
+	case time.Time:
+		return strconv.Quote(val.UTC().Format(time.RFC3339Nano))
+	case fmt.Stringer:
+		return strconv.Quote(val.String())
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,106 @@
+package filter
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+func TestGroup_BuildSquirrel(t *testing.T) {
+	var unset *StringFilter
+	group := And(
+		Or(
+			NewStringFilter().Column("status").Eq("a"),
+			NewIntegerFilter[int]().Column("priority").Gt(3),
+		),
+		Not(NewBooleanFilter().Column("archived").Eq(true)),
+		NewStringFilter().Column("name"),
+		unset,
+		Or(),
+	)
+
+	sqlizer, err := group.BuildSquirrel()
+	if err != nil {
+		t.Fatalf("BuildSquirrel() error = %v", err)
+	}
+	sql, args, err := sqlizer.ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+
+	wantSQL := "(((status = ?) OR (priority > ?)) AND NOT ((archived = ?)))"
+	if sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", sql, wantSQL)
+	}
+	if want := []any{"a", 3, true}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+}
+
+func TestGroup_Empty(t *testing.T) {
+	group := And(NewStringFilter().Column("name"), Or(Not(NewDateFilter().Column("created_at"))))
+
+	sqlizer, err := group.BuildSquirrel()
+	if err != nil || sqlizer != nil {
+		t.Fatalf("BuildSquirrel() = %v, %v; want nil, nil", sqlizer, err)
+	}
+	if cond, args := group.Build(); cond != "1=1" || args != nil {
+		t.Fatalf("Build() = %q, %v; want 1=1, nil", cond, args)
+	}
+	if key := group.String(); key != "" {
+		t.Fatalf("String() = %q, want empty", key)
+	}
+}
+
+func TestGroup_Canonical(t *testing.T) {
+	a := And(
+		NewStringFilter().Column("status").Eq("a"),
+		Not(NewIntegerFilter[int]().Column("priority").Gt(3)),
+	)
+	b := And(
+		Not(NewIntegerFilter[int]().Column("priority").Gt(3)),
+		NewStringFilter().Column("status").Eq("a"),
+	)
+
+	want := `AND((status = "a"), NOT((priority > 3)))`
+	if got := a.String(); got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+	if a.String() != b.String() {
+		t.Fatalf("String() differs for equivalent groups: %q vs %q", a.String(), b.String())
+	}
+	if Or(NewStringFilter().Column("status").Eq("a")).String() == And(NewStringFilter().Column("status").Eq("a")).String() {
+		t.Fatalf("String() must differ between AND and OR groups")
+	}
+
+	want = `AND(doc ?? "x")`
+	if got := And(NewJSONFilter().Column("doc").HasKey("x")).String(); got != want {
+		t.Fatalf("String() of a JSON key filter = %q, want %q", got, want)
+	}
+}
+
+func TestGroup_DialectLeavesChildrenUnchanged(t *testing.T) {
+	name := NewStringFilter().Column("name").ILike("%rama%")
+	pg := And(name).Dialect(databases.DialectPostgres)
+	my := And(Or(name)).Dialect(databases.DialectMySQL)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() { defer wg.Done(); _, _ = pg.BuildSquirrel() }()
+		go func() { defer wg.Done(); _ = my.String() }()
+	}
+	wg.Wait()
+
+	if sql, _ := my.Build(); sql != "(((LOWER(name) LIKE LOWER(?))))" {
+		t.Fatalf("mysql sql = %q", sql)
+	}
+	if sql, _ := pg.Build(); sql != "((name ILIKE ?))" {
+		t.Fatalf("postgres sql = %q", sql)
+	}
+	if sql, _ := name.Build(); sql != "name ILIKE ?" {
+		t.Fatalf("child sql after building groups = %q, want its own PostgreSQL rendering", sql)
+	}
+}
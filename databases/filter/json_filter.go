@@ -0,0 +1,378 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// JSONFilter represents a fluent filter builder for JSON columns (jsonb on PostgreSQL,
+// JSON on MySQL), typically backed by databases.JSON[T].
+// Use NewJSONFilter() to create an instance and chain methods to build filter conditions.
+//
+// Path keys are embedded in the SQL as quoted literals, so they must come from code
+// (or an allowlist), never from user input. Values are always parameterized.
+//
+// On PostgreSQL the key existence operators are rendered as ??, ??| and ??&, which
+// squirrel.Dollar and databases.QuestionToDollar turn into ?, ?| and ?&.
+//
+// Example:
+//
+//	f := filter.NewJSONFilter().Column("metadata").
+//	    Contains(map[string]any{"plan": "pro"}).
+//	    HasKey("trial_ends_at").
+//	    PathGte([]string{"limits", "seats"}, 10)
+//	sqlizer, err := f.BuildSquirrel()
+type JSONFilter struct {
+	column  string
+	dialect databases.Dialect
+
+	// Null checks
+	isNull    bool
+	isNotNull bool
+
+	// Containment
+	contains []any
+
+	// Key existence
+	hasKeys    []string
+	hasAnyKeys [][]string
+	hasAllKeys [][]string
+
+	// Comparisons on extracted paths, in the order they were added
+	paths []jsonPathCond
+}
+
+type jsonPathCond struct {
+	path   []string
+	op     string
+	values []any
+}
+
+// NewJSONFilter creates a new JSONFilter.
+func NewJSONFilter() *JSONFilter {
+	return &JSONFilter{}
+}
+
+// Column sets the JSON column name.
+func (f *JSONFilter) Column(column string) *JSONFilter {
+	f.column = column
+	return f
+}
+
+// Dialect sets the SQL dialect the filter is rendered for. Default: PostgreSQL.
+func (f *JSONFilter) Dialect(d databases.Dialect) *JSONFilter {
+	f.dialect = d
+	return f
+}
+
+func (f *JSONFilter) withDialect(d databases.Dialect) Filter {
+	c := *f
+	c.dialect = d
+	return &c
+}
+
+// IsNull sets a condition to check for SQL NULL (column IS NULL).
+func (f *JSONFilter) IsNull() *JSONFilter {
+	f.isNull = true
+	return f
+}
+
+// IsNotNull sets a condition to check for non-NULL values (column IS NOT NULL).
+func (f *JSONFilter) IsNotNull() *JSONFilter {
+	f.isNotNull = true
+	return f
+}
+
+// Contains sets a containment condition: the column contains value, which is
+// marshalled to JSON (column @> ?::jsonb, JSON_CONTAINS on MySQL).
+func (f *JSONFilter) Contains(value any) *JSONFilter {
+	f.contains = append(f.contains, value)
+	return f
+}
+
+// HasKey sets a condition that the top-level key exists (column ? key).
+func (f *JSONFilter) HasKey(key string) *JSONFilter {
+	f.hasKeys = append(f.hasKeys, key)
+	return f
+}
+
+// HasAnyKey sets a condition that at least one of the top-level keys exists (column ?| keys).
+func (f *JSONFilter) HasAnyKey(keys ...string) *JSONFilter {
+	if len(keys) > 0 {
+		f.hasAnyKeys = append(f.hasAnyKeys, keys)
+	}
+	return f
+}
+
+// HasAllKeys sets a condition that all of the top-level keys exist (column ?& keys).
+func (f *JSONFilter) HasAllKeys(keys ...string) *JSONFilter {
+	if len(keys) > 0 {
+		f.hasAllKeys = append(f.hasAllKeys, keys)
+	}
+	return f
+}
+
+// PathEq sets an equality condition on the value at path.
+// The extracted value is compared with the type of value: numbers, booleans and
+// time.Time are cast accordingly, anything else is compared as text.
+func (f *JSONFilter) PathEq(path []string, value any) *JSONFilter {
+	return f.addPath(path, "=", value)
+}
+
+// PathNeq sets a not-equal condition on the value at path.
+func (f *JSONFilter) PathNeq(path []string, value any) *JSONFilter {
+	return f.addPath(path, "<>", value)
+}
+
+// PathGt sets a greater-than condition on the value at path.
+func (f *JSONFilter) PathGt(path []string, value any) *JSONFilter {
+	return f.addPath(path, ">", value)
+}
+
+// PathGte sets a greater-than-or-equal condition on the value at path.
+func (f *JSONFilter) PathGte(path []string, value any) *JSONFilter {
+	return f.addPath(path, ">=", value)
+}
+
+// PathLt sets a less-than condition on the value at path.
+func (f *JSONFilter) PathLt(path []string, value any) *JSONFilter {
+	return f.addPath(path, "<", value)
+}
+
+// PathLte sets a less-than-or-equal condition on the value at path.
+func (f *JSONFilter) PathLte(path []string, value any) *JSONFilter {
+	return f.addPath(path, "<=", value)
+}
+
+// PathIn sets an IN condition on the value at path; the first value determines the cast.
+func (f *JSONFilter) PathIn(path []string, values ...any) *JSONFilter {
+	if len(values) == 0 {
+		return f
+	}
+	return f.addPath(path, "IN", values...)
+}
+
+// PathLike sets a LIKE pattern match condition on the text value at path.
+func (f *JSONFilter) PathLike(path []string, pattern string) *JSONFilter {
+	return f.addPath(path, "LIKE", pattern)
+}
+
+// PathIsNull sets a condition that path is missing or holds JSON null.
+func (f *JSONFilter) PathIsNull(path []string) *JSONFilter {
+	return f.addPath(path, "IS NULL")
+}
+
+// PathIsNotNull sets a condition that path exists and does not hold JSON null.
+func (f *JSONFilter) PathIsNotNull(path []string) *JSONFilter {
+	return f.addPath(path, "IS NOT NULL")
+}
+
+func (f *JSONFilter) addPath(path []string, op string, values ...any) *JSONFilter {
+	if len(path) > 0 {
+		f.paths = append(f.paths, jsonPathCond{path: path, op: op, values: values})
+	}
+	return f
+}
+
+// Extract returns the expression selecting the JSON value at path
+// (column->'a'->'b' on PostgreSQL, JSON_EXTRACT on MySQL), for use in Select or OrderBy.
+//
+// Example:
+//
+//	f := filter.NewJSONFilter().Column("metadata")
+//	query := squirrel.Select("id", f.Extract("address")+" AS address").From("users")
+func (f *JSONFilter) Extract(path ...string) string {
+	if len(path) == 0 {
+		return f.column
+	}
+	if f.dialect == databases.DialectMySQL {
+		return fmt.Sprintf("JSON_EXTRACT(%s, %s)", f.column, mysqlJSONPath(path))
+	}
+
+	var b strings.Builder
+	b.WriteString(f.column)
+	for _, key := range path {
+		b.WriteString("->")
+		b.WriteString(sqlLiteral(key))
+	}
+	return b.String()
+}
+
+// ExtractText returns the expression selecting the value at path as text
+// (column->>'a' or column#>>ARRAY['a','b'] on PostgreSQL, JSON_UNQUOTE(JSON_EXTRACT(...))
+// on MySQL), for use in Select or OrderBy.
+func (f *JSONFilter) ExtractText(path ...string) string {
+	if len(path) == 0 {
+		return f.column
+	}
+	if f.dialect == databases.DialectMySQL {
+		return fmt.Sprintf("JSON_UNQUOTE(%s)", f.Extract(path...))
+	}
+	if len(path) == 1 {
+		return fmt.Sprintf("%s->>%s", f.column, sqlLiteral(path[0]))
+	}
+	return fmt.Sprintf("%s#>>%s", f.column, sqlArray(path))
+}
+
+// Build returns the SQL condition string and arguments for use with prepared statements.
+// Returns "1=1" if no conditions are set, and "1=0" if the filter cannot be rendered
+// for its dialect (use BuildSquirrel to get the error).
+func (f *JSONFilter) Build() (condition string, args []any) {
+	return buildFromSqlizer(f.BuildSquirrel())
+}
+
+// BuildSquirrel returns a squirrel.Sqlizer for use with the squirrel query builder.
+// Multiple conditions are combined with AND; nil is returned if no conditions are set.
+// An *UnsupportedOperatorError is returned for dialects other than PostgreSQL and MySQL.
+func (f *JSONFilter) BuildSquirrel() (squirrel.Sqlizer, error) {
+	if !isPostgres(f.dialect) && f.dialect != databases.DialectMySQL {
+		return nil, &UnsupportedOperatorError{Operator: "json", Dialect: f.dialect}
+	}
+	mysql := f.dialect == databases.DialectMySQL
+	where := make([]squirrel.Sqlizer, 0)
+
+	// IS NULL / IS NOT NULL
+	if f.isNull {
+		where = append(where, squirrel.Expr(f.column+" IS NULL"))
+	}
+	if f.isNotNull {
+		where = append(where, squirrel.Expr(f.column+" IS NOT NULL"))
+	}
+
+	// @> / JSON_CONTAINS
+	for _, v := range f.contains {
+		doc, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("filter: marshal json containment value: %w", err)
+		}
+		if mysql {
+			where = append(where, squirrel.Expr(fmt.Sprintf("JSON_CONTAINS(%s, ?)", f.column), string(doc)))
+		} else {
+			where = append(where, squirrel.Expr(f.column+" @> ?::jsonb", string(doc)))
+		}
+	}
+
+	// ? / ?| / ?& (JSON_CONTAINS_PATH on MySQL)
+	for _, key := range f.hasKeys {
+		where = append(where, f.keyExists("??", "one", []string{key}))
+	}
+	for _, keys := range f.hasAnyKeys {
+		where = append(where, f.keyExists("??|", "one", keys))
+	}
+	for _, keys := range f.hasAllKeys {
+		where = append(where, f.keyExists("??&", "all", keys))
+	}
+
+	// comparisons on extracted paths
+	for _, cond := range f.paths {
+		where = append(where, f.pathSqlizer(cond))
+	}
+
+	if len(where) == 0 {
+		return nil, nil
+	}
+	if len(where) == 1 {
+		return where[0], nil
+	}
+	return squirrel.And(where), nil
+}
+
+func (f *JSONFilter) keyExists(pgOp, mysqlMode string, keys []string) squirrel.Sqlizer {
+	args := make([]any, 0, len(keys))
+	if f.dialect == databases.DialectMySQL {
+		for _, key := range keys {
+			args = append(args, mysqlPath([]string{key}))
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		return squirrel.Expr(fmt.Sprintf("JSON_CONTAINS_PATH(%s, '%s', %s)", f.column, mysqlMode, placeholders), args...)
+	}
+
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	if len(keys) == 1 && pgOp == "??" {
+		return squirrel.Expr(fmt.Sprintf("%s ?? ?", f.column), args...)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+	return squirrel.Expr(fmt.Sprintf("%s %s ARRAY[%s]", f.column, pgOp, placeholders), args...)
+}
+
+func (f *JSONFilter) pathSqlizer(cond jsonPathCond) squirrel.Sqlizer {
+	if cond.op == "IS NULL" || cond.op == "IS NOT NULL" {
+		// JSON null and a missing key both extract to SQL NULL as text.
+		return squirrel.Expr(fmt.Sprintf("%s %s", f.ExtractText(cond.path...), cond.op))
+	}
+
+	expr := f.typedExtract(cond.path, cond.values[0])
+	if cond.op == "IN" {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(cond.values)), ", ")
+		return squirrel.Expr(fmt.Sprintf("%s IN (%s)", expr, placeholders), cond.values...)
+	}
+	return squirrel.Expr(fmt.Sprintf("%s %s ?", expr, cond.op), cond.values...)
+}
+
+// typedExtract returns the text extraction of path cast to the SQL type matching sample.
+func (f *JSONFilter) typedExtract(path []string, sample any) string {
+	text := f.ExtractText(path...)
+	mysql := f.dialect == databases.DialectMySQL
+
+	switch sample.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		if mysql {
+			return fmt.Sprintf("CAST(%s AS DECIMAL(65, 10))", text)
+		}
+		return fmt.Sprintf("(%s)::numeric", text)
+	case bool:
+		if mysql {
+			// JSON true/false unquote to 'true'/'false'; compare as 1/0.
+			return fmt.Sprintf("(%s = 'true')", text)
+		}
+		return fmt.Sprintf("(%s)::boolean", text)
+	case time.Time:
+		if mysql {
+			return fmt.Sprintf("CAST(%s AS DATETIME(6))", text)
+		}
+		return fmt.Sprintf("(%s)::timestamptz", text)
+	default:
+		if mysql {
+			return text
+		}
+		return "(" + text + ")"
+	}
+}
+
+// sqlLiteral quotes s as a standard SQL string literal.
+func sqlLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlArray renders keys as a PostgreSQL text array constructor.
+func sqlArray(keys []string) string {
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		quoted = append(quoted, sqlLiteral(key))
+	}
+	return "ARRAY[" + strings.Join(quoted, ", ") + "]"
+}
+
+// mysqlPath renders keys as a MySQL JSON path, e.g. $."a"."b".
+func mysqlPath(keys []string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, key := range keys {
+		b.WriteString(".")
+		b.WriteString(strconv.Quote(key))
+	}
+	return b.String()
+}
+
+// mysqlJSONPath renders keys as a quoted MySQL JSON path literal, e.g. '$."a"."b"'.
+func mysqlJSONPath(keys []string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(mysqlPath(keys)) + "'"
+}
@@ -0,0 +1,105 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+func TestJSONFilter_BuildSquirrel(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  databases.Dialect
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "postgres",
+			dialect: databases.DialectPostgres,
+			wantSQL: "(metadata @> ?::jsonb AND metadata ?? ? AND metadata ??| ARRAY[?, ?] AND " +
+				"(metadata#>>ARRAY['limits', 'seats'])::numeric >= ? AND (metadata->>'plan') IN (?, ?))",
+			wantArgs: []any{`{"plan":"pro"}`, "trial", "a", "b", 10, "pro", "team"},
+		},
+		{
+			name:    "mysql",
+			dialect: databases.DialectMySQL,
+			wantSQL: "(JSON_CONTAINS(metadata, ?) AND JSON_CONTAINS_PATH(metadata, 'one', ?) AND " +
+				"JSON_CONTAINS_PATH(metadata, 'one', ?, ?) AND " +
+				`CAST(JSON_UNQUOTE(JSON_EXTRACT(metadata, '$."limits"."seats"')) AS DECIMAL(65, 10)) >= ? AND ` +
+				`JSON_UNQUOTE(JSON_EXTRACT(metadata, '$."plan"')) IN (?, ?))`,
+			wantArgs: []any{`{"plan":"pro"}`, `$."trial"`, `$."a"`, `$."b"`, 10, "pro", "team"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewJSONFilter().Column("metadata").Dialect(tt.dialect).
+				Contains(map[string]any{"plan": "pro"}).
+				HasKey("trial").
+				HasAnyKey("a", "b").
+				PathGte([]string{"limits", "seats"}, 10).
+				PathIn([]string{"plan"}, "pro", "team")
+
+			sql, args := f.Build()
+			if sql != tt.wantSQL {
+				t.Fatalf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestJSONFilter_KeyExistsDollar(t *testing.T) {
+	f := NewJSONFilter().Column("metadata").HasKey("trial").PathEq([]string{"plan"}, "pro")
+	sqlizer, _ := f.BuildSquirrel()
+
+	sql, _, err := squirrel.Select("id").From("users").Where(sqlizer).PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	want := "SELECT id FROM users WHERE (metadata ? $1 AND (metadata->>'plan') = $2)"
+	if sql != want {
+		t.Fatalf("sql = %q, want %q", sql, want)
+	}
+}
+
+func TestFullTextFilter(t *testing.T) {
+	pg := NewFullTextFilter().Columns("title", "body").Config("english").Search("go -java")
+	where, err := pg.BuildSquirrel()
+	if err != nil {
+		t.Fatalf("BuildSquirrel() error = %v", err)
+	}
+	query, err := pg.OrderByRank(squirrel.Select("id").From("articles").Where(where))
+	if err != nil {
+		t.Fatalf("OrderByRank() error = %v", err)
+	}
+	sql, args, err := query.ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	doc := "to_tsvector('english', coalesce(title, '') || ' ' || coalesce(body, ''))"
+	wantSQL := "SELECT id FROM articles WHERE " + doc + " @@ websearch_to_tsquery('english', ?) " +
+		"ORDER BY ts_rank(" + doc + ", websearch_to_tsquery('english', ?)) DESC"
+	if sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", sql, wantSQL)
+	}
+	if want := []any{"go -java", "go -java"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+
+	my := NewFullTextFilter().Columns("title", "body").Dialect(databases.DialectMySQL).BooleanMode().Search("+go")
+	if sql, _ := my.Build(); sql != "MATCH (title, body) AGAINST (? IN BOOLEAN MODE)" {
+		t.Fatalf("mysql sql = %q", sql)
+	}
+
+	if _, err := NewFullTextFilter().Columns("title").Config("english'; --").Search("go").BuildSquirrel(); err == nil {
+		t.Fatalf("BuildSquirrel() with invalid config: want error")
+	}
+	if sql, args := NewFullTextFilter().Columns("title").Search("  ").Build(); sql != "1=1" || args != nil {
+		t.Fatalf("Build() blank search = %q, %v; want 1=1, nil", sql, args)
+	}
+}
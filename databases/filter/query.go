@@ -0,0 +1,430 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+	"github.com/google/uuid"
+)
+
+// FieldType is the value type of a filterable field declared in a Schema.
+type FieldType string
+
+const (
+	TypeString  FieldType = "string"
+	TypeInteger FieldType = "integer"
+	TypeFloat   FieldType = "float"
+	TypeBoolean FieldType = "boolean"
+	TypeDate    FieldType = "date"
+	TypeUUID    FieldType = "uuid"
+)
+
+// Operator is a filter operator as written in the query string, e.g. filter[age][gte]=18.
+type Operator string
+
+const (
+	OpEq         Operator = "eq"
+	OpNeq        Operator = "neq"
+	OpGt         Operator = "gt"
+	OpGte        Operator = "gte"
+	OpLt         Operator = "lt"
+	OpLte        Operator = "lte"
+	OpLike       Operator = "like"
+	OpNotLike    Operator = "not_like"
+	OpILike      Operator = "ilike"
+	OpNotILike   Operator = "not_ilike"
+	OpIn         Operator = "in"
+	OpNotIn      Operator = "not_in"
+	OpBetween    Operator = "between"
+	OpNotBetween Operator = "not_between"
+	OpIsNull     Operator = "is_null" // true => IS NULL, false => IS NOT NULL
+)
+
+// typeOperators lists the operators supported by each field type.
+var typeOperators = map[FieldType][]Operator{
+	TypeString:  {OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpLike, OpNotLike, OpILike, OpNotILike, OpIn, OpNotIn, OpBetween, OpNotBetween, OpIsNull},
+	TypeInteger: {OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpIn, OpNotIn, OpBetween, OpNotBetween, OpIsNull},
+	TypeFloat:   {OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpIn, OpNotIn, OpBetween, OpNotBetween, OpIsNull},
+	TypeDate:    {OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpIn, OpNotIn, OpBetween, OpNotBetween, OpIsNull},
+	TypeBoolean: {OpEq, OpNeq, OpIsNull},
+	TypeUUID:    {OpEq, OpNeq, OpIn, OpNotIn, OpIsNull},
+}
+
+// Field declares a filterable field of a Schema.
+type Field struct {
+	// Column is the vetted SQL column (or expression) the field maps to.
+	Column string
+
+	// Type is the value type; values are parsed and validated accordingly.
+	Type FieldType
+
+	// Operators allowed for the field. Default: every operator supported by Type.
+	Operators []Operator
+
+	// Layout is the time layout of TypeDate values.
+	// Default: "2006-01-02", falling back to time.RFC3339.
+	Layout string
+
+	// Values optionally restricts TypeString values to a fixed set (enums).
+	Values []string
+}
+
+// Schema maps public field names, as used in the query string, to columns.
+// Only declared fields and operators can be filtered on.
+//
+// Example:
+//
+//	schema := filter.Schema{
+//	    "status":     {Column: "t.status", Type: filter.TypeString, Values: []string{"open", "closed"}},
+//	    "created_at": {Column: "t.created_at", Type: filter.TypeDate},
+//	    "name":       {Column: "t.name", Type: filter.TypeString, Operators: []filter.Operator{filter.OpEq, filter.OpILike}},
+//	}
+//	group, err := schema.Parse(r.URL.Query())
+//	// ?filter[status][in]=open,closed&filter[created_at][between]=2024-01-01,2024-02-01&filter[name][ilike]=rama
+type Schema map[string]Field
+
+var filterKeyPattern = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// Parse builds an AND group from the filter[field][op]=value parameters of query;
+// filter[field]=value is a shorthand for the eq operator. List operators (in, not_in)
+// take comma separated values, between and not_between take exactly two; spaces
+// around list values are trimmed. Only list operators may be repeated.
+//
+// Other query parameters are ignored. Unknown fields, operators not allowed for a
+// field, repeated parameters and invalid values are all reported in a single apperror.BadRequest with
+// one violation per parameter.
+func (s Schema) Parse(query url.Values) (*Group, error) {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	group := And()
+	violations := make([]apperror.Violation, 0)
+	for _, key := range keys {
+		f, err := s.parseParam(key, query[key])
+		if err != nil {
+			violations = append(violations, apperror.Violation{Field: key, Message: err.Error()})
+			continue
+		}
+		group.Add(f)
+	}
+
+	if len(violations) > 0 {
+		return nil, apperror.BadRequest("invalid filter query parameters", apperror.WithViolations(violations...))
+	}
+	return group, nil
+}
+
+func (s Schema) parseParam(key string, values []string) (Filter, error) {
+	m := filterKeyPattern.FindStringSubmatch(key)
+	if m == nil {
+		return nil, fmt.Errorf("malformed filter parameter, expected filter[field][operator]")
+	}
+
+	name, op := m[1], Operator(m[2])
+	if op == "" {
+		op = OpEq
+	}
+
+	field, ok := s[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter field %q", name)
+	}
+	if !field.allows(op) {
+		return nil, fmt.Errorf("operator %q is not allowed for field %q", op, name)
+	}
+
+	if op == OpIn || op == OpNotIn {
+		return field.build(op, strings.Join(values, ","))
+	}
+	if len(values) > 1 {
+		return nil, fmt.Errorf("filter parameter must not be repeated")
+	}
+	raw := ""
+	if len(values) > 0 {
+		raw = values[0]
+	}
+	return field.build(op, raw)
+}
+
+func (f Field) allows(op Operator) bool {
+	if !slices.Contains(typeOperators[f.Type], op) {
+		return false
+	}
+	return len(f.Operators) == 0 || slices.Contains(f.Operators, op)
+}
+
+// build creates the typed filter of f for a single operator and its raw value.
+func (f Field) build(op Operator, raw string) (Filter, error) {
+	if op == OpIsNull {
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("value must be true or false")
+		}
+		return nullFilter(f, isNull), nil
+	}
+
+	var parts []string
+	switch op {
+	case OpIn, OpNotIn:
+		parts = splitList(raw)
+	case OpBetween, OpNotBetween:
+		parts = splitList(raw)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("operator %q requires two comma separated values", op)
+		}
+	default:
+		parts = []string{raw}
+	}
+
+	switch f.Type {
+	case TypeString:
+		for _, p := range parts {
+			if len(f.Values) > 0 && !slices.Contains(f.Values, p) {
+				return nil, fmt.Errorf("value %q must be one of %s", p, strings.Join(f.Values, ", "))
+			}
+		}
+		return applyString(NewStringFilter().Column(f.Column), op, parts), nil
+	case TypeInteger:
+		vals, err := parseValues(parts, func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) })
+		if err != nil {
+			return nil, err
+		}
+		return applyInteger(NewIntegerFilter[int64]().Column(f.Column), op, vals), nil
+	case TypeFloat:
+		vals, err := parseValues(parts, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+		if err != nil {
+			return nil, err
+		}
+		return applyFloat(NewFloatFilter[float64]().Column(f.Column), op, vals), nil
+	case TypeDate:
+		vals, err := parseValues(parts, f.parseTime)
+		if err != nil {
+			return nil, err
+		}
+		return applyDate(NewDateFilter().Column(f.Column), op, vals), nil
+	case TypeBoolean:
+		vals, err := parseValues(parts, strconv.ParseBool)
+		if err != nil {
+			return nil, err
+		}
+		b := NewBooleanFilter().Column(f.Column)
+		if op == OpNeq {
+			return b.Neq(vals[0]), nil
+		}
+		return b.Eq(vals[0]), nil
+	case TypeUUID:
+		vals, err := parseValues(parts, uuid.Parse)
+		if err != nil {
+			return nil, err
+		}
+		return applyUUID(NewUUIDFilter().Column(f.Column), op, vals), nil
+	default:
+		return nil, fmt.Errorf("unsupported field type %q", f.Type)
+	}
+}
+
+func (f Field) parseTime(s string) (time.Time, error) {
+	if f.Layout != "" {
+		return time.Parse(f.Layout, s)
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// splitList splits the comma separated values of a list operator, trimming the
+// spaces around each value.
+func splitList(raw string) []string {
+	parts := strings.Split(raw, ",")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts
+}
+
+func parseValues[T any](parts []string, parse func(string) (T, error)) ([]T, error) {
+	vals := make([]T, 0, len(parts))
+	for _, p := range parts {
+		v, err := parse(strings.TrimSpace(p))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", p)
+		}
+		vals = append(vals, v)
+	}
+	return vals, nil
+}
+
+// nullFilter builds an IS NULL (isNull) or IS NOT NULL condition on the column of f.
+func nullFilter(f Field, isNull bool) Filter {
+	switch f.Type {
+	case TypeInteger:
+		b := NewIntegerFilter[int64]().Column(f.Column)
+		if isNull {
+			return b.IsNull()
+		}
+		return b.IsNotNull()
+	case TypeFloat:
+		b := NewFloatFilter[float64]().Column(f.Column)
+		if isNull {
+			return b.IsNull()
+		}
+		return b.IsNotNull()
+	case TypeDate:
+		b := NewDateFilter().Column(f.Column)
+		if isNull {
+			return b.IsNull()
+		}
+		return b.IsNotNull()
+	case TypeBoolean:
+		b := NewBooleanFilter().Column(f.Column)
+		if isNull {
+			return b.IsNull()
+		}
+		return b.IsNotNull()
+	case TypeUUID:
+		b := NewUUIDFilter().Column(f.Column)
+		if isNull {
+			return b.IsNull()
+		}
+		return b.IsNotNull()
+	default:
+		b := NewStringFilter().Column(f.Column)
+		if isNull {
+			return b.IsNull()
+		}
+		return b.IsNotNull()
+	}
+}
+
+func applyString(b *StringFilter, op Operator, vals []string) *StringFilter {
+	switch op {
+	case OpNeq:
+		return b.Neq(vals[0])
+	case OpGt:
+		return b.Gt(vals[0])
+	case OpGte:
+		return b.Gte(vals[0])
+	case OpLt:
+		return b.Lt(vals[0])
+	case OpLte:
+		return b.Lte(vals[0])
+	case OpLike:
+		return b.Like(vals[0])
+	case OpNotLike:
+		return b.NotLike(vals[0])
+	case OpILike:
+		return b.ILike(vals[0])
+	case OpNotILike:
+		return b.NotILike(vals[0])
+	case OpIn:
+		return b.In(vals...)
+	case OpNotIn:
+		return b.NotIn(vals...)
+	case OpBetween:
+		return b.Between(vals[0], vals[1])
+	case OpNotBetween:
+		return b.NotBetween(vals[0], vals[1])
+	default:
+		return b.Eq(vals[0])
+	}
+}
+
+func applyInteger(b *IntegerFilter[int64], op Operator, vals []int64) *IntegerFilter[int64] {
+	switch op {
+	case OpNeq:
+		return b.Neq(vals[0])
+	case OpGt:
+		return b.Gt(vals[0])
+	case OpGte:
+		return b.Gte(vals[0])
+	case OpLt:
+		return b.Lt(vals[0])
+	case OpLte:
+		return b.Lte(vals[0])
+	case OpIn:
+		return b.In(vals...)
+	case OpNotIn:
+		return b.NotIn(vals...)
+	case OpBetween:
+		return b.Between(vals[0], vals[1])
+	case OpNotBetween:
+		return b.NotBetween(vals[0], vals[1])
+	default:
+		return b.Eq(vals[0])
+	}
+}
+
+func applyFloat(b *FloatFilter[float64], op Operator, vals []float64) *FloatFilter[float64] {
+	switch op {
+	case OpNeq:
+		return b.Neq(vals[0])
+	case OpGt:
+		return b.Gt(vals[0])
+	case OpGte:
+		return b.Gte(vals[0])
+	case OpLt:
+		return b.Lt(vals[0])
+	case OpLte:
+		return b.Lte(vals[0])
+	case OpIn:
+		return b.In(vals...)
+	case OpNotIn:
+		return b.NotIn(vals...)
+	case OpBetween:
+		return b.Between(vals[0], vals[1])
+	case OpNotBetween:
+		return b.NotBetween(vals[0], vals[1])
+	default:
+		return b.Eq(vals[0])
+	}
+}
+
+func applyDate(b *DateFilter, op Operator, vals []time.Time) *DateFilter {
+	switch op {
+	case OpNeq:
+		return b.Neq(vals[0])
+	case OpGt:
+		return b.After(vals[0])
+	case OpGte:
+		return b.AfterOrEqual(vals[0])
+	case OpLt:
+		return b.Before(vals[0])
+	case OpLte:
+		return b.BeforeOrEqual(vals[0])
+	case OpIn:
+		return b.In(vals...)
+	case OpNotIn:
+		return b.NotIn(vals...)
+	case OpBetween:
+		return b.Between(vals[0], vals[1])
+	case OpNotBetween:
+		return b.NotBetween(vals[0], vals[1])
+	default:
+		return b.Eq(vals[0])
+	}
+}
+
+func applyUUID(b *UUIDFilter, op Operator, vals []uuid.UUID) *UUIDFilter {
+	switch op {
+	case OpNeq:
+		return b.Neq(vals[0])
+	case OpIn:
+		return b.In(vals...)
+	case OpNotIn:
+		return b.NotIn(vals...)
+	default:
+		return b.Eq(vals[0])
+	}
+}
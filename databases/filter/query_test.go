@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+)
+
+var testSchema = Schema{
+	"status":     {Column: "t.status", Type: TypeString, Values: []string{"a", "b", "c"}},
+	"created_at": {Column: "t.created_at", Type: TypeDate},
+	"name":       {Column: "t.name", Type: TypeString, Operators: []Operator{OpEq, OpILike}},
+	"priority":   {Column: "t.priority", Type: TypeInteger},
+}
+
+func TestSchema_Parse(t *testing.T) {
+	query, _ := url.ParseQuery("filter[status][in]=a,%20b&filter[created_at][between]=2024-01-01,2024-02-01" +
+		"&filter[name][ilike]=rama&filter[priority]=3&page=2")
+
+	group, err := testSchema.Parse(query)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	sql, args := group.Build()
+
+	wantSQL := "(((t.created_at >= ? AND t.created_at <= ?)) AND (t.name ILIKE ?) AND (t.priority = ?) AND (t.status IN (?,?)))"
+	if sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", sql, wantSQL)
+	}
+	wantArgs := []any{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		"rama", int64(3), "a", "b",
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestSchema_Parse_Violations(t *testing.T) {
+	query, _ := url.ParseQuery("filter[password]=x&filter[name][like]=rama&filter[status]=z" +
+		"&filter[priority][between]=1&filter[created_at][gt]=yesterday&filter[x" +
+		"&filter[priority][eq]=1&filter[priority][eq]=2")
+
+	_, err := testSchema.Parse(query)
+	if !apperror.IsBadRequest(err) {
+		t.Fatalf("Parse() error = %v, want bad request", err)
+	}
+
+	appErr, _ := apperror.As(err)
+	got := make([]string, 0, len(appErr.Violations))
+	for _, v := range appErr.Violations {
+		got = append(got, v.Field)
+	}
+	want := []string{
+		"filter[created_at][gt]",
+		"filter[name][like]",
+		"filter[password]",
+		"filter[priority][between]",
+		"filter[priority][eq]",
+		"filter[status]",
+		"filter[x",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("violations = %v, want %v", got, want)
+	}
+}
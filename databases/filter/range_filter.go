@@ -0,0 +1,219 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// RangeFilter represents a fluent filter builder for PostgreSQL range columns
+// (tstzrange, daterange, int4range, ...). Use NewRangeFilter[T]() to create an instance
+// and chain methods to build filter conditions.
+//
+// Range arguments are passed as databases.Range[T]. When the driver cannot infer the
+// parameter type (e.g. database/sql with text parameters), set RangeType so the
+// arguments are cast explicitly (?::tstzrange) and the elements of ContainsElement
+// to the matching element type (?::timestamptz), see ElementType.
+//
+// Example:
+//
+//	// bookings overlapping [start, end) in the same room
+//	f := filter.NewRangeFilter[time.Time]().
+//	    Column("during").
+//	    RangeType("tstzrange").
+//	    Overlaps(databases.NewRange(start, end, "[)"))
+//	sqlizer, err := f.BuildSquirrel()
+type RangeFilter[T databases.RangeElement] struct {
+	column      string
+	rangeType   string
+	elementType string
+	dialect     databases.Dialect
+
+	conds []rangeCond
+
+	// Null checks
+	isNull    bool
+	isNotNull bool
+
+	isEmpty    bool
+	isNotEmpty bool
+}
+
+type rangeCond struct {
+	op      string
+	value   any
+	element bool
+}
+
+// rangeElementTypes maps the built-in range types to their element types.
+var rangeElementTypes = map[string]string{
+	"int4range": "integer",
+	"int8range": "bigint",
+	"numrange":  "numeric",
+	"tsrange":   "timestamp",
+	"tstzrange": "timestamptz",
+	"daterange": "date",
+}
+
+// NewRangeFilter creates a new RangeFilter for ranges of T.
+func NewRangeFilter[T databases.RangeElement]() *RangeFilter[T] {
+	return &RangeFilter[T]{}
+}
+
+// Column sets the range column name.
+func (f *RangeFilter[T]) Column(column string) *RangeFilter[T] {
+	f.column = column
+	return f
+}
+
+// RangeType sets the PostgreSQL range type used to cast range arguments, e.g. "tstzrange".
+func (f *RangeFilter[T]) RangeType(rangeType string) *RangeFilter[T] {
+	f.rangeType = rangeType
+	return f
+}
+
+// ElementType sets the PostgreSQL type used to cast the element of ContainsElement,
+// e.g. "timestamptz". Default: the element type of a built-in RangeType.
+func (f *RangeFilter[T]) ElementType(elementType string) *RangeFilter[T] {
+	f.elementType = elementType
+	return f
+}
+
+// Dialect sets the SQL dialect the filter is rendered for. Default: PostgreSQL.
+// Range types only exist on PostgreSQL; other dialects return an *UnsupportedOperatorError.
+func (f *RangeFilter[T]) Dialect(d databases.Dialect) *RangeFilter[T] {
+	f.dialect = d
+	return f
+}
+
+func (f *RangeFilter[T]) withDialect(d databases.Dialect) Filter {
+	c := *f
+	c.dialect = d
+	return &c
+}
+
+// Overlaps sets an overlap condition (column && range): the ranges share at least one point.
+func (f *RangeFilter[T]) Overlaps(r databases.Range[T]) *RangeFilter[T] {
+	return f.add("&&", r, false)
+}
+
+// Contains sets a containment condition (column @> range).
+func (f *RangeFilter[T]) Contains(r databases.Range[T]) *RangeFilter[T] {
+	return f.add("@>", r, false)
+}
+
+// ContainsElement sets a condition that the range contains value (column @> value).
+func (f *RangeFilter[T]) ContainsElement(value T) *RangeFilter[T] {
+	return f.add("@>", value, true)
+}
+
+// ContainedBy sets a condition that the column lies within range (column <@ range).
+func (f *RangeFilter[T]) ContainedBy(r databases.Range[T]) *RangeFilter[T] {
+	return f.add("<@", r, false)
+}
+
+// Adjacent sets an adjacency condition (column -|- range): the ranges touch without overlapping.
+func (f *RangeFilter[T]) Adjacent(r databases.Range[T]) *RangeFilter[T] {
+	return f.add("-|-", r, false)
+}
+
+// StrictlyLeft sets a condition that the column lies entirely before range (column << range).
+func (f *RangeFilter[T]) StrictlyLeft(r databases.Range[T]) *RangeFilter[T] {
+	return f.add("<<", r, false)
+}
+
+// StrictlyRight sets a condition that the column lies entirely after range (column >> range).
+func (f *RangeFilter[T]) StrictlyRight(r databases.Range[T]) *RangeFilter[T] {
+	return f.add(">>", r, false)
+}
+
+// IsEmpty sets a condition that the range is empty (isempty(column)).
+func (f *RangeFilter[T]) IsEmpty() *RangeFilter[T] {
+	f.isEmpty = true
+	return f
+}
+
+// IsNotEmpty sets a condition that the range is not empty (NOT isempty(column)).
+func (f *RangeFilter[T]) IsNotEmpty() *RangeFilter[T] {
+	f.isNotEmpty = true
+	return f
+}
+
+// IsNull sets a condition to check for NULL values (column IS NULL).
+func (f *RangeFilter[T]) IsNull() *RangeFilter[T] {
+	f.isNull = true
+	return f
+}
+
+// IsNotNull sets a condition to check for non-NULL values (column IS NOT NULL).
+func (f *RangeFilter[T]) IsNotNull() *RangeFilter[T] {
+	f.isNotNull = true
+	return f
+}
+
+func (f *RangeFilter[T]) add(op string, value any, element bool) *RangeFilter[T] {
+	f.conds = append(f.conds, rangeCond{op: op, value: value, element: element})
+	return f
+}
+
+// Build returns the SQL condition string and arguments for use with prepared statements.
+// Returns "1=1" if no conditions are set, and "1=0" if the filter cannot be rendered
+// for its dialect (use BuildSquirrel to get the error).
+func (f *RangeFilter[T]) Build() (condition string, args []any) {
+	return buildFromSqlizer(f.BuildSquirrel())
+}
+
+// BuildSquirrel returns a squirrel.Sqlizer for use with the squirrel query builder.
+// Multiple conditions are combined with AND; nil is returned if no conditions are set.
+func (f *RangeFilter[T]) BuildSquirrel() (squirrel.Sqlizer, error) {
+	if !isPostgres(f.dialect) {
+		return nil, &UnsupportedOperatorError{Operator: "range", Dialect: f.dialect}
+	}
+	where := make([]squirrel.Sqlizer, 0)
+
+	// IS NULL / IS NOT NULL
+	if f.isNull {
+		where = append(where, squirrel.Expr(f.column+" IS NULL"))
+	}
+	if f.isNotNull {
+		where = append(where, squirrel.Expr(f.column+" IS NOT NULL"))
+	}
+
+	// isempty
+	if f.isEmpty {
+		where = append(where, squirrel.Expr(fmt.Sprintf("isempty(%s)", f.column)))
+	}
+	if f.isNotEmpty {
+		where = append(where, squirrel.Expr(fmt.Sprintf("NOT isempty(%s)", f.column)))
+	}
+
+	// && / @> / <@ / -|- / << / >>
+	for _, cond := range f.conds {
+		placeholder := "?"
+		if cast := f.castType(cond.element); cast != "" {
+			placeholder = "?::" + cast
+		}
+		where = append(where, squirrel.Expr(fmt.Sprintf("%s %s %s", f.column, cond.op, placeholder), cond.value))
+	}
+
+	if len(where) == 0 {
+		return nil, nil
+	}
+	if len(where) == 1 {
+		return where[0], nil
+	}
+	return squirrel.And(where), nil
+}
+
+// castType returns the type the argument of a condition is cast to: the range type for
+// ranges, the element type for elements, or "" to leave it uncast.
+func (f *RangeFilter[T]) castType(element bool) string {
+	if !element {
+		return f.rangeType
+	}
+	if f.elementType != "" {
+		return f.elementType
+	}
+	return rangeElementTypes[f.rangeType]
+}
@@ -0,0 +1,43 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+func TestRangeFilter_Build(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	slot := databases.NewRange(start, start.Add(time.Hour), "[)")
+
+	sql, args := NewRangeFilter[time.Time]().Column("during").RangeType("tstzrange").
+		Overlaps(slot).
+		ContainsElement(start).
+		IsNotEmpty().
+		Build()
+
+	wantSQL := "(NOT isempty(during) AND during && ?::tstzrange AND during @> ?::timestamptz)"
+	if sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", sql, wantSQL)
+	}
+	if want := []any{slot, start}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+
+	sql, _ = NewRangeFilter[int]().Column("seats").ContainsElement(3).Build()
+	if want := "seats @> ?"; sql != want {
+		t.Fatalf("sql without RangeType = %q, want %q", sql, want)
+	}
+	sql, _ = NewRangeFilter[int]().Column("seats").RangeType("seatrange").ElementType("smallint").ContainsElement(3).Build()
+	if want := "seats @> ?::smallint"; sql != want {
+		t.Fatalf("sql with ElementType = %q, want %q", sql, want)
+	}
+
+	_, err := NewRangeFilter[int]().Column("seats").Dialect(databases.DialectMySQL).Adjacent(databases.RangeFrom(1)).BuildSquirrel()
+	if !errors.Is(err, ErrUnsupportedOperator) {
+		t.Fatalf("BuildSquirrel() on mysql error = %v, want ErrUnsupportedOperator", err)
+	}
+}
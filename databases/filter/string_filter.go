@@ -4,12 +4,14 @@ import (
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 )
 
 // StringFilter represents a fluent filter builder for string columns in SQL queries.
 // Use New() to create an instance and chain methods to build filter conditions.
 type StringFilter struct {
-	column string
+	column  string
+	dialect databases.Dialect
 
 	// Equality operators
 	eqValue  *string
@@ -52,6 +54,19 @@ func (f *StringFilter) Column(column string) *StringFilter {
 	return f
 }
 
+// Dialect sets the SQL dialect the filter is rendered for. Default: PostgreSQL.
+// On MySQL, ILike/NotILike are emulated with LOWER(column) LIKE LOWER(?).
+func (f *StringFilter) Dialect(d databases.Dialect) *StringFilter {
+	f.dialect = d
+	return f
+}
+
+func (f *StringFilter) withDialect(d databases.Dialect) Filter {
+	c := *f
+	c.dialect = d
+	return &c
+}
+
 // Eq sets an equality condition (column = value).
 func (f *StringFilter) Eq(value string) *StringFilter {
 	f.eqValue = &value
@@ -76,13 +91,13 @@ func (f *StringFilter) NotLike(pattern string) *StringFilter {
 	return f
 }
 
-// ILike sets a case-insensitive LIKE pattern match (PostgreSQL).
+// ILike sets a case-insensitive LIKE pattern match (emulated on MySQL).
 func (f *StringFilter) ILike(pattern string) *StringFilter {
 	f.iLikeValue = &pattern
 	return f
 }
 
-// NotILike sets a case-insensitive NOT LIKE pattern match (PostgreSQL).
+// NotILike sets a case-insensitive NOT LIKE pattern match (emulated on MySQL).
 func (f *StringFilter) NotILike(pattern string) *StringFilter {
 	f.notILikeValue = &pattern
 	return f
@@ -153,8 +168,13 @@ func (f *StringFilter) NotBetween(start, end string) *StringFilter {
 }
 
 // Build returns the SQL condition string and arguments for use with prepared statements.
-// Returns empty string if no conditions are set.
+// Returns "1=1" if no conditions are set, and "1=0" if the filter cannot be rendered
+// for its dialect (use BuildSquirrel to get the error).
 func (f *StringFilter) Build() (condition string, args []any) {
+	if !isPostgres(f.dialect) {
+		return buildFromSqlizer(f.BuildSquirrel())
+	}
+
 	var conditions []string
 	args = []any{}
 
@@ -262,6 +282,9 @@ func (f *StringFilter) Build() (condition string, args []any) {
 	return condition, args
 }
 
+// BuildSquirrel returns a squirrel.Sqlizer for use with the squirrel query builder.
+// Multiple conditions are combined with AND; nil is returned if no conditions are set.
+// An *UnsupportedOperatorError is returned if an operator has no equivalent in the dialect.
 func (f *StringFilter) BuildSquirrel() (squirrel.Sqlizer, error) {
 	where := make([]squirrel.Sqlizer, 0)
 
@@ -289,12 +312,20 @@ func (f *StringFilter) BuildSquirrel() (squirrel.Sqlizer, error) {
 		where = append(where, squirrel.NotLike{f.column: *f.notLikeValue})
 	}
 
-	// ILIKE / NOT ILIKE (Postgres)
+	// ILIKE / NOT ILIKE (LOWER ... LIKE on MySQL)
 	if f.iLikeValue != nil {
-		where = append(where, squirrel.ILike{f.column: *f.iLikeValue})
+		pred, err := iLikeSqlizer(f.dialect, f.column, *f.iLikeValue, false)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, pred)
 	}
 	if f.notILikeValue != nil {
-		where = append(where, squirrel.NotILike{f.column: *f.notILikeValue})
+		pred, err := iLikeSqlizer(f.dialect, f.column, *f.notILikeValue, true)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, pred)
 	}
 
 	// IN / NOT IN
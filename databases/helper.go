@@ -1,21 +1,180 @@
 package databases
 
-import "fmt"
+import (
+	"strconv"
+	"strings"
+)
 
+// QuestionToDollar rewrites ? placeholders to PostgreSQL positional placeholders ($1, $2, ...).
+//
+// Question marks inside string literals ('...', E'...'), quoted identifiers ("..."),
+// dollar-quoted strings ($tag$...$tag$) and comments (-- and /* */) are left untouched.
+// An escaped "??" is written as a single literal "?", which allows the jsonb operators
+// ?, ?| and ?& (written ??, ??| and ??&), matching squirrel.Dollar.
 func QuestionToDollar(sql string) string {
 	var (
-		idx int = 1
-		out     = make([]rune, 0, len(sql))
+		idx = 1
+		out strings.Builder
 	)
+	out.Grow(len(sql) + 8)
 
-	for i := 0; i < len(sql); i++ {
-		if sql[i] == '?' {
-			out = append(out, []rune(fmt.Sprintf("$%d", idx))...)
-			idx++
+	for i := 0; i < len(sql); {
+		if end := skipLiteral(sql, i, DialectPostgres); end > i {
+			out.WriteString(sql[i:end])
+			i = end
+			continue
+		}
+
+		c := sql[i]
+		if c != '?' {
+			out.WriteByte(c)
+			i++
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == '?' {
+			out.WriteByte('?')
+			i += 2
+			continue
+		}
+		out.WriteByte('$')
+		out.WriteString(strconv.Itoa(idx))
+		idx++
+		i++
+	}
+
+	return out.String()
+}
+
+// splitStatements splits sql on the semicolons outside string literals, quoted
+// identifiers, dollar-quoted strings and comments, lexed with the rules of dialect d.
+// Those are blanked out of the returned statements, so keywords can be matched on the
+// SQL code alone; blank statements are dropped.
+func splitStatements(sql string, d Dialect) []string {
+	var (
+		stmts []string
+		cur   strings.Builder
+	)
+	flush := func() {
+		if stmt := cur.String(); strings.TrimSpace(stmt) != "" {
+			stmts = append(stmts, stmt)
+		}
+		cur.Reset()
+	}
+
+	for i := 0; i < len(sql); {
+		if end := skipLiteral(sql, i, d); end > i {
+			cur.WriteByte(' ')
+			i = end
+			continue
+		}
+		if sql[i] == ';' {
+			flush()
 		} else {
-			out = append(out, rune(sql[i]))
+			cur.WriteByte(sql[i])
 		}
+		i++
 	}
+	flush()
+	return stmts
+}
+
+// skipLiteral returns the index just past the string literal, quoted identifier or
+// comment starting at sql[i], or i if none starts there. Any other dialect than MySQL
+// is lexed as PostgreSQL.
+//
+// PostgreSQL: '...' (backslash escapes in E'...' only), "..." identifiers,
+// $tag$...$tag$ strings, -- and nested /* */ comments.
+// MySQL: '...' and "..." strings with backslash escapes, `...` identifiers,
+// -- and # comments, non-nested /* */ comments.
+func skipLiteral(sql string, i int, d Dialect) int {
+	mysql := d == DialectMySQL
+	c := sql[i]
+	switch {
+	case c == '\'':
+		escapes := mysql || (i > 0 && (sql[i-1] == 'E' || sql[i-1] == 'e') && (i == 1 || !isIdentByte(sql[i-2])))
+		return skipQuoted(sql, i, '\'', escapes)
+	case c == '"':
+		return skipQuoted(sql, i, '"', mysql)
+	case c == '`' && mysql:
+		return skipQuoted(sql, i, '`', false)
+	case c == '-' && i+1 < len(sql) && sql[i+1] == '-', c == '#' && mysql:
+		end := strings.IndexByte(sql[i:], '\n')
+		if end < 0 {
+			return len(sql)
+		}
+		return i + end
+	case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+		return skipBlockComment(sql, i, !mysql)
+	case c == '$' && !mysql && (i == 0 || !isIdentByte(sql[i-1])):
+		if end := skipDollarQuoted(sql, i); end > i+1 {
+			return end
+		}
+	}
+	return i
+}
+
+// skipQuoted returns the index just past the literal starting with quote at sql[start].
+// A doubled quote is an escaped quote; with escapes, backslash escapes the next byte.
+func skipQuoted(sql string, start int, quote byte, escapes bool) int {
+	for i := start + 1; i < len(sql); i++ {
+		switch sql[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(sql)
+}
+
+// skipBlockComment returns the index just past the comment starting at sql[start],
+// counting nested comments when nested is set.
+func skipBlockComment(sql string, start int, nested bool) int {
+	depth := 0
+	for i := start; i+1 < len(sql); i++ {
+		switch {
+		case sql[i] == '/' && sql[i+1] == '*' && (nested || depth == 0):
+			depth++
+			i++
+		case sql[i] == '*' && sql[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(sql)
+}
+
+// skipDollarQuoted returns the index just past the dollar-quoted string starting at sql[start],
+// or start+1 when sql[start] does not open one (e.g. an existing $1 placeholder).
+func skipDollarQuoted(sql string, start int) int {
+	end := start + 1
+	for end < len(sql) && sql[end] != '$' {
+		if !isIdentByte(sql[end]) || (end == start+1 && sql[end] >= '0' && sql[end] <= '9') {
+			return start + 1
+		}
+		end++
+	}
+	if end >= len(sql) {
+		return start + 1
+	}
+
+	tag := sql[start : end+1]
+	closing := strings.Index(sql[end+1:], tag)
+	if closing < 0 {
+		return len(sql)
+	}
+	return end + 1 + closing + len(tag)
+}
 
-	return string(out)
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
 }
@@ -0,0 +1,50 @@
+package databases
+
+import "testing"
+
+func TestQuestionToDollar(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "placeholders",
+			in:   "SELECT * FROM users WHERE id = ? AND status IN (?, ?)",
+			want: "SELECT * FROM users WHERE id = $1 AND status IN ($2, $3)",
+		},
+		{
+			name: "string literals",
+			in:   "SELECT 'what?', 'it''s ?', E'\\'?' FROM t WHERE a = ?",
+			want: "SELECT 'what?', 'it''s ?', E'\\'?' FROM t WHERE a = $1",
+		},
+		{
+			name: "quoted identifier",
+			in:   `SELECT "col?" FROM t WHERE "a""?" = ?`,
+			want: `SELECT "col?" FROM t WHERE "a""?" = $1`,
+		},
+		{
+			name: "dollar quoted",
+			in:   "SELECT $$ ? $$, $fn$ ?? $fn$ WHERE a = ?",
+			want: "SELECT $$ ? $$, $fn$ ?? $fn$ WHERE a = $1",
+		},
+		{
+			name: "comments",
+			in:   "SELECT a -- why?\nFROM t /* ? /* nested ? */ ? */ WHERE a = ?",
+			want: "SELECT a -- why?\nFROM t /* ? /* nested ? */ ? */ WHERE a = $1",
+		},
+		{
+			name: "escaped jsonb operators",
+			in:   "SELECT * FROM t WHERE data ?? ? AND tags ??| ?",
+			want: "SELECT * FROM t WHERE data ? $1 AND tags ?| $2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuestionToDollar(tt.in); got != tt.want {
+				t.Fatalf("QuestionToDollar() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
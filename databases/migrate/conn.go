@@ -0,0 +1,135 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// conn is a single dedicated database session. Session-level locks
+// (pg_advisory_lock, GET_LOCK) are only held by the session that took them,
+// so the whole migration run goes through one conn.
+type conn interface {
+	exec(ctx context.Context, query string, args ...any) error
+	query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error
+	begin(ctx context.Context) (txConn, error)
+	close() error
+}
+
+// txConn is a transaction started on a conn.
+type txConn interface {
+	exec(ctx context.Context, query string, args ...any) error
+	commit(ctx context.Context) error
+	rollback(ctx context.Context) error
+}
+
+// pgxConn adapts a connection acquired from a pgx pool.
+type pgxConn struct {
+	conn *pgxpool.Conn
+}
+
+func (c *pgxConn) exec(ctx context.Context, query string, args ...any) error {
+	_, err := c.conn.Exec(ctx, query, args...)
+	return err
+}
+
+func (c *pgxConn) query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error {
+	rows, err := c.conn.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = fn(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (c *pgxConn) begin(ctx context.Context) (txConn, error) {
+	tx, err := c.conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pgxTx{tx: tx}, nil
+}
+
+func (c *pgxConn) close() error {
+	c.conn.Release()
+	return nil
+}
+
+type pgxTx struct {
+	tx pgx.Tx
+}
+
+func (t *pgxTx) exec(ctx context.Context, query string, args ...any) error {
+	_, err := t.tx.Exec(ctx, query, args...)
+	return err
+}
+
+func (t *pgxTx) commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *pgxTx) rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
+
+// sqlConn adapts a connection reserved from a *sql.DB.
+type sqlConn struct {
+	conn *sql.Conn
+}
+
+func (c *sqlConn) exec(ctx context.Context, query string, args ...any) error {
+	_, err := c.conn.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (c *sqlConn) query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error {
+	rows, err := c.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = fn(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (c *sqlConn) begin(ctx context.Context) (txConn, error) {
+	tx, err := c.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{tx: tx}, nil
+}
+
+func (c *sqlConn) close() error {
+	return c.conn.Close()
+}
+
+type sqlTx struct {
+	tx *sql.Tx
+}
+
+func (t *sqlTx) exec(ctx context.Context, query string, args ...any) error {
+	_, err := t.tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (t *sqlTx) commit(context.Context) error {
+	return t.tx.Commit()
+}
+
+func (t *sqlTx) rollback(context.Context) error {
+	return t.tx.Rollback()
+}
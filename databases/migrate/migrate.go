@@ -0,0 +1,687 @@
+// Package migrate applies versioned SQL migrations embedded in the binary
+// (typically via embed.FS) to PostgreSQL or MySQL.
+//
+// Migration files are named "<version>_<name>.up.sql" and "<version>_<name>.down.sql",
+// e.g. "0001_create_users.up.sql". Migrations are applied in version order and recorded
+// in a migration table together with a checksum of the up script, so editing an applied
+// migration is detected.
+//
+// Concurrency: a run holds a database lock for its whole duration (pg_advisory_lock on
+// PostgreSQL, GET_LOCK on MySQL), so several instances starting at the same time apply
+// each migration exactly once.
+//
+// Transactions: on PostgreSQL each migration runs in its own transaction together with
+// its bookkeeping. Add the line "-- migrate:no-transaction" at the top of a file to opt
+// out (e.g. CREATE INDEX CONCURRENTLY). MySQL commits DDL implicitly, so migrations run
+// without a transaction; a failed migration leaves its version marked dirty and further
+// runs fail with ErrDirty until it is fixed manually.
+//
+// Multi-statement files require the MySQL DSN option multiStatements=true.
+//
+// Example:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	m, err := migrate.NewPgx(pool, migrations, migrate.WithDir("migrations"))
+//	if err != nil {
+//		return err
+//	}
+//	_, err = m.Up(ctx)
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/observability"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultTable       = "schema_migrations"
+	defaultLockTimeout = time.Minute
+)
+
+var (
+	// ErrDirty is returned when a previous run failed halfway through a migration
+	// that could not be rolled back. The database must be fixed manually and the
+	// dirty row removed from the migration table.
+	ErrDirty = errors.New("migrate: database is dirty")
+
+	// ErrChecksumMismatch is returned when an applied migration was edited afterwards.
+	ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+
+	// ErrMissingMigration is returned when an applied migration that must be rolled
+	// back is no longer present in the source.
+	ErrMissingMigration = errors.New("migrate: applied migration not found in source")
+
+	// ErrNoDownMigration is returned when rolling back a migration without a down script.
+	ErrNoDownMigration = errors.New("migrate: no down migration")
+
+	// ErrDuplicateVersion is returned when two migration files share a version.
+	ErrDuplicateVersion = errors.New("migrate: duplicate migration version")
+
+	// ErrLockTimeout is returned when the migration lock could not be acquired in time.
+	ErrLockTimeout = errors.New("migrate: timed out waiting for migration lock")
+
+	// ErrUnknownCommand is returned by Run for an unsupported command.
+	ErrUnknownCommand = errors.New("migrate: unknown command")
+)
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Direction is the direction a migration is applied in.
+type Direction string
+
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+// Result describes a migration applied (or, in dry-run mode, planned) by Up or Down.
+type Result struct {
+	Version   uint64
+	Name      string
+	Direction Direction
+	Duration  time.Duration
+	DryRun    bool
+}
+
+// Status describes the state of a single migration.
+type Status struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Dirty     bool // The migration failed halfway and must be fixed manually
+	Modified  bool // The up script changed after the migration was applied
+	Missing   bool // The migration is applied but no longer present in the source
+}
+
+type appliedMigration struct {
+	version   uint64
+	name      string
+	checksum  string
+	dirty     bool
+	appliedAt time.Time
+}
+
+// Migrator applies the migrations of a source filesystem to a database.
+type Migrator struct {
+	cfg         *config
+	dialect     databases.Dialect
+	placeholder squirrel.PlaceholderFormat
+	migrations  []*Migration
+	open        func(ctx context.Context) (conn, error)
+}
+
+// NewPgx creates a Migrator for PostgreSQL using a pgx pool, such as the one
+// returned by pgxx RDBMS.GetDB.
+func NewPgx(pool *pgxpool.Pool, source fs.FS, opts ...Option) (*Migrator, error) {
+	return newMigrator(databases.DialectPostgres, source, func(ctx context.Context) (conn, error) {
+		c, err := pool.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &pgxConn{conn: c}, nil
+	}, opts...)
+}
+
+// NewSQL creates a Migrator on top of a *sql.DB (e.g. the one given to sqlx.NewRDBMS)
+// for the given dialect.
+func NewSQL(db *sql.DB, dialect databases.Dialect, source fs.FS, opts ...Option) (*Migrator, error) {
+	return newMigrator(dialect, source, func(ctx context.Context) (conn, error) {
+		c, err := db.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &sqlConn{conn: c}, nil
+	}, opts...)
+}
+
+func newMigrator(
+	dialect databases.Dialect,
+	source fs.FS,
+	open func(ctx context.Context) (conn, error),
+	opts ...Option,
+) (*Migrator, error) {
+	cfg := defaultConfig()
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+
+	if !tableNamePattern.MatchString(cfg.table) {
+		return nil, fmt.Errorf("migrate: invalid table name %q", cfg.table)
+	}
+
+	var placeholder squirrel.PlaceholderFormat
+	switch dialect {
+	case databases.DialectPostgres:
+		placeholder = squirrel.Dollar
+	case databases.DialectMySQL:
+		placeholder = squirrel.Question
+	default:
+		return nil, fmt.Errorf("migrate: unsupported dialect %q", dialect)
+	}
+
+	migrations, err := loadMigrations(source, cfg.dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	return &Migrator{
+		cfg:         cfg,
+		dialect:     dialect,
+		placeholder: placeholder,
+		migrations:  migrations,
+		open:        open,
+	}, nil
+}
+
+// Migrations returns the migrations loaded from the source, sorted by version.
+func (m *Migrator) Migrations() []*Migration {
+	return m.migrations
+}
+
+// Up applies all pending migrations in version order.
+// It returns the migrations applied before an error occurred, if any.
+func (m *Migrator) Up(ctx context.Context) ([]Result, error) {
+	var results []Result
+	err := m.withLock(ctx, func(ctx context.Context, c conn) error {
+		if !m.cfg.dryRun {
+			if err := m.ensureTable(ctx, c); err != nil {
+				return err
+			}
+		}
+
+		applied, err := m.applied(ctx, c)
+		if err != nil {
+			return err
+		}
+		if err = m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			res, err := m.apply(ctx, c, mig, DirectionUp)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+		return nil
+	})
+	return results, err
+}
+
+// Down rolls back the given number of most recently applied migrations.
+// It returns the migrations rolled back before an error occurred, if any.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Result, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("migrate: steps must be positive, got %d", steps)
+	}
+
+	var results []Result
+	err := m.withLock(ctx, func(ctx context.Context, c conn) error {
+		applied, err := m.applied(ctx, c)
+		if err != nil {
+			return err
+		}
+		if err = m.verify(applied); err != nil {
+			return err
+		}
+
+		versions := make([]uint64, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		slices.SortFunc(versions, func(a, b uint64) int { return cmp.Compare(b, a) })
+
+		byVersion := m.byVersion()
+		for _, v := range versions[:min(steps, len(versions))] {
+			mig, ok := byVersion[v]
+			if !ok {
+				return fmt.Errorf("%w: version %d", ErrMissingMigration, v)
+			}
+			res, err := m.apply(ctx, c, mig, DirectionDown)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+		return nil
+	})
+	return results, err
+}
+
+// Status reports the state of every migration in the source, plus applied
+// migrations that are missing from it, sorted by version.
+func (m *Migrator) Status(ctx context.Context) (statuses []Status, err error) {
+	c, err := m.open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: open connection: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, c.close())
+	}()
+
+	applied, err := m.applied(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses = make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if a, ok := applied[mig.Version]; ok {
+			s.Applied, s.AppliedAt, s.Dirty = true, a.appliedAt, a.dirty
+			s.Modified = a.checksum != mig.Checksum
+			delete(applied, mig.Version)
+		}
+		statuses = append(statuses, s)
+	}
+	for _, a := range applied {
+		statuses = append(statuses, Status{
+			Version: a.version, Name: a.name, Applied: true, AppliedAt: a.appliedAt, Dirty: a.dirty, Missing: true,
+		})
+	}
+	slices.SortFunc(statuses, func(a, b Status) int { return cmp.Compare(a.Version, b.Version) })
+	return statuses, nil
+}
+
+// Run executes a migration command, writing a human-readable report to w.
+// It is meant to back a CLI entry point, e.g. m.Run(ctx, os.Stdout, os.Args[1:]...).
+//
+// Commands:
+//   - up (default): apply all pending migrations.
+//   - down [n]: roll back the last n migrations (default 1).
+//   - status: print the state of every migration.
+func (m *Migrator) Run(ctx context.Context, w io.Writer, args ...string) error {
+	cmd := "up"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+
+	switch cmd {
+	case "up":
+		results, err := m.Up(ctx)
+		writeResults(w, results)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("migrate: invalid steps %q: %w", args[1], err)
+			}
+			steps = n
+		}
+		results, err := m.Down(ctx, steps)
+		writeResults(w, results)
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return writeStatuses(w, statuses)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownCommand, cmd)
+	}
+}
+
+// withLock runs fn on a dedicated connection holding the migration lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(ctx context.Context, c conn) error) (err error) {
+	c, err := m.open(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: open connection: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, c.close())
+	}()
+
+	if err = m.lock(ctx, c); err != nil {
+		return err
+	}
+	defer func() {
+		if errUnlock := m.unlock(context.WithoutCancel(ctx), c); errUnlock != nil {
+			err = errors.Join(err, fmt.Errorf("migrate: release lock: %w", errUnlock))
+		}
+	}()
+
+	return fn(ctx, c)
+}
+
+func (m *Migrator) lockKey() string {
+	return "migrate:" + m.cfg.table
+}
+
+func (m *Migrator) lock(ctx context.Context, c conn) error {
+	switch m.dialect {
+	case databases.DialectMySQL:
+		var got sql.NullInt64
+		timeout := int(math.Ceil(m.cfg.lockTimeout.Seconds()))
+		err := c.query(ctx, "SELECT GET_LOCK(?, ?)", []any{m.lockKey(), timeout}, func(scan func(dest ...any) error) error {
+			return scan(&got)
+		})
+		if err != nil {
+			return fmt.Errorf("migrate: acquire lock: %w", err)
+		}
+		if !got.Valid || got.Int64 != 1 {
+			return ErrLockTimeout
+		}
+		return nil
+	default:
+		lockCtx, cancel := context.WithTimeout(ctx, m.cfg.lockTimeout)
+		defer cancel()
+
+		if err := c.exec(lockCtx, "SELECT pg_advisory_lock($1)", m.advisoryLockID()); err != nil {
+			if errors.Is(lockCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+				return ErrLockTimeout
+			}
+			return fmt.Errorf("migrate: acquire lock: %w", err)
+		}
+		return nil
+	}
+}
+
+func (m *Migrator) unlock(ctx context.Context, c conn) error {
+	switch m.dialect {
+	case databases.DialectMySQL:
+		return c.exec(ctx, "SELECT RELEASE_LOCK(?)", m.lockKey())
+	default:
+		return c.exec(ctx, "SELECT pg_advisory_unlock($1)", m.advisoryLockID())
+	}
+}
+
+// advisoryLockID derives a stable PostgreSQL advisory lock key from the table name.
+func (m *Migrator) advisoryLockID() int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(m.lockKey()))
+	return int64(h.Sum64())
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, c conn) error {
+	err := c.exec(ctx, `CREATE TABLE IF NOT EXISTS `+m.cfg.table+` (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum VARCHAR(64) NOT NULL,
+	dirty BOOLEAN NOT NULL,
+	applied_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("migrate: create migration table: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) tableExists(ctx context.Context, c conn) (bool, error) {
+	schemaExpr := "current_schema()"
+	if m.dialect == databases.DialectMySQL {
+		schemaExpr = "DATABASE()"
+	}
+
+	table := m.cfg.table
+	where := squirrel.And{squirrel.Expr("table_schema = " + schemaExpr), squirrel.Eq{"table_name": table}}
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		where = squirrel.And{squirrel.Eq{"table_schema": schema}, squirrel.Eq{"table_name": name}}
+	}
+
+	query, args, err := squirrel.Select("COUNT(*)").
+		From("information_schema.tables").
+		Where(where).
+		PlaceholderFormat(m.placeholder).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var count int64
+	err = c.query(ctx, query, args, func(scan func(dest ...any) error) error {
+		return scan(&count)
+	})
+	if err != nil {
+		return false, fmt.Errorf("migrate: check migration table: %w", err)
+	}
+	return count > 0, nil
+}
+
+// applied returns the migrations recorded in the migration table, keyed by version.
+// A missing table means nothing was applied yet.
+func (m *Migrator) applied(ctx context.Context, c conn) (map[uint64]*appliedMigration, error) {
+	applied := make(map[uint64]*appliedMigration)
+
+	exists, err := m.tableExists(ctx, c)
+	if err != nil || !exists {
+		return applied, err
+	}
+
+	query := "SELECT version, name, checksum, dirty, applied_at FROM " + m.cfg.table + " ORDER BY version"
+	err = c.query(ctx, query, nil, func(scan func(dest ...any) error) error {
+		var (
+			version   int64
+			appliedAt any
+			a         appliedMigration
+		)
+		if err := scan(&version, &a.name, &a.checksum, &a.dirty, &appliedAt); err != nil {
+			return err
+		}
+		a.version = uint64(version)
+		a.appliedAt = parseAppliedAt(appliedAt)
+		applied[a.version] = &a
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read migration table: %w", err)
+	}
+	return applied, nil
+}
+
+// verify fails if a migration is dirty or an applied migration was edited.
+func (m *Migrator) verify(applied map[uint64]*appliedMigration) error {
+	byVersion := m.byVersion()
+	for _, a := range applied {
+		if a.dirty {
+			return fmt.Errorf("%w: version %d (%s)", ErrDirty, a.version, a.name)
+		}
+		if mig, ok := byVersion[a.version]; ok && mig.Checksum != a.checksum {
+			return fmt.Errorf("%w: version %d (%s) was modified after it was applied", ErrChecksumMismatch, a.version, a.name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) byVersion() map[uint64]*Migration {
+	byVersion := make(map[uint64]*Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+	return byVersion
+}
+
+// apply runs a single migration in the given direction and records the outcome.
+func (m *Migrator) apply(ctx context.Context, c conn, mig *Migration, dir Direction) (Result, error) {
+	s := mig.up
+	if dir == DirectionDown {
+		s = mig.down
+	}
+	if s == nil {
+		return Result{}, fmt.Errorf("%w: version %d (%s)", ErrNoDownMigration, mig.Version, mig.Name)
+	}
+
+	res := Result{Version: mig.Version, Name: mig.Name, Direction: dir, DryRun: m.cfg.dryRun}
+	if m.cfg.dryRun {
+		m.log(ctx, res)
+		return res, nil
+	}
+
+	start := time.Now()
+	var err error
+	if m.dialect == databases.DialectPostgres && !s.noTx {
+		err = m.applyInTx(ctx, c, mig, dir, s)
+	} else {
+		err = m.applyWithoutTx(ctx, c, mig, dir, s)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("migrate: %s %d_%s: %w", dir, mig.Version, mig.Name, err)
+	}
+
+	res.Duration = time.Since(start)
+	m.log(ctx, res)
+	return res, nil
+}
+
+func (m *Migrator) applyInTx(ctx context.Context, c conn, mig *Migration, dir Direction, s *script) (err error) {
+	tx, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.rollback(ctx))
+			return
+		}
+		err = tx.commit(ctx)
+	}()
+
+	if err = tx.exec(ctx, s.sql); err != nil {
+		return err
+	}
+	if dir == DirectionUp {
+		return m.record(ctx, tx.exec, squirrel.Insert(m.cfg.table).
+			Columns("version", "name", "checksum", "dirty", "applied_at").
+			Values(int64(mig.Version), mig.Name, mig.Checksum, false, time.Now().UTC()))
+	}
+	return m.record(ctx, tx.exec, squirrel.Delete(m.cfg.table).Where(squirrel.Eq{"version": int64(mig.Version)}))
+}
+
+// applyWithoutTx marks the migration dirty while its script runs, so a failure
+// halfway through is detected by the next run.
+func (m *Migrator) applyWithoutTx(ctx context.Context, c conn, mig *Migration, dir Direction, s *script) error {
+	var err error
+	if dir == DirectionUp {
+		err = m.record(ctx, c.exec, squirrel.Insert(m.cfg.table).
+			Columns("version", "name", "checksum", "dirty", "applied_at").
+			Values(int64(mig.Version), mig.Name, mig.Checksum, true, time.Now().UTC()))
+	} else {
+		err = m.record(ctx, c.exec, squirrel.Update(m.cfg.table).
+			Set("dirty", true).
+			Where(squirrel.Eq{"version": int64(mig.Version)}))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = c.exec(ctx, s.sql); err != nil {
+		return err
+	}
+
+	if dir == DirectionUp {
+		return m.record(ctx, c.exec, squirrel.Update(m.cfg.table).
+			Set("dirty", false).
+			Where(squirrel.Eq{"version": int64(mig.Version)}))
+	}
+	return m.record(ctx, c.exec, squirrel.Delete(m.cfg.table).Where(squirrel.Eq{"version": int64(mig.Version)}))
+}
+
+// record executes a bookkeeping statement on the migration table.
+func (m *Migrator) record(ctx context.Context, exec func(ctx context.Context, query string, args ...any) error, b squirrel.Sqlizer) error {
+	query, args, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+	query, err = m.placeholder.ReplacePlaceholders(query)
+	if err != nil {
+		return err
+	}
+	if err = exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("update migration table: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) log(ctx context.Context, res Result) {
+	observability.Start(ctx, zerolog.InfoLevel).
+		Uint64("version", res.Version).
+		Str("name", res.Name).
+		Str("direction", string(res.Direction)).
+		Dur("duration", res.Duration).
+		Bool("dry_run", res.DryRun).
+		Msg("[MIGRATE]")
+}
+
+func writeResults(w io.Writer, results []Result) {
+	if len(results) == 0 {
+		_, _ = fmt.Fprintln(w, "no migrations to run")
+		return
+	}
+	for _, r := range results {
+		verb := "applied"
+		if r.Direction == DirectionDown {
+			verb = "rolled back"
+		}
+		if r.DryRun {
+			_, _ = fmt.Fprintf(w, "would run %s %d_%s (dry run)\n", r.Direction, r.Version, r.Name)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s %d_%s (%s)\n", verb, r.Version, r.Name, r.Duration.Round(time.Millisecond))
+	}
+}
+
+func writeStatuses(w io.Writer, statuses []Status) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		state, appliedAt := "pending", "-"
+		if s.Applied {
+			state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+		}
+		switch {
+		case s.Dirty:
+			state = "dirty"
+		case s.Missing:
+			state = "missing"
+		case s.Modified:
+			state = "modified"
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return tw.Flush()
+}
+
+// parseAppliedAt converts the scanned applied_at column. MySQL returns text
+// unless the DSN sets parseTime=true.
+func parseAppliedAt(v any) time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case []byte:
+		parsed, _ := time.Parse("2006-01-02 15:04:05.999999", string(t))
+		return parsed
+	case string:
+		parsed, _ := time.Parse("2006-01-02 15:04:05.999999", t)
+		return parsed
+	default:
+		return time.Time{}
+	}
+}
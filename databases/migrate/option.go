@@ -0,0 +1,61 @@
+package migrate
+
+import "time"
+
+// Option configures a Migrator.
+type Option interface {
+	apply(*config)
+}
+
+type optFunc func(*config)
+
+func (o optFunc) apply(c *config) {
+	o(c)
+}
+
+type config struct {
+	dir         string
+	table       string
+	lockTimeout time.Duration
+	dryRun      bool
+}
+
+func defaultConfig() *config {
+	return &config{
+		dir:         ".",
+		table:       defaultTable,
+		lockTimeout: defaultLockTimeout,
+	}
+}
+
+// WithDir sets the directory of the source filesystem holding the migration files.
+// Default: the root of the filesystem.
+func WithDir(dir string) Option {
+	return optFunc(func(c *config) {
+		c.dir = dir
+	})
+}
+
+// WithTable sets the name of the table recording applied migrations.
+// Default: schema_migrations.
+func WithTable(table string) Option {
+	return optFunc(func(c *config) {
+		c.table = table
+	})
+}
+
+// WithLockTimeout sets how long a run waits for the migration lock held by
+// another process before failing with ErrLockTimeout. Default: 1m.
+func WithLockTimeout(d time.Duration) Option {
+	return optFunc(func(c *config) {
+		c.lockTimeout = d
+	})
+}
+
+// WithDryRun makes Up and Down report the migrations they would run
+// without executing them or changing the migration table.
+func WithDryRun(dryRun bool) Option {
+	return optFunc(func(c *config) {
+		c.dryRun = dryRun
+	})
+}
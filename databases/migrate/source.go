@@ -0,0 +1,126 @@
+package migrate
+
+import (
+	"bufio"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// noTransactionDirective disables the transaction for a single migration file,
+// e.g. for CREATE INDEX CONCURRENTLY in PostgreSQL.
+const noTransactionDirective = "-- migrate:no-transaction"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([^.]+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change loaded from the source filesystem.
+type Migration struct {
+	Version  uint64 // Numeric prefix of the file name
+	Name     string // File name part between the version and the direction
+	Checksum string // SHA-256 (hex) of the up script, used to detect edited migrations
+
+	up   *script
+	down *script
+}
+
+// HasDown reports whether the migration has a down script.
+func (m *Migration) HasDown() bool {
+	return m.down != nil
+}
+
+type script struct {
+	sql  string
+	noTx bool
+}
+
+// loadMigrations reads "<version>_<name>.up.sql" and "<version>_<name>.down.sql" files
+// from dir and returns them sorted by version. Other files are ignored.
+func loadMigrations(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir %q: %w", dir, err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse version of %q: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %q: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d is used by %q and %q", ErrDuplicateVersion, version, m.Name, match[2])
+		}
+
+		s := &script{sql: string(content), noTx: hasNoTransactionDirective(string(content))}
+		switch match[3] {
+		case "up":
+			if m.up != nil {
+				return nil, fmt.Errorf("%w: version %d has more than one up script", ErrDuplicateVersion, version)
+			}
+			sum := sha256.Sum256(content)
+			m.up, m.Checksum = s, hex.EncodeToString(sum[:])
+		case "down":
+			if m.down != nil {
+				return nil, fmt.Errorf("%w: version %d has more than one down script", ErrDuplicateVersion, version)
+			}
+			m.down = s
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == nil {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+	slices.SortFunc(migrations, func(a, b *Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
+
+// hasNoTransactionDirective reports whether the leading comment block of the
+// script contains the no-transaction directive.
+func hasNoTransactionDirective(sql string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(sql))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			return false
+		}
+		if strings.EqualFold(line, noTransactionDirective) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0002_add_index.up.sql":      {Data: []byte("-- add index\n-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY idx ON users (email);")},
+		"migrations/0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id BIGINT);")},
+		"migrations/0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"migrations/README.md":                  {Data: []byte("ignored")},
+	}
+
+	migrations, err := loadMigrations(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("len(migrations) = %d, want 2", len(migrations))
+	}
+
+	first, second := migrations[0], migrations[1]
+	if first.Version != 1 || first.Name != "create_users" || !first.HasDown() || first.up.noTx {
+		t.Fatalf("migrations[0] = %+v, want version 1 create_users with down script in a transaction", first)
+	}
+	if second.Version != 2 || second.HasDown() || !second.up.noTx {
+		t.Fatalf("migrations[1] = %+v, want version 2 without down script and no transaction", second)
+	}
+	if len(first.Checksum) != 64 || first.Checksum == second.Checksum {
+		t.Fatalf("checksums = %q, %q, want distinct sha256 hex", first.Checksum, second.Checksum)
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr error
+	}{
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"1_a.up.sql": {Data: []byte("SELECT 1;")},
+				"1_b.up.sql": {Data: []byte("SELECT 2;")},
+			},
+			wantErr: ErrDuplicateVersion,
+		},
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{
+				"1_a.down.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.fsys, ".")
+			if err == nil {
+				t.Fatalf("loadMigrations() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadMigrations() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasNoTransactionDirective(t *testing.T) {
+	if hasNoTransactionDirective("CREATE TABLE t (id INT);\n-- migrate:no-transaction") {
+		t.Fatalf("directive after the first statement must be ignored")
+	}
+	if !hasNoTransactionDirective("\n-- MIGRATE:NO-TRANSACTION\nCREATE INDEX CONCURRENTLY i ON t (id);") {
+		t.Fatalf("leading directive must be detected")
+	}
+}
@@ -0,0 +1,94 @@
+package outbox
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Option configures an Outbox.
+type Option interface {
+	apply(*config)
+}
+
+type optFunc func(*config)
+
+func (o optFunc) apply(c *config) {
+	o(c)
+}
+
+type config struct {
+	table         string
+	writerKey     string
+	batchSize     int
+	pollInterval  time.Duration
+	retention     time.Duration
+	notifyChannel string
+	propagator    propagation.TextMapPropagator
+}
+
+func defaultConfig() *config {
+	return &config{
+		table:        defaultTable,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// WithTable sets the outbox table name. Default: outbox_events.
+func WithTable(table string) Option {
+	return optFunc(func(c *config) {
+		c.table = table
+	})
+}
+
+// WithWriterKey sets the kafkax writer (PubInput.KeyWriter) used by the relay.
+// The writer must not have a fixed Topic, since every event carries its own.
+func WithWriterKey(key string) Option {
+	return optFunc(func(c *config) {
+		c.writerKey = key
+	})
+}
+
+// WithBatchSize sets the maximum number of events published per relay cycle. Default: 100.
+func WithBatchSize(n int) Option {
+	return optFunc(func(c *config) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	})
+}
+
+// WithPollInterval sets how often the relay polls the table when idle. Default: 1s.
+func WithPollInterval(d time.Duration) Option {
+	return optFunc(func(c *config) {
+		if d > 0 {
+			c.pollInterval = d
+		}
+	})
+}
+
+// WithRetention keeps sent events for d (marked with sent_at) before they are purged.
+// By default sent events are deleted as soon as they are published.
+func WithRetention(d time.Duration) Option {
+	return optFunc(func(c *config) {
+		c.retention = d
+	})
+}
+
+// WithNotify enables LISTEN/NOTIFY on the given channel (PostgreSQL only).
+// Enqueue notifies the channel on commit, and a relay built with NewPgx wakes up
+// immediately instead of waiting for the next poll.
+func WithNotify(channel string) Option {
+	return optFunc(func(c *config) {
+		c.notifyChannel = channel
+	})
+}
+
+// WithPropagator sets the propagator used to store the trace context of Enqueue
+// in the event headers. Default: otel.GetTextMapPropagator().
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return optFunc(func(c *config) {
+		c.propagator = p
+	})
+}
@@ -0,0 +1,223 @@
+// Package outbox implements the transactional outbox pattern on top of pgxx/sqlx and kafkax.
+//
+// Events are written with Enqueue in the same database transaction as the business
+// change (inside DoTxContext), so either both are committed or neither is. A relay
+// (Run) then publishes committed events to Kafka through kafkax.PubSub and removes
+// them from the table. Delivery is at-least-once: consumers must be idempotent.
+//
+// Ordering: events sharing an AggregateKey are published in insertion order, even with
+// several relays running concurrently, and use the AggregateKey as Kafka message key
+// (unless Key is set) so they land on the same partition.
+//
+// The table can be created with CreateTableSQL, e.g. from a migration.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/broker/kafkax"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/sqlx"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	defaultTable        = "outbox_events"
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+)
+
+// ErrNoTransaction is returned by Enqueue when ctx does not carry a transaction
+// started by DoTxContext on the outbox database.
+var ErrNoTransaction = errors.New("outbox: enqueue must run inside DoTxContext")
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Message is an event to be published to Kafka.
+type Message struct {
+	Topic        string            // Kafka topic
+	AggregateKey string            // Ordering key; events of the same aggregate are published in order
+	Key          []byte            // Kafka message key. Default: AggregateKey
+	Value        []byte            // Message payload
+	Headers      map[string]string // Additional Kafka headers
+}
+
+// Outbox writes events to the outbox table and relays them to Kafka.
+type Outbox struct {
+	cfg         *config
+	store       store
+	pubSub      kafkax.PubSub
+	placeholder squirrel.PlaceholderFormat
+}
+
+// NewPgx creates an Outbox on a pgxx RDBMS (the base, non-transactional instance).
+func NewPgx(db pgxx.RDBMS, pubSub kafkax.PubSub, opts ...Option) (*Outbox, error) {
+	return newOutbox(&pgxStore{db: db}, pubSub, opts...)
+}
+
+// NewSQL creates an Outbox on a sqlx RDBMS (the base, non-transactional instance)
+// for the given dialect. MySQL requires version 8.0+ for SKIP LOCKED.
+func NewSQL(db sqlx.RDBMS, dialect databases.Dialect, pubSub kafkax.PubSub, opts ...Option) (*Outbox, error) {
+	return newOutbox(&sqlStore{db: db, sqlDialect: dialect}, pubSub, opts...)
+}
+
+func newOutbox(s store, pubSub kafkax.PubSub, opts ...Option) (*Outbox, error) {
+	cfg := defaultConfig()
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+	if cfg.propagator == nil {
+		cfg.propagator = otel.GetTextMapPropagator()
+	}
+
+	if !tableNamePattern.MatchString(cfg.table) {
+		return nil, fmt.Errorf("outbox: invalid table name %q", cfg.table)
+	}
+
+	var placeholder squirrel.PlaceholderFormat
+	switch s.dialect() {
+	case databases.DialectPostgres:
+		placeholder = squirrel.Dollar
+	case databases.DialectMySQL:
+		placeholder = squirrel.Question
+	default:
+		return nil, fmt.Errorf("outbox: unsupported dialect %q", s.dialect())
+	}
+
+	return &Outbox{
+		cfg:         cfg,
+		store:       s,
+		pubSub:      pubSub,
+		placeholder: placeholder,
+	}, nil
+}
+
+// CreateTableSQL returns the DDL of the outbox table for the given dialect.
+// The table may be schema-qualified, e.g. "app.outbox".
+func CreateTableSQL(dialect databases.Dialect, table string) string {
+	index := pendingIndexName(table)
+	if dialect == databases.DialectMySQL {
+		return `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	aggregate_key VARCHAR(255) NOT NULL,
+	topic VARCHAR(255) NOT NULL,
+	msg_key VARBINARY(1024) NULL,
+	payload LONGBLOB NOT NULL,
+	headers JSON NOT NULL,
+	created_at TIMESTAMP(6) NOT NULL,
+	sent_at TIMESTAMP(6) NULL,
+	INDEX ` + index + ` (sent_at, aggregate_key, id)
+)`
+	}
+
+	return `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	id BIGSERIAL PRIMARY KEY,
+	aggregate_key TEXT NOT NULL,
+	topic TEXT NOT NULL,
+	msg_key BYTEA NULL,
+	payload BYTEA NOT NULL,
+	headers JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	sent_at TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS ` + index + ` ON ` + table + ` (aggregate_key, id) WHERE sent_at IS NULL`
+}
+
+// pendingIndexName returns the name of the index on pending events of table. Index
+// names cannot be schema-qualified, the index lives in the schema of its table.
+func pendingIndexName(table string) string {
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		table = table[i+1:]
+	}
+	return "idx_" + table + "_pending"
+}
+
+// Enqueue writes msgs to the outbox table within the transaction carried by ctx.
+// It must be called with the ctx given to a DoTxContext callback on the outbox database;
+// otherwise it returns ErrNoTransaction.
+//
+// The trace context of ctx is stored in the event headers, so consumers continue
+// the trace of the request that produced the event.
+func (o *Outbox) Enqueue(ctx context.Context, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	if !o.store.inTx(ctx) {
+		return ErrNoTransaction
+	}
+
+	now := time.Now().UTC()
+	insert := squirrel.Insert(o.cfg.table).
+		Columns("aggregate_key", "topic", "msg_key", "payload", "headers", "created_at").
+		PlaceholderFormat(o.placeholder)
+	for _, m := range msgs {
+		if m.Topic == "" {
+			return errors.New("outbox: message topic is required")
+		}
+		headers, err := o.encodeHeaders(ctx, m.Headers)
+		if err != nil {
+			return err
+		}
+		insert = insert.Values(m.AggregateKey, m.Topic, m.Key, m.Value, headers, now)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("outbox: build insert: %w", err)
+	}
+	if err = o.store.exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("outbox: insert events: %w", err)
+	}
+
+	if o.cfg.notifyChannel != "" && o.store.dialect() == databases.DialectPostgres {
+		if err = o.store.exec(ctx, "SELECT pg_notify($1, '')", o.cfg.notifyChannel); err != nil {
+			return fmt.Errorf("outbox: notify: %w", err)
+		}
+	}
+	return nil
+}
+
+// encodeHeaders merges the trace context of ctx into headers and encodes them as JSON.
+func (o *Outbox) encodeHeaders(ctx context.Context, headers map[string]string) (string, error) {
+	carrier := make(propagation.MapCarrier, len(headers)+2)
+	for k, v := range headers {
+		carrier[k] = v
+	}
+	o.cfg.propagator.Inject(ctx, carrier)
+
+	b, err := json.Marshal(carrier)
+	if err != nil {
+		return "", fmt.Errorf("outbox: encode headers: %w", err)
+	}
+	return string(b), nil
+}
+
+// toKafkaMessage converts a stored event into a Kafka message.
+func toKafkaMessage(topic, aggregateKey string, key, payload, headers []byte) (kafka.Message, error) {
+	msg := kafka.Message{Topic: topic, Key: key, Value: payload}
+	if len(msg.Key) == 0 {
+		msg.Key = []byte(aggregateKey)
+	}
+
+	if len(headers) > 0 {
+		var h map[string]string
+		if err := json.Unmarshal(headers, &h); err != nil {
+			return kafka.Message{}, fmt.Errorf("outbox: decode headers: %w", err)
+		}
+		msg.Headers = make([]kafka.Header, 0, len(h))
+		for k, v := range h {
+			msg.Headers = append(msg.Headers, kafka.Header{Key: k, Value: []byte(v)})
+		}
+	}
+	return msg, nil
+}
@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOutbox_HeadersRoundTrip(t *testing.T) {
+	o, err := newOutbox(&sqlStore{sqlDialect: databases.DialectMySQL}, nil, WithPropagator(propagation.TraceContext{}))
+	if err != nil {
+		t.Fatalf("newOutbox() error = %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	headers, err := o.encodeHeaders(ctx, map[string]string{"event_type": "user.created"})
+	if err != nil {
+		t.Fatalf("encodeHeaders() error = %v", err)
+	}
+
+	msg, err := toKafkaMessage("users", "user-1", nil, []byte(`{}`), []byte(headers))
+	if err != nil {
+		t.Fatalf("toKafkaMessage() error = %v", err)
+	}
+	if string(msg.Key) != "user-1" {
+		t.Fatalf("Key = %q, want aggregate key user-1", msg.Key)
+	}
+
+	got := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		got[h.Key] = string(h.Value)
+	}
+	if got["event_type"] != "user.created" {
+		t.Fatalf("headers = %v, want event_type", got)
+	}
+	if want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"; got["traceparent"] != want {
+		t.Fatalf("traceparent = %q, want %q", got["traceparent"], want)
+	}
+}
+
+func TestNewOutbox_InvalidTable(t *testing.T) {
+	if _, err := newOutbox(&sqlStore{sqlDialect: databases.DialectPostgres}, nil, WithTable("events; DROP TABLE x")); err == nil {
+		t.Fatalf("newOutbox() error = nil, want invalid table error")
+	}
+}
+
+func TestCreateTableSQL_SchemaQualified(t *testing.T) {
+	pg := CreateTableSQL(databases.DialectPostgres, "app.outbox")
+	if want := "CREATE INDEX IF NOT EXISTS idx_outbox_pending ON app.outbox "; !strings.Contains(pg, want) {
+		t.Fatalf("CreateTableSQL(postgres) = %q, want it to contain %q", pg, want)
+	}
+	my := CreateTableSQL(databases.DialectMySQL, "app.outbox")
+	if want := "INDEX idx_outbox_pending (sent_at"; !strings.Contains(my, want) {
+		t.Fatalf("CreateTableSQL(mysql) = %q, want it to contain %q", my, want)
+	}
+}
@@ -0,0 +1,216 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/broker/kafkax"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/observability"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/kafka-go"
+)
+
+// Run relays committed events to Kafka until ctx is canceled, then returns nil.
+// Several relays may run concurrently (e.g. one per pod); rows are claimed with
+// FOR UPDATE SKIP LOCKED so every event is published by a single relay.
+//
+// Failed cycles are logged and retried on the next poll; events stay in the table
+// until they are published.
+func (o *Outbox) Run(ctx context.Context) error {
+	wake := make(chan struct{}, 1)
+	if o.cfg.notifyChannel != "" {
+		go o.listen(ctx, wake)
+	}
+
+	ticker := time.NewTicker(o.cfg.pollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		n, err := o.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			observability.Start(ctx, zerolog.ErrorLevel).Err(err).Msg("[OUTBOX] relay failed")
+		}
+
+		if o.cfg.retention > 0 && time.Since(lastCleanup) >= o.cleanupInterval() {
+			if err = o.Cleanup(ctx); err != nil && ctx.Err() == nil {
+				observability.Start(ctx, zerolog.ErrorLevel).Err(err).Msg("[OUTBOX] cleanup failed")
+			}
+			lastCleanup = time.Now()
+		}
+
+		// A full batch means more events are likely pending.
+		if err == nil && n == o.cfg.batchSize {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+// RelayOnce publishes up to one batch of pending events and returns how many were sent.
+//
+// Only the oldest pending event of each aggregate is locked (FOR UPDATE SKIP LOCKED);
+// holding it prevents other relays from claiming any event of that aggregate, so the
+// remaining events of the claimed aggregates can be published in order within the batch.
+func (o *Outbox) RelayOnce(ctx context.Context) (sent int, err error) {
+	err = o.store.doTx(ctx, func(ctx context.Context) error {
+		keys, err := o.claimAggregates(ctx)
+		if err != nil || len(keys) == 0 {
+			return err
+		}
+
+		ids, msgs, err := o.pendingEvents(ctx, keys)
+		if err != nil || len(msgs) == 0 {
+			return err
+		}
+
+		if _, err = o.pubSub.Publish(ctx, kafkax.PubInput{KeyWriter: o.cfg.writerKey, Messages: msgs}); err != nil {
+			return fmt.Errorf("outbox: publish: %w", err)
+		}
+
+		if err = o.markSent(ctx, ids); err != nil {
+			return err
+		}
+		sent = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sent, nil
+}
+
+// Cleanup deletes events sent longer ago than the retention configured with WithRetention.
+func (o *Outbox) Cleanup(ctx context.Context) error {
+	query, args, err := squirrel.Delete(o.cfg.table).
+		Where(squirrel.NotEq{"sent_at": nil}).
+		Where(squirrel.Lt{"sent_at": time.Now().UTC().Add(-o.cfg.retention)}).
+		PlaceholderFormat(o.placeholder).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if err = o.store.exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("outbox: delete sent events: %w", err)
+	}
+	return nil
+}
+
+func (o *Outbox) cleanupInterval() time.Duration {
+	return max(o.cfg.retention/10, time.Minute)
+}
+
+// claimAggregates locks the oldest pending event of up to batchSize aggregates
+// and returns their aggregate keys.
+func (o *Outbox) claimAggregates(ctx context.Context) ([]string, error) {
+	query, args, err := squirrel.Select("aggregate_key").
+		From(o.cfg.table).
+		Where(squirrel.Eq{"sent_at": nil}).
+		Where("id IN (SELECT MIN(id) FROM " + o.cfg.table + " WHERE sent_at IS NULL GROUP BY aggregate_key)").
+		OrderBy("id").
+		Limit(uint64(o.cfg.batchSize)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		PlaceholderFormat(o.placeholder).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	err = o.store.query(ctx, query, args, func(scan func(dest ...any) error) error {
+		var key string
+		if err := scan(&key); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox: claim events: %w", err)
+	}
+	return keys, nil
+}
+
+// pendingEvents loads up to batchSize pending events of the given aggregates in insertion order.
+func (o *Outbox) pendingEvents(ctx context.Context, keys []string) ([]int64, []kafka.Message, error) {
+	query, args, err := squirrel.Select("id", "aggregate_key", "topic", "msg_key", "payload", "headers").
+		From(o.cfg.table).
+		Where(squirrel.Eq{"sent_at": nil, "aggregate_key": keys}).
+		OrderBy("id").
+		Limit(uint64(o.cfg.batchSize)).
+		PlaceholderFormat(o.placeholder).
+		ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]int64, 0, o.cfg.batchSize)
+	msgs := make([]kafka.Message, 0, o.cfg.batchSize)
+	err = o.store.query(ctx, query, args, func(scan func(dest ...any) error) error {
+		var (
+			id                    int64
+			aggregateKey, topic   string
+			key, payload, headers []byte
+		)
+		if err := scan(&id, &aggregateKey, &topic, &key, &payload, &headers); err != nil {
+			return err
+		}
+
+		msg, err := toKafkaMessage(topic, aggregateKey, key, payload, headers)
+		if err != nil {
+			return fmt.Errorf("event %d: %w", id, err)
+		}
+		ids = append(ids, id)
+		msgs = append(msgs, msg)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("outbox: load events: %w", err)
+	}
+	return ids, msgs, nil
+}
+
+// markSent deletes the published events, or marks them sent when a retention is configured.
+func (o *Outbox) markSent(ctx context.Context, ids []int64) error {
+	var b squirrel.Sqlizer = squirrel.Delete(o.cfg.table).
+		Where(squirrel.Eq{"id": ids}).
+		PlaceholderFormat(o.placeholder)
+	if o.cfg.retention > 0 {
+		b = squirrel.Update(o.cfg.table).
+			Set("sent_at", time.Now().UTC()).
+			Where(squirrel.Eq{"id": ids}).
+			PlaceholderFormat(o.placeholder)
+	}
+
+	query, args, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+	if err = o.store.exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("outbox: mark events sent: %w", err)
+	}
+	return nil
+}
+
+// listen wakes the relay on notifications, reconnecting after failures.
+func (o *Outbox) listen(ctx context.Context, wake chan<- struct{}) {
+	for ctx.Err() == nil {
+		err := o.store.listen(ctx, o.cfg.notifyChannel, wake)
+		if err == nil {
+			return
+		}
+		observability.Start(ctx, zerolog.WarnLevel).Err(err).Msg("[OUTBOX] listen failed, falling back to polling")
+		sleep(ctx, o.cfg.pollInterval)
+	}
+}
@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/sqlx"
+	"github.com/jackc/pgx/v5"
+)
+
+// store adapts the pgxx and sqlx RDBMS to the few operations the outbox needs.
+// Operations made with a ctx carrying a transaction join that transaction.
+type store interface {
+	dialect() databases.Dialect
+	inTx(ctx context.Context) bool
+	exec(ctx context.Context, query string, args ...any) error
+	query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error
+	doTx(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// listen blocks until ctx is done, signaling wake on every notification
+	// received on channel. Stores without LISTEN support return nil immediately
+	// and the relay falls back to polling.
+	listen(ctx context.Context, channel string, wake chan<- struct{}) error
+}
+
+type pgxStore struct {
+	db pgxx.RDBMS
+}
+
+func (s *pgxStore) dialect() databases.Dialect {
+	return databases.DialectPostgres
+}
+
+func (s *pgxStore) inTx(ctx context.Context) bool {
+	_, ok := pgxx.TxFromContext(ctx, s.db)
+	return ok
+}
+
+func (s *pgxStore) exec(ctx context.Context, query string, args ...any) error {
+	_, err := s.db.Exec(ctx, query, args...)
+	return err
+}
+
+func (s *pgxStore) query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = fn(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (s *pgxStore) doTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, ok := s.db.(pgxx.Tx)
+	if !ok {
+		return errors.New("outbox: pgxx RDBMS does not support transactions")
+	}
+	return tx.DoTxContext(ctx, pgx.TxOptions{}, func(ctx context.Context, _ pgxx.RDBMS) error {
+		return fn(ctx)
+	})
+}
+
+func (s *pgxStore) listen(ctx context.Context, channel string, wake chan<- struct{}) error {
+	l, err := pgxx.NewListener(s.db, []string{channel})
+	if err != nil {
+		return err
+	}
+	return l.Run(ctx, func(context.Context, pgxx.Notification) error {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+}
+
+type sqlStore struct {
+	db         sqlx.RDBMS
+	sqlDialect databases.Dialect
+}
+
+func (s *sqlStore) dialect() databases.Dialect {
+	return s.sqlDialect
+}
+
+func (s *sqlStore) inTx(ctx context.Context) bool {
+	_, ok := sqlx.TxFromContext(ctx, s.db)
+	return ok
+}
+
+func (s *sqlStore) exec(ctx context.Context, query string, args ...any) error {
+	_, err := s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (s *sqlStore) query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = fn(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (s *sqlStore) doTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, ok := s.db.(sqlx.Tx)
+	if !ok {
+		return errors.New("outbox: sqlx RDBMS does not support transactions")
+	}
+	return tx.DoTxContext(ctx, &sql.TxOptions{}, func(ctx context.Context, _ sqlx.RDBMS) error {
+		return fn(ctx)
+	})
+}
+
+func (s *sqlStore) listen(context.Context, string, chan<- struct{}) error {
+	return nil
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
@@ -0,0 +1,160 @@
+package pgxx
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrNotInTx is returned by the transaction-level advisory locks outside a transaction.
+var ErrNotInTx = errors.New("pgxx: transaction-level advisory lock requires a transaction")
+
+// AdvisoryLockKey hashes key (FNV-1a) to the int64 key of a PostgreSQL advisory lock.
+// Every process must use the same string for the same resource, e.g. "cron:billing".
+func AdvisoryLockKey(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+// AdvisoryLock is a session-level advisory lock, held on a dedicated connection
+// acquired from the pool until Unlock. The lock is also released by PostgreSQL when
+// that connection is lost; use Ping to detect it.
+type AdvisoryLock struct {
+	db   *rdbms
+	key  string
+	conn *pgxpool.Conn
+	mu   sync.Mutex
+}
+
+// AdvisoryLock acquires the session-level advisory lock of key (pg_advisory_lock),
+// waiting until it is available or ctx is done. The lock is held on a dedicated
+// connection, outside any transaction, until Unlock.
+func (s *rdbms) AdvisoryLock(ctx context.Context, key string) (*AdvisoryLock, error) {
+	lock, _, err := s.advisoryLock(ctx, key, "SELECT true FROM pg_advisory_lock($1)")
+	return lock, err
+}
+
+// TryAdvisoryLock acquires the session-level advisory lock of key if it is available
+// (pg_try_advisory_lock), without waiting. It returns false if another session holds it.
+func (s *rdbms) TryAdvisoryLock(ctx context.Context, key string) (*AdvisoryLock, bool, error) {
+	return s.advisoryLock(ctx, key, "SELECT pg_try_advisory_lock($1)")
+}
+
+func (s *rdbms) advisoryLock(ctx context.Context, key, sql string) (*AdvisoryLock, bool, error) {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	info := &HookInfo{Op: OpAdvisoryLock, SQL: sql, Args: []any{key}, Node: databases.NodePrimary, Start: time.Now()}
+	ctx, err = s.checkBefore(ctx, info)
+	acquired := false
+	if err == nil {
+		err = conn.QueryRow(ctx, sql, AdvisoryLockKey(key)).Scan(&acquired)
+		info.Err = err
+	}
+	info.End = time.Now()
+	s.callAfter(ctx, info)
+
+	if err != nil {
+		// A canceled wait may have raced with the grant: closing the session
+		// guarantees the lock is not left behind.
+		closeConn(conn)
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Release()
+		return nil, false, nil
+	}
+	return &AdvisoryLock{db: s, key: key, conn: conn}, true, nil
+}
+
+// Key returns the key the lock was acquired with.
+func (l *AdvisoryLock) Key() string {
+	return l.key
+}
+
+// Ping checks the connection holding the lock. An error means the lock may be lost:
+// the holder must stop acting as its owner and Unlock.
+func (l *AdvisoryLock) Ping(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return errors.New("pgxx: advisory lock released")
+	}
+	return l.conn.Ping(ctx)
+}
+
+// Unlock releases the lock (pg_advisory_unlock) and returns its connection to the pool.
+// If the unlock fails, the connection is closed, which releases the lock as well.
+// Unlock is idempotent.
+func (l *AdvisoryLock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	conn := l.conn
+	l.conn = nil
+
+	sql := "SELECT pg_advisory_unlock($1)"
+	info := &HookInfo{Op: OpAdvisoryUnlock, SQL: sql, Args: []any{l.key}, Node: databases.NodePrimary, Start: time.Now()}
+	ctx = l.db.callBefore(ctx, info)
+	_, err := conn.Exec(ctx, sql, AdvisoryLockKey(l.key))
+	info.Err = err
+	info.End = time.Now()
+	l.db.callAfter(ctx, info)
+
+	if err != nil {
+		closeConn(conn)
+		return err
+	}
+	conn.Release()
+	return nil
+}
+
+// XactAdvisoryLock acquires the transaction-level advisory lock of key
+// (pg_advisory_xact_lock), waiting until it is available or ctx is done. It must run
+// inside a transaction (s or ctx carrying one, see DoTxContext), which releases the
+// lock on commit or rollback; otherwise it returns ErrNotInTx.
+func (s *rdbms) XactAdvisoryLock(ctx context.Context, key string) error {
+	if tx, ok := s.ambientTx(ctx); ok {
+		return tx.XactAdvisoryLock(ctx, key)
+	}
+	if !s.isTx {
+		return ErrNotInTx
+	}
+
+	_, err := s.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", AdvisoryLockKey(key))
+	return err
+}
+
+// TryXactAdvisoryLock acquires the transaction-level advisory lock of key if it is
+// available (pg_try_advisory_xact_lock), without waiting. See XactAdvisoryLock.
+func (s *rdbms) TryXactAdvisoryLock(ctx context.Context, key string) (bool, error) {
+	if tx, ok := s.ambientTx(ctx); ok {
+		return tx.TryXactAdvisoryLock(ctx, key)
+	}
+	if !s.isTx {
+		return false, ErrNotInTx
+	}
+
+	acquired := false
+	err := s.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", AdvisoryLockKey(key)).Scan(&acquired)
+	return acquired, err
+}
+
+// closeConn removes conn from the pool and closes it, ending its session.
+func closeConn(conn *pgxpool.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = conn.Hijack().Close(ctx)
+}
@@ -0,0 +1,268 @@
+package pgxx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// bulkExecutor is implemented by both *pgxpool.Pool and pgx.Tx.
+type bulkExecutor interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// CopyFrom bulk loads rows into tableName using the PostgreSQL COPY protocol.
+// It runs inside the transaction when s (or ctx) carries one, otherwise on the pool.
+// Use pgx.CopyFromRows/CopyFromSlice/CopyFromFunc as row source, or CopyFromStructs
+// for a slice of structs. Hooks receive OpCopyFrom with the number of copied rows.
+func (s *rdbms) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	if tx, ok := s.ambientTx(ctx); ok {
+		return tx.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	}
+
+	executor, ok := s.queryExecutor.(bulkExecutor)
+	if !ok {
+		return 0, errors.New("pgxx: executor does not support COPY")
+	}
+
+	info := &HookInfo{
+		Op:    OpCopyFrom,
+		SQL:   fmt.Sprintf("COPY %s (%s) FROM STDIN", tableName.Sanitize(), strings.Join(columnNames, ", ")),
+		InTx:  s.isTx,
+		Node:  databases.NodePrimary,
+		Start: time.Now(),
+	}
+	databases.MarkWrite(ctx)
+	ctx, err := s.checkBefore(ctx, info)
+	if err != nil {
+		info.End = time.Now()
+		s.callAfter(ctx, info)
+		return 0, err
+	}
+
+	n, err := executor.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	info.Err = err
+	if err == nil {
+		info.Rows = &n
+	}
+	info.End = time.Now()
+	s.callAfter(ctx, info)
+	return n, err
+}
+
+// SendBatch sends all queued queries of batch to the server in a single round trip
+// (pipelined). It runs inside the transaction when s (or ctx) carries one.
+//
+// The returned BatchReader must be closed; hooks receive OpSendBatch when it is closed,
+// with the total number of rows affected or returned by the results read.
+func (s *rdbms) SendBatch(ctx context.Context, batch *pgx.Batch) *BatchReader {
+	if tx, ok := s.ambientTx(ctx); ok {
+		return tx.SendBatch(ctx, batch)
+	}
+
+	queries := make([]string, 0, batch.Len())
+	for _, q := range batch.QueuedQueries {
+		queries = append(queries, q.SQL)
+	}
+	info := &HookInfo{
+		Op:    OpSendBatch,
+		SQL:   strings.Join(queries, ";\n"),
+		InTx:  s.isTx,
+		Node:  databases.NodePrimary,
+		Start: time.Now(),
+	}
+	databases.MarkWrite(ctx)
+	ctx, err := s.checkBefore(ctx, info)
+
+	reader := &BatchReader{ctx: ctx, info: info, hooks: s}
+	if err != nil {
+		reader.err = err
+		return reader
+	}
+	executor, ok := s.queryExecutor.(bulkExecutor)
+	if !ok {
+		reader.err = errors.New("pgxx: executor does not support batches")
+		return reader
+	}
+	reader.results = executor.SendBatch(ctx, batch)
+	return reader
+}
+
+// BatchReader reads the results of a batch sent with SendBatch, in queue order.
+// Typed results can be collected with BatchCollect and BatchCollectOne.
+type BatchReader struct {
+	ctx     context.Context
+	info    *HookInfo
+	hooks   *rdbms
+	results pgx.BatchResults
+	rows    int64
+	err     error
+	closed  bool
+}
+
+// Exec reads the result of the next queued query as a command tag.
+func (r *BatchReader) Exec() (pgconn.CommandTag, error) {
+	if r.err != nil && r.results == nil {
+		return pgconn.CommandTag{}, r.err
+	}
+	tag, err := r.results.Exec()
+	r.observe(tag.RowsAffected(), err)
+	return tag, err
+}
+
+// Query reads the result of the next queued query as rows.
+func (r *BatchReader) Query() (pgx.Rows, error) {
+	if r.err != nil && r.results == nil {
+		return nil, r.err
+	}
+	rows, err := r.results.Query()
+	r.observe(0, err)
+	return rows, err
+}
+
+// QueryRow reads the result of the next queued query as a single row.
+func (r *BatchReader) QueryRow() pgx.Row {
+	if r.err != nil && r.results == nil {
+		return errRow{err: r.err}
+	}
+	return r.results.QueryRow()
+}
+
+// Close reads any remaining results, closes the batch and fires the OpSendBatch hooks.
+// It is safe to call more than once.
+func (r *BatchReader) Close() error {
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+
+	if r.results != nil {
+		if err := r.results.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+	}
+
+	r.info.Err = r.err
+	r.info.Rows = &r.rows
+	r.info.End = time.Now()
+	r.hooks.callAfter(r.ctx, r.info)
+	return r.err
+}
+
+func (r *BatchReader) observe(rows int64, err error) {
+	if err != nil {
+		if r.err == nil {
+			r.err = err
+		}
+		return
+	}
+	r.rows += rows
+}
+
+// BatchCollect reads the result of the next queued query of r and maps every row with fn,
+// e.g. pgx.RowToStructByName[User].
+func BatchCollect[T any](r *BatchReader, fn pgx.RowToFunc[T]) ([]T, error) {
+	rows, err := r.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := pgx.CollectRows(rows, fn)
+	r.observe(rows.CommandTag().RowsAffected(), err)
+	return items, err
+}
+
+// BatchCollectOne reads the result of the next queued query of r and maps its single row with fn.
+// It returns pgx.ErrNoRows when the query returned no rows.
+func BatchCollectOne[T any](r *BatchReader, fn pgx.RowToFunc[T]) (T, error) {
+	rows, err := r.Query()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	item, err := pgx.CollectOneRow(rows, fn)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		r.observe(0, err)
+		return item, err
+	}
+	r.observe(rows.CommandTag().RowsAffected(), nil)
+	return item, err
+}
+
+// CopyFromStructs adapts a slice of structs to CopyFrom. Columns are mapped with
+// databases.StructFields; fields tagged with the readonly option (`db:"id,readonly"`,
+// generated by the database) are not copied.
+//
+// Example:
+//
+//	cols, src, err := pgxx.CopyFromStructs(users)
+//	n, err := db.CopyFrom(ctx, pgx.Identifier{"users"}, cols, src)
+func CopyFromStructs[T any](items []T) ([]string, pgx.CopyFromSource, error) {
+	all, err := databases.StructFields(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, nil, fmt.Errorf("pgxx: CopyFromStructs: %w", err)
+	}
+
+	fields := make([]databases.StructField, 0, len(all))
+	columns := make([]string, 0, len(all))
+	for _, f := range all {
+		if f.HasOption("readonly") {
+			continue
+		}
+		fields = append(fields, f)
+		columns = append(columns, f.Column)
+	}
+
+	src := pgx.CopyFromSlice(len(items), func(i int) ([]any, error) {
+		v := reflect.ValueOf(items[i])
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, fmt.Errorf("pgxx: CopyFromStructs: nil item at index %d", i)
+			}
+			v = v.Elem()
+		}
+
+		values := make([]any, len(fields))
+		for j, f := range fields {
+			fv, err := v.FieldByIndexErr(f.Index)
+			if err != nil {
+				values[j] = nil
+				continue
+			}
+			values[j] = fv.Interface()
+		}
+		return values, nil
+	})
+	return columns, src, nil
+}
+
+// errRow is a pgx.Row that reports err on Scan.
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+// observedRow reports the outcome of a replica QueryRow to the replica router once
+// the row is scanned, as pgx only surfaces the query error on Scan.
+type observedRow struct {
+	pgx.Row
+	observe func(err error)
+}
+
+func (r *observedRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.observe(nil)
+	} else {
+		r.observe(err)
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package pgxx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCopyFromStructs_PointerEmbed(t *testing.T) {
+	type Base struct {
+		ID     int64 `db:"id,readonly"`
+		Tenant string
+	}
+	type order struct {
+		*Base
+		Total int
+	}
+
+	columns, src, err := CopyFromStructs([]order{
+		{Base: &Base{ID: 1, Tenant: "acme"}, Total: 10},
+		{Total: 20},
+	})
+	if err != nil {
+		t.Fatalf("CopyFromStructs() error = %v", err)
+	}
+	if want := []string{"tenant", "total"}; !reflect.DeepEqual(columns, want) {
+		t.Fatalf("columns = %v, want %v", columns, want)
+	}
+
+	want := [][]any{{"acme", 10}, {nil, 20}}
+	for i := 0; src.Next(); i++ {
+		values, err := src.Values()
+		if err != nil {
+			t.Fatalf("Values() error = %v", err)
+		}
+		if !reflect.DeepEqual(values, want[i]) {
+			t.Fatalf("row %d = %v, want %v", i, values, want[i])
+		}
+	}
+}
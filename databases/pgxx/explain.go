@@ -0,0 +1,95 @@
+package pgxx
+
+import (
+	"context"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// explainTracerName is the instrumentation name of the db.explain spans.
+const explainTracerName = "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx"
+
+// explainer captures the plan of the slow queries sampled by an ObservabilityHook.
+type explainer struct {
+	sampler *databases.ExplainSampler
+	query   func(ctx context.Context, sql string, args ...any) ([]byte, error)
+}
+
+// newExplainers creates the explainers of the ObservabilityHooks configured with Explain,
+// keyed by hook. Their EXPLAIN statements run on pool, outside of any transaction and
+// without hooks. Explainers belong to the RDBMS, so a hook shared by several RDBMS
+// explains each query on the database that ran it.
+func newExplainers(hooks []DBHook, pool *pgxpool.Pool) map[DBHook]*explainer {
+	explainers := make(map[DBHook]*explainer)
+	for _, h := range hooks {
+		oh, ok := h.(*ObservabilityHook)
+		if !ok || oh.Explain == nil {
+			continue
+		}
+		explainers[h] = &explainer{
+			sampler: databases.NewExplainSampler(*oh.Explain),
+			query: func(ctx context.Context, sql string, args ...any) ([]byte, error) {
+				var plan []byte
+				err := pool.QueryRow(ctx, sql, args...).Scan(&plan)
+				return plan, err
+			},
+		}
+	}
+	return explainers
+}
+
+// explain returns the plan summary of the query sql.
+func (x *explainer) explain(ctx context.Context, sql string, args []any) (databases.PlanSummary, error) {
+	query, err := databases.ExplainQuery(databases.DialectPostgres, sql)
+	if err != nil {
+		return databases.PlanSummary{}, err
+	}
+	plan, err := x.query(ctx, query, args...)
+	if err != nil {
+		return databases.PlanSummary{}, err
+	}
+	return databases.ParsePlan(databases.DialectPostgres, plan)
+}
+
+// logWithPlan explains sql, then emits e with the plan summary. It runs in its own
+// goroutine, so the query that was slow is not delayed further. By then the span of
+// the query has usually ended, so the EXPLAIN and its plan are recorded on a
+// db.explain child span, started with the tracer provider of the query span.
+func (x *explainer) logWithPlan(ctx context.Context, e *zerolog.Event, sql string, args []any, done func()) {
+	defer done()
+	ctx = context.WithoutCancel(ctx)
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer(explainTracerName).
+		Start(ctx, "db.explain", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	explainCtx, cancel := context.WithTimeout(ctx, x.sampler.Timeout())
+	defer cancel()
+
+	plan, err := x.explain(explainCtx, sql, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		e.Str("explain_err", truncateString(err.Error(), defaultLogFieldMaxSize)).Msg("[PGX]")
+		return
+	}
+
+	dict := zerolog.Dict().
+		Float64("cost", plan.Cost).
+		Float64("rows", plan.Rows).
+		Strs("seq_scans", plan.SeqScans)
+	if len(plan.Plan) <= defaultLogFieldMaxSize {
+		dict = dict.RawJSON("json", plan.Plan)
+	}
+	e.Dict("plan", dict).Msg("[PGX]")
+
+	span.SetAttributes(
+		attribute.Float64("db.plan.cost", plan.Cost),
+		attribute.Float64("db.plan.rows", plan.Rows),
+		attribute.StringSlice("db.plan.seq_scans", plan.SeqScans),
+	)
+}
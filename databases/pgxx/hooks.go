@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/observability"
 	"github.com/rs/zerolog"
 )
@@ -20,21 +21,47 @@ const (
 	OpTxBegin    Op = "tx_begin"
 	OpTxCommit   Op = "tx_commit"
 	OpTxRollback Op = "tx_rollback"
+
+	OpSavepoint           Op = "savepoint"
+	OpRollbackToSavepoint Op = "rollback_to_savepoint"
+	OpReleaseSavepoint    Op = "release_savepoint"
+
+	OpCopyFrom  Op = "copy_from"
+	OpSendBatch Op = "send_batch"
+
+	OpAfterCommit   Op = "after_commit"
+	OpAfterRollback Op = "after_rollback"
+
+	OpListen       Op = "listen"
+	OpNotification Op = "notification"
+
+	OpAdvisoryLock   Op = "advisory_lock"
+	OpAdvisoryUnlock Op = "advisory_unlock"
 )
 
 // HookInfo contains detailed information about a database operation.
 type HookInfo struct {
-	Op    Op
-	SQL   string
-	Args  []any
-	InTx  bool
-	Start time.Time
-	End   time.Time
-	Err   error
-	Rows  *int64
+	Op      Op
+	SQL     string
+	Args    []any
+	InTx    bool
+	Node    string
+	Attempt int
+	Start   time.Time
+	End     time.Time
+	Err     error
+	Rows    *int64
+	Vetoed  bool // True if a PolicyHook (or WithTenantRLS) vetoed the operation; it never reached the server and Err holds the veto
+
+	entered    int                   // Number of hooks whose Before ran, see callAfter
+	explainers map[DBHook]*explainer // Explainers of the RDBMS running the operation, by hook
 }
 
 // DBHook defines the interface for database hooks.
+//
+// Before is called in registration order (UseHook) and After in reverse order, so
+// the first registered hook wraps all others. A PolicyHook is checked right after its
+// own Before and may veto the operation; After is only called on hooks whose Before ran.
 type DBHook interface {
 	Before(ctx context.Context, info *HookInfo) context.Context
 	After(ctx context.Context, info *HookInfo)
@@ -70,6 +97,7 @@ func (h *DebugHook) After(ctx context.Context, info *HookInfo) {
 	attrs := []slog.Attr{
 		slog.String("op", string(info.Op)),
 		slog.Bool("in_tx", info.InTx),
+		slog.String("node", info.Node),
 		slog.Duration("duration", dur),
 		slog.Any("err", truncateError(info.Err, defaultLogFieldMaxSize)),
 		slog.Any("rows", rowsPtrVal(info.Rows)),
@@ -87,6 +115,9 @@ type ObservabilityHook struct {
 	WithArgs      bool
 	Mode          ObservabilityLogMode
 	SlowThreshold time.Duration
+
+	// Explain enables the EXPLAIN capture of slow queries, see WithObservabilityExplain.
+	Explain *databases.ExplainConfig
 }
 
 func (h *ObservabilityHook) Before(ctx context.Context, info *HookInfo) context.Context {
@@ -109,6 +140,12 @@ func (h *ObservabilityHook) After(ctx context.Context, info *HookInfo) {
 		Dur("duration", dur).
 		Str("sql", truncateString(info.SQL, defaultLogFieldMaxSize))
 
+	if info.Node != "" {
+		e = e.Str("node", info.Node)
+	}
+	if info.Attempt > 0 {
+		e = e.Int("attempt", info.Attempt)
+	}
 	if isSlow {
 		e = e.Bool("slow", true).
 			Dur("slow_threshold", h.slowThreshold())
@@ -123,6 +160,12 @@ func (h *ObservabilityHook) After(ctx context.Context, info *HookInfo) {
 		e = e.Interface("args", truncateArgs(info.Args, defaultLogFieldMaxSize))
 	}
 
+	if x := info.explainers[h]; x != nil && isSlow && !info.Vetoed && (info.Op == OpQuery || info.Op == OpQueryRow) {
+		if done, ok := x.sampler.Acquire(databases.DialectPostgres, info.SQL); ok {
+			go x.logWithPlan(ctx, e, info.SQL, info.Args, done)
+			return
+		}
+	}
 	e.Msg("[PGX]")
 }
 
@@ -0,0 +1,179 @@
+package pgxx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/observability"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultLeaderRetryInterval = 5 * time.Second
+	defaultLeaderCheckInterval = time.Second
+)
+
+// LeaderOption configures a LeaderElector.
+type LeaderOption interface {
+	apply(*leaderConfig)
+}
+
+type leaderOptFunc func(*leaderConfig)
+
+func (o leaderOptFunc) apply(cfg *leaderConfig) {
+	o(cfg)
+}
+
+type leaderConfig struct {
+	retryInterval time.Duration
+	checkInterval time.Duration
+	onChange      []func(ctx context.Context, leader bool)
+}
+
+// WithLeaderRetryInterval sets how often a follower tries to take the leadership. Default: 5s.
+func WithLeaderRetryInterval(d time.Duration) LeaderOption {
+	return leaderOptFunc(func(cfg *leaderConfig) {
+		if d > 0 {
+			cfg.retryInterval = d
+		}
+	})
+}
+
+// WithLeaderCheckInterval sets how often the leader checks the connection holding its
+// lock, which bounds how long a lost leadership goes unnoticed. Default: 1s.
+func WithLeaderCheckInterval(d time.Duration) LeaderOption {
+	return leaderOptFunc(func(cfg *leaderConfig) {
+		if d > 0 {
+			cfg.checkInterval = d
+		}
+	})
+}
+
+// OnLeadershipChange registers fn, called with leader=true when the leadership is
+// acquired and leader=false when it is lost or given up. On election ctx is canceled
+// when the leadership ends, so work started from it stops with the leadership.
+// Callbacks run synchronously in the election loop and must return quickly.
+func OnLeadershipChange(fn func(ctx context.Context, leader bool)) LeaderOption {
+	return leaderOptFunc(func(cfg *leaderConfig) {
+		cfg.onChange = append(cfg.onChange, fn)
+	})
+}
+
+// LeaderElector elects a single leader among the processes sharing a key, e.g. to run
+// cron jobs on one pod only. The leader holds the session-level advisory lock of the
+// key (see AdvisoryLock) and checks its connection periodically: when the connection
+// is lost, so is the lock, and the elector steps down and competes again.
+//
+// Example:
+//
+//	elector, err := pgxx.NewLeaderElector(db, "cron:billing",
+//		pgxx.OnLeadershipChange(func(ctx context.Context, leader bool) {
+//			if leader {
+//				go scheduler.Run(ctx) // stops when ctx is canceled
+//			}
+//		}),
+//	)
+//	go elector.Run(ctx)
+type LeaderElector struct {
+	db     *rdbms
+	key    string
+	cfg    *leaderConfig
+	leader atomic.Bool
+	mu     sync.Mutex
+}
+
+// NewLeaderElector creates a LeaderElector for key on the pool of db.
+func NewLeaderElector(db RDBMS, key string, opts ...LeaderOption) (*LeaderElector, error) {
+	if key == "" {
+		return nil, errors.New("pgxx: leader election requires a key")
+	}
+
+	cfg := &leaderConfig{
+		retryInterval: defaultLeaderRetryInterval,
+		checkInterval: defaultLeaderCheckInterval,
+	}
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+
+	r, ok := db.(*rdbms)
+	if !ok {
+		r = &rdbms{db: db.GetDB()}
+	}
+	return &LeaderElector{db: r, key: key, cfg: cfg}, nil
+}
+
+// IsLeader reports whether this process currently holds the leadership.
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run competes for the leadership until ctx is canceled; it then steps down, releasing
+// the lock, and returns nil. Run must not be called concurrently on the same elector.
+func (e *LeaderElector) Run(ctx context.Context) error {
+	if !e.mu.TryLock() {
+		return errors.New("pgxx: leader elector is already running")
+	}
+	defer e.mu.Unlock()
+
+	for {
+		lock, acquired, err := e.db.TryAdvisoryLock(ctx, e.key)
+		if err != nil && ctx.Err() == nil {
+			observability.Start(ctx, zerolog.WarnLevel).Err(err).Str("key", e.key).Msg("[PGXX] leader election failed")
+		}
+		if acquired {
+			e.lead(ctx, lock)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(e.cfg.retryInterval):
+		}
+	}
+}
+
+// lead holds the leadership until ctx is canceled or the lock connection is lost.
+func (e *LeaderElector) lead(ctx context.Context, lock *AdvisoryLock) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	e.leader.Store(true)
+	e.notify(leaderCtx, true)
+
+	ticker := time.NewTicker(e.cfg.checkInterval)
+	defer ticker.Stop()
+	for lost := false; !lost; {
+		select {
+		case <-ctx.Done():
+			lost = true
+		case <-ticker.C:
+			pingCtx, cancelPing := context.WithTimeout(ctx, e.cfg.checkInterval)
+			if err := lock.Ping(pingCtx); err != nil {
+				if ctx.Err() == nil {
+					observability.Start(ctx, zerolog.WarnLevel).Err(err).Str("key", e.key).Msg("[PGXX] leader lost its lock connection")
+				}
+				lost = true
+			}
+			cancelPing()
+		}
+	}
+
+	e.leader.Store(false)
+	cancel()
+	e.notify(context.WithoutCancel(ctx), false)
+
+	unlockCtx, cancelUnlock := context.WithTimeout(context.WithoutCancel(ctx), e.cfg.checkInterval)
+	defer cancelUnlock()
+	_ = lock.Unlock(unlockCtx)
+}
+
+func (e *LeaderElector) notify(ctx context.Context, leader bool) {
+	for _, fn := range e.cfg.onChange {
+		fn(ctx, leader)
+	}
+}
@@ -0,0 +1,272 @@
+package pgxx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/observability"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const listenerTracerName = "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx"
+
+const (
+	defaultListenerMinBackoff = 100 * time.Millisecond
+	defaultListenerMaxBackoff = 30 * time.Second
+)
+
+// Notification is a NOTIFY message received by a Listener.
+type Notification struct {
+	Channel string
+	Payload string
+	PID     uint32 // Backend process ID of the notifying session
+
+	ctx context.Context
+}
+
+// Context returns the context of the notification, carrying its span and the values
+// added by hooks. It is context.Background() for a zero Notification.
+func (n Notification) Context() context.Context {
+	if n.ctx == nil {
+		return context.Background()
+	}
+	return n.ctx
+}
+
+// NotificationHandler handles a notification received by a Listener. ctx carries the
+// notification span. A returned error is reported to hooks and the span; it does not
+// stop the listener.
+type NotificationHandler func(ctx context.Context, n Notification) error
+
+// ListenerOption configures a Listener.
+type ListenerOption interface {
+	apply(*listenerConfig)
+}
+
+type listenerOptFunc func(*listenerConfig)
+
+func (o listenerOptFunc) apply(cfg *listenerConfig) {
+	o(cfg)
+}
+
+type listenerConfig struct {
+	backoff        databases.TxRetryPolicy
+	bufferSize     int
+	tracerProvider trace.TracerProvider
+}
+
+// WithListenerBackoff sets the jittered exponential backoff between reconnection
+// attempts, from minDelay up to maxDelay. Default: 100ms up to 30s.
+func WithListenerBackoff(minDelay, maxDelay time.Duration) ListenerOption {
+	return listenerOptFunc(func(cfg *listenerConfig) {
+		if minDelay > 0 {
+			cfg.backoff.BaseDelay = minDelay
+		}
+		if maxDelay > 0 {
+			cfg.backoff.MaxDelay = maxDelay
+		}
+	})
+}
+
+// WithListenerBufferSize sets the capacity of the channel returned by Listen. Default: 64.
+func WithListenerBufferSize(n int) ListenerOption {
+	return listenerOptFunc(func(cfg *listenerConfig) {
+		if n >= 0 {
+			cfg.bufferSize = n
+		}
+	})
+}
+
+// WithListenerTracerProvider sets the provider of the notification spans.
+// Default: otel.GetTracerProvider().
+func WithListenerTracerProvider(tp trace.TracerProvider) ListenerOption {
+	return listenerOptFunc(func(cfg *listenerConfig) {
+		cfg.tracerProvider = tp
+	})
+}
+
+// Listener receives PostgreSQL NOTIFY messages on a set of channels.
+//
+// It holds a dedicated connection acquired from the pool for as long as it runs, so
+// the pool must allow one more connection per listener. When the connection is lost,
+// the listener reconnects with backoff and LISTENs again; notifications sent while
+// it was disconnected are lost, so consumers should catch up (e.g. poll once) on
+// their own after an outage.
+//
+// Every notification is traced with a consumer span and reported to the RDBMS hooks
+// as OpNotification; the LISTEN statements are reported as OpListen.
+//
+// Example:
+//
+//	l, err := pgxx.NewListener(db, []string{"cache_invalidation"})
+//	go l.Run(ctx, func(ctx context.Context, n pgxx.Notification) error {
+//		cache.Delete(n.Payload)
+//		return nil
+//	})
+type Listener struct {
+	db       *rdbms
+	channels []string
+	cfg      *listenerConfig
+	tracer   trace.Tracer
+}
+
+// NewListener creates a Listener of channels on the pool of db. Hooks registered on
+// db (UseHook) also receive the listener operations.
+func NewListener(db RDBMS, channels []string, opts ...ListenerOption) (*Listener, error) {
+	if len(channels) == 0 {
+		return nil, errors.New("pgxx: listener requires at least one channel")
+	}
+
+	cfg := &listenerConfig{
+		backoff: databases.TxRetryPolicy{
+			BaseDelay: defaultListenerMinBackoff,
+			MaxDelay:  defaultListenerMaxBackoff,
+		},
+		bufferSize:     64,
+		tracerProvider: otel.GetTracerProvider(),
+	}
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+
+	r, ok := db.(*rdbms)
+	if !ok {
+		r = &rdbms{db: db.GetDB()}
+	}
+	return &Listener{
+		db:       r,
+		channels: channels,
+		cfg:      cfg,
+		tracer:   cfg.tracerProvider.Tracer(listenerTracerName),
+	}, nil
+}
+
+// Run listens and calls fn for every notification, sequentially, until ctx is
+// canceled; it then returns nil. Connection failures are logged and retried.
+func (l *Listener) Run(ctx context.Context, fn NotificationHandler) error {
+	attempt := 1
+	for {
+		connected, err := l.listen(ctx, fn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			attempt = 1
+		}
+		attempt++
+
+		observability.Start(ctx, zerolog.WarnLevel).Err(err).
+			Strs("channels", l.channels).
+			Int("attempt", attempt-1).
+			Msg("[PGXX] listener disconnected, reconnecting")
+		if err = l.cfg.backoff.Wait(ctx, attempt); err != nil {
+			return nil
+		}
+	}
+}
+
+// Listen runs the listener in a goroutine and delivers the notifications on the
+// returned channel, which is closed once ctx is canceled. The notification context
+// is available with Notification.Context. A slow reader blocks the listener, whose
+// pending notifications are then buffered by the connection.
+func (l *Listener) Listen(ctx context.Context) <-chan Notification {
+	ch := make(chan Notification, l.cfg.bufferSize)
+	go func() {
+		defer close(ch)
+		_ = l.Run(ctx, func(nctx context.Context, n Notification) error {
+			n.ctx = nctx
+			select {
+			case ch <- n:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return ch
+}
+
+// listen runs one connection: it LISTENs on every channel and delivers notifications
+// until the connection fails or ctx is canceled. connected reports whether the
+// LISTEN statements succeeded, to reset the backoff.
+func (l *Listener) listen(ctx context.Context, fn NotificationHandler) (connected bool, err error) {
+	conn, err := l.db.db.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	// The connection is not returned to the pool: it would keep listening.
+	defer closeConn(conn)
+
+	for _, channel := range l.channels {
+		if err = l.exec(ctx, conn, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return false, err
+		}
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		l.deliver(ctx, Notification{Channel: n.Channel, Payload: n.Payload, PID: n.PID}, fn)
+	}
+}
+
+// exec runs a LISTEN statement on conn, reported to hooks as OpListen.
+func (l *Listener) exec(ctx context.Context, conn *pgxpool.Conn, sql string) error {
+	info := &HookInfo{Op: OpListen, SQL: sql, Node: databases.NodePrimary, Start: time.Now()}
+	ctx, err := l.db.checkBefore(ctx, info)
+	if err == nil {
+		_, err = conn.Exec(ctx, sql)
+		info.Err = err
+	}
+	info.End = time.Now()
+	l.db.callAfter(ctx, info)
+	return err
+}
+
+// deliver calls fn for n within a consumer span, reported to hooks as OpNotification.
+func (l *Listener) deliver(ctx context.Context, n Notification, fn NotificationHandler) {
+	ctx, span := l.tracer.Start(ctx, "notification "+n.Channel,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("messaging.destination.name", n.Channel),
+			attribute.Int("messaging.message.body.size", len(n.Payload)),
+		),
+	)
+	defer span.End()
+
+	info := &HookInfo{
+		Op:    OpNotification,
+		SQL:   "NOTIFY " + pgx.Identifier{n.Channel}.Sanitize(),
+		Args:  []any{n.Payload},
+		Node:  databases.NodePrimary,
+		Start: time.Now(),
+	}
+	ctx = l.db.callBefore(ctx, info)
+	info.Err = fn(ctx, n)
+	info.End = time.Now()
+	l.db.callAfter(ctx, info)
+
+	if info.Err != nil {
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+	}
+}
+
+// Notify sends a notification with payload on channel (pg_notify). Inside a
+// transaction (s or ctx carrying one, see DoTxContext) it is delivered on commit,
+// and not at all on rollback. Payloads are limited to 8000 bytes by PostgreSQL.
+func (s *rdbms) Notify(ctx context.Context, channel, payload string) error {
+	_, err := s.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+	return err
+}
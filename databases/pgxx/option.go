@@ -3,6 +3,7 @@ package pgxx
 import (
 	"time"
 
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/observability/otelpgx"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -14,8 +15,12 @@ type Option interface {
 type optFunc func(*rdbmsConfig)
 
 type rdbmsConfig struct {
-	pool  *pgxpool.Config
-	hooks []DBHook
+	pool          *pgxpool.Config
+	hooks         []DBHook
+	cursorSecret  string
+	replicaConfig databases.ReplicaRouterConfig
+	txRetry       databases.TxRetryPolicy
+	tenantRLS     *databases.TenantRLS
 }
 
 func defaultConfig(pool *pgxpool.Config) *rdbmsConfig {
@@ -49,6 +54,53 @@ func UseHook(h ...DBHook) Option {
 	})
 }
 
+// WithCursorSecret sets the secret used to sign and verify the opaque cursors
+// produced by QuerySqCursorPagination. Cursor pagination fails without it.
+func WithCursorSecret(secret string) Option {
+	return optFunc(func(cfg *rdbmsConfig) {
+		cfg.cursorSecret = secret
+	})
+}
+
+// WithReplicaPolicy sets how NewRoutingRDBMS selects a replica for reads.
+// Default: databases.ReplicaRoundRobin.
+func WithReplicaPolicy(policy databases.ReplicaPolicy) Option {
+	return optFunc(func(cfg *rdbmsConfig) {
+		cfg.replicaConfig.Policy = policy
+	})
+}
+
+// WithReplicaHealthCheck configures replica health-based ejection for NewRoutingRDBMS.
+// Replicas are pinged every interval and ejected when a ping fails or after maxFailures
+// consecutive connection errors. Defaults: 5s interval, 3 failures.
+func WithReplicaHealthCheck(interval time.Duration, maxFailures int) Option {
+	return optFunc(func(cfg *rdbmsConfig) {
+		cfg.replicaConfig.HealthCheckInterval = interval
+		cfg.replicaConfig.MaxFailures = maxFailures
+	})
+}
+
+// WithTxRetry enables automatic retries of top-level DoTx/DoTxContext transactions that
+// fail with a retryable SQLSTATE, such as 40001 (serialization failure) or 40P01 (deadlock).
+// See databases.TxRetryPolicy for the defaults.
+func WithTxRetry(policy databases.TxRetryPolicy) Option {
+	return optFunc(func(cfg *rdbmsConfig) {
+		cfg.txRetry = policy
+	})
+}
+
+// WithTenantRLS applies the tenant of the context (databases.WithTenant) at the beginning
+// of every transaction so PostgreSQL row-level security policies can enforce it.
+// Statements outside a transaction fail closed with databases.ErrMissingTenant or
+// databases.ErrTenantTxRequired, without reaching the server.
+// A transaction begun without a tenant or databases.WithTenantBypass fails with
+// databases.ErrMissingTenant. See databases.TenantRLS for the settings and policies.
+func WithTenantRLS(rls databases.TenantRLS) Option {
+	return optFunc(func(cfg *rdbmsConfig) {
+		cfg.tenantRLS = &rls
+	})
+}
+
 // UseDebug enables a simple SQL log hook.
 func UseDebug(withArgs bool) Option {
 	return UseHook(&DebugHook{WithArgs: withArgs})
@@ -92,3 +144,15 @@ func WithObservabilitySlowThreshold(d time.Duration) ObservabilityHookOption {
 		h.SlowThreshold = d
 	}
 }
+
+// WithObservabilityExplain captures the plan of sampled slow SELECTs: an EXPLAIN
+// (FORMAT JSON) runs asynchronously on a separate pool connection, and the slow query
+// log entry is emitted once it completes, with the plan summary (cost, estimated rows,
+// sequential scans). The EXPLAIN and the summary are also recorded on a "db.explain"
+// span, child of the span of the query context. See databases.ExplainConfig for the
+// sampling, rate limit and denylist.
+func WithObservabilityExplain(cfg databases.ExplainConfig) ObservabilityHookOption {
+	return func(h *ObservabilityHook) {
+		h.Explain = &cfg
+	}
+}
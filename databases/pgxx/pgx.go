@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -21,12 +23,40 @@ type rdbms struct {
 	db    *pgxpool.Pool
 	hooks []DBHook
 	queryExecutor
-	isTx bool
+	isTx         bool
+	cursorSecret string
+	replicas     *databases.ReplicaRouter[*pgxpool.Pool]
+	txRetry      databases.TxRetryPolicy
+	tenantRLS    *databases.TenantRLS
+	explainers   map[DBHook]*explainer
+
+	// savepointDepth is the nesting level of DoTxContext calls inside the transaction.
+	savepointDepth int
+
+	// txCallbacks collects AfterCommit / AfterRollback callbacks of the transaction
+	// scope s belongs to; nil outside a transaction.
+	txCallbacks *databases.TxCallbacks
 }
 
 // NewRDBMS creates a new RDBMS backed by a pgx connection pool.
 func NewRDBMS(conn string, opts ...Option) (*rdbms, func(), error) {
-	poolCfg, err := pgxpool.ParseConfig(conn)
+	return NewRoutingRDBMS(conn, nil, opts...)
+}
+
+// NewRoutingRDBMS creates an RDBMS that sends writes and transactions to the primary
+// pool and spreads Query/QueryRow (and the Squirrel variants) across replica pools.
+//
+// Routing rules:
+//   - Everything inside DoTx/DoTxContext is served by the primary.
+//   - Reads go to the primary when no replica is healthy, when ctx was marked with
+//     databases.UsePrimary, or after a write within a databases.WithReadYourWrites context.
+//   - Replicas failing health checks (see WithReplicaHealthCheck) are ejected until they recover.
+//
+// Replica pools share the pgx tracer configured by WithOtel. The node that served each
+// operation is reported in HookInfo.Node. The returned cleanup function stops the health
+// checker and closes every pool.
+func NewRoutingRDBMS(primaryConn string, replicaConns []string, opts ...Option) (*rdbms, func(), error) {
+	poolCfg, err := pgxpool.ParseConfig(primaryConn)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create connection pool: %w", err)
 	}
@@ -36,54 +66,164 @@ func NewRDBMS(conn string, opts ...Option) (*rdbms, func(), error) {
 		o.apply(internalCfg)
 	}
 
+	replicaPools := make([]*pgxpool.Pool, 0, len(replicaConns))
+	closeReplicas := func() {
+		for _, p := range replicaPools {
+			p.Close()
+		}
+	}
+	for i, conn := range replicaConns {
+		replicaCfg, err := pgxpool.ParseConfig(conn)
+		if err != nil {
+			closeReplicas()
+			return nil, nil, fmt.Errorf("create replica-%d connection pool: %w", i, err)
+		}
+		replicaCfg.ConnConfig.Tracer = internalCfg.pool.ConnConfig.Tracer
+
+		pool, err := pgxpool.NewWithConfig(context.Background(), replicaCfg)
+		if err != nil {
+			closeReplicas()
+			return nil, nil, fmt.Errorf("connect to replica-%d database: %w", i, err)
+		}
+		replicaPools = append(replicaPools, pool)
+	}
+
 	db, err := pgxpool.NewWithConfig(context.Background(), internalCfg.pool)
 	if err != nil {
+		closeReplicas()
 		return nil, nil, fmt.Errorf("connect to database: %w", err)
 	}
 
-	return &rdbms{
+	r := &rdbms{
 		db:            db,
 		queryExecutor: db,
 		hooks:         internalCfg.hooks,
-	}, db.Close, nil
+		cursorSecret:  internalCfg.cursorSecret,
+		txRetry:       internalCfg.txRetry,
+		tenantRLS:     internalCfg.tenantRLS,
+	}
+	r.explainers = newExplainers(r.hooks, db)
+	if len(replicaPools) == 0 {
+		return r, db.Close, nil
+	}
+
+	nodes := make([]*databases.ReplicaNode[*pgxpool.Pool], len(replicaPools))
+	for i, p := range replicaPools {
+		nodes[i] = &databases.ReplicaNode[*pgxpool.Pool]{DB: p}
+	}
+	r.replicas = databases.NewReplicaRouter(nodes, internalCfg.replicaConfig)
+	stopHealth := r.replicas.StartHealthCheck(func(ctx context.Context, db *pgxpool.Pool) error {
+		return db.Ping(ctx)
+	})
+
+	return r, func() {
+		stopHealth()
+		closeReplicas()
+		db.Close()
+	}, nil
+}
+
+// readExecutor selects the executor that serves a query. Inside a transaction it is
+// always the transaction; otherwise a healthy replica may be selected for a routing RDBMS.
+// A query that writes (e.g. INSERT ... RETURNING) is served by the primary and marks
+// the read-your-writes session of ctx.
+// It returns a nil replica node when the primary (or transaction) is selected.
+func (s *rdbms) readExecutor(ctx context.Context, sql string) (queryExecutor, *databases.ReplicaNode[*pgxpool.Pool]) {
+	if databases.IsWriteStatement(databases.DialectPostgres, sql) {
+		databases.MarkWrite(ctx)
+		return s.queryExecutor, nil
+	}
+	if s.isTx || s.replicas == nil || databases.ShouldReadPrimary(ctx) {
+		return s.queryExecutor, nil
+	}
+	node, ok := s.replicas.Pick()
+	if !ok {
+		return s.queryExecutor, nil
+	}
+	return node.DB, node
 }
 
 // newRDBMSWithExecutor creates a transactional RDBMS that uses the given executor.
-// It is intended for use inside transactions (e.g., pgx.Tx).
-func newRDBMSWithExecutor(db *pgxpool.Pool, executor queryExecutor, hooks []DBHook) *rdbms {
+// It is intended for use inside transactions (e.g., pgx.Tx) at the given savepoint depth.
+func (s *rdbms) newRDBMSWithExecutor(executor queryExecutor, savepointDepth int) *rdbms {
 	return &rdbms{
-		db:            db,
-		queryExecutor: executor,
-		hooks:         hooks,
-		isTx:          true,
+		db:             s.db,
+		queryExecutor:  executor,
+		hooks:          s.hooks,
+		isTx:           true,
+		cursorSecret:   s.cursorSecret,
+		tenantRLS:      s.tenantRLS,
+		explainers:     s.explainers,
+		savepointDepth: savepointDepth,
 	}
 }
 
 func (s *rdbms) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
-	info := &HookInfo{Op: OpQuery, SQL: sql, Args: args, InTx: s.isTx, Start: time.Now()}
-	ctx = s.callBefore(ctx, info)
+	if tx, ok := s.ambientTx(ctx); ok {
+		return tx.Query(ctx, sql, args...)
+	}
 
-	rows, err := s.queryExecutor.Query(ctx, sql, args...)
+	executor, node := s.readExecutor(ctx, sql)
+	info := &HookInfo{Op: OpQuery, SQL: sql, Args: args, InTx: s.isTx, Node: databases.NodePrimary, Start: time.Now()}
+	if node != nil {
+		info.Node = node.Name
+	}
+	ctx, err := s.checkBefore(ctx, info)
+	if err != nil {
+		info.End = time.Now()
+		s.callAfter(ctx, info)
+		return nil, err
+	}
+
+	rows, err := executor.Query(ctx, sql, args...)
 	info.Err = err
 	info.End = time.Now()
+	s.replicas.Observe(node, info.End.Sub(info.Start), err)
 	s.callAfter(ctx, info)
 	return rows, err
 }
 
 func (s *rdbms) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
-	info := &HookInfo{Op: OpQueryRow, SQL: sql, Args: args, InTx: s.isTx, Start: time.Now()}
-	ctx = s.callBefore(ctx, info)
+	if tx, ok := s.ambientTx(ctx); ok {
+		return tx.QueryRow(ctx, sql, args...)
+	}
+
+	executor, node := s.readExecutor(ctx, sql)
+	info := &HookInfo{Op: OpQueryRow, SQL: sql, Args: args, InTx: s.isTx, Node: databases.NodePrimary, Start: time.Now()}
+	if node != nil {
+		info.Node = node.Name
+	}
+	ctx, err := s.checkBefore(ctx, info)
 	defer func() {
 		info.End = time.Now()
 		s.callAfter(ctx, info)
 	}()
+	if err != nil {
+		return errRow{err: err}
+	}
 
-	return s.queryExecutor.QueryRow(ctx, sql, args...)
+	row := executor.QueryRow(ctx, sql, args...)
+	if node == nil {
+		return row
+	}
+	return &observedRow{Row: row, observe: func(err error) {
+		s.replicas.Observe(node, time.Since(info.Start), err)
+	}}
 }
 
 func (s *rdbms) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
-	info := &HookInfo{Op: OpExec, SQL: sql, Args: arguments, InTx: s.isTx, Start: time.Now()}
-	ctx = s.callBefore(ctx, info)
+	if tx, ok := s.ambientTx(ctx); ok {
+		return tx.Exec(ctx, sql, arguments...)
+	}
+
+	info := &HookInfo{Op: OpExec, SQL: sql, Args: arguments, InTx: s.isTx, Node: databases.NodePrimary, Start: time.Now()}
+	databases.MarkWrite(ctx)
+	ctx, err := s.checkBefore(ctx, info)
+	if err != nil {
+		info.End = time.Now()
+		s.callAfter(ctx, info)
+		return pgconn.CommandTag{}, err
+	}
 
 	tag, err := s.queryExecutor.Exec(ctx, sql, arguments...)
 	info.Err = err
@@ -122,6 +262,35 @@ func (s *rdbms) ExecSq(ctx context.Context, query squirrel.Sqlizer) (pgconn.Comm
 	return s.Exec(ctx, rawQuery, args...)
 }
 
+// UpdateWithVersion executes update.UpdateQuery, which sets the columns and increments
+// the version only while it still equals update.Version, and returns the new version.
+// A zero-row update is followed by an existence check: a stale version returns an
+// apperror.Conflict (cause databases.ErrVersionConflict), a missing row an
+// apperror.NotFound (cause databases.ErrNoUpdateRow).
+func (s *rdbms) UpdateWithVersion(ctx context.Context, update databases.VersionedUpdate) (databases.Version, error) {
+	tag, err := s.ExecSq(ctx, update.UpdateQuery(databases.DialectPostgres))
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() > 0 {
+		return update.Version.Next(), nil
+	}
+
+	// The existence check must see the latest state, never a lagging replica.
+	row, err := s.QueryRowSq(databases.UsePrimary(ctx), update.ExistsQuery(databases.DialectPostgres))
+	if err != nil {
+		return 0, err
+	}
+	var exists int
+	if err = row.Scan(&exists); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, update.NoRowsError(false)
+		}
+		return 0, err
+	}
+	return 0, update.NoRowsError(true)
+}
+
 // QueryRowSq executes a SELECT query built with Squirrel and returns a single row.
 func (s *rdbms) QueryRowSq(ctx context.Context, query squirrel.Sqlizer) (pgx.Row, error) {
 	rawQuery, args, err := query.ToSql()
@@ -135,6 +304,11 @@ func (s *rdbms) GetDB() *pgxpool.Pool {
 	return s.db
 }
 
+// Dialect returns databases.DialectPostgres, for configuring dialect-aware filters.
+func (s *rdbms) Dialect() databases.Dialect {
+	return databases.DialectPostgres
+}
+
 // QuerySqPagination executes a paginated SELECT query built with Squirrel.
 func (s *rdbms) QuerySqPagination(
 	ctx context.Context,
@@ -166,14 +340,154 @@ func (s *rdbms) QuerySqPagination(
 	return primitive.CreatePaginationOutput(paginationInput, totalData), nil
 }
 
+// QuerySqCursorPagination executes a keyset (cursor) paginated SELECT query built with Squirrel.
+// The keyset predicate, ORDER BY and LIMIT are applied to query; countQuery is only
+// executed when paginationInput.WithTotal is true. fn is called once per row and must
+// Scan it and return the keyset column values used to build the signed cursors.
+// When paging backward (output.Reversed), rows are delivered in reverse order.
+func (s *rdbms) QuerySqCursorPagination(
+	ctx context.Context,
+	countQuery, query squirrel.SelectBuilder,
+	keyset primitive.Keyset,
+	paginationInput primitive.CursorPaginationInput,
+	fn func(rows pgx.Rows) ([]any, error),
+) (primitive.CursorPaginationOutput, error) {
+	if paginationInput.PageSize <= 0 {
+		paginationInput.PageSize = 20
+	}
+
+	cursor, err := primitive.DecodeCursor(keyset, paginationInput.Cursor, s.cursorSecret)
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+
+	query, err = keyset.BuildSquirrel(query, cursor, paginationInput.PageSize)
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+
+	var totalData *int64
+	if paginationInput.WithTotal {
+		total := int64(0)
+		row, err := s.QueryRowSq(ctx, countQuery)
+		if err != nil {
+			return primitive.CursorPaginationOutput{}, err
+		}
+		if err := row.Scan(&total); err != nil {
+			return primitive.CursorPaginationOutput{}, err
+		}
+		totalData = &total
+	}
+
+	var (
+		keys    = make([][]any, 0, paginationInput.PageSize)
+		hasMore bool
+	)
+	err = s.QuerySq(ctx, query, func(rows pgx.Rows) error {
+		for rows.Next() {
+			if int64(len(keys)) == paginationInput.PageSize {
+				hasMore = true
+				break
+			}
+			key, err := fn(rows)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, key)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+
+	output, err := primitive.NewCursorPaginationOutput(keyset, paginationInput, cursor, keys, hasMore, s.cursorSecret)
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+	output.TotalData = totalData
+	return output, nil
+}
+
 // DoTx executes a function within a database transaction.
-func (s *rdbms) DoTx(ctx context.Context, opt pgx.TxOptions, fn func(tx RDBMS) error) (err error) {
+// It is like DoTxContext, but fn does not receive the transactional context.
+func (s *rdbms) DoTx(ctx context.Context, opt pgx.TxOptions, fn func(tx RDBMS) error) error {
+	return s.DoTxContext(ctx, opt, func(_ context.Context, tx RDBMS) error {
+		return fn(tx)
+	})
+}
+
+// DoTxContext is like DoTx, but passes ctx along to the transactional function.
+// If s is already inside a transaction, or ctx carries an ambient transaction for this
+// pool, fn is executed as a nested transaction inside a SAVEPOINT of that transaction:
+// if fn fails, only its work is rolled back (ROLLBACK TO SAVEPOINT) and the outer
+// transaction continues. Savepoint events are surfaced via hooks (OpSavepoint,
+// OpRollbackToSavepoint, OpReleaseSavepoint).
+//
+// The ctx passed to fn carries the transaction, so any call made with it on the base
+// RDBMS (e.g. repositories holding the non-transactional RDBMS) transparently joins
+// the transaction. Use WithoutTx to opt out for a specific call. Callbacks registered
+// with it via databases.AfterCommit / databases.AfterRollback run once the transaction
+// has ended (OpAfterCommit, OpAfterRollback).
+//
+// With WithTxRetry, a top-level transaction failing with a retryable SQLSTATE
+// (40001 serialization failure, 40P01 deadlock by default) is rolled back and fn is
+// re-run on a fresh transaction after a jittered backoff. The attempt number is reported
+// in HookInfo.Attempt and as a span attribute; if the last attempt still fails, the error
+// is a *databases.TxRetryError carrying the number of attempts.
+func (s *rdbms) DoTxContext(
+	ctx context.Context,
+	opt pgx.TxOptions,
+	fn func(ctx context.Context, tx RDBMS) error,
+) (err error) {
+	if s.isTx {
+		return s.doSavepoint(ctx, fn)
+	}
+	if tx, ok := s.ambientTx(ctx); ok {
+		return tx.doSavepoint(ctx, fn)
+	}
+
 	if opt.IsoLevel == "" {
 		opt = pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite}
 	}
+	if opt.AccessMode != pgx.ReadOnly {
+		databases.MarkWrite(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = s.doTx(ctx, opt, fn, attempt)
+		if err == nil || attempt >= s.txRetry.MaxAttempts || !s.txRetry.IsRetryable(err) {
+			if err != nil && attempt > 1 {
+				err = &databases.TxRetryError{Attempts: attempt, Err: err}
+			}
+			return err
+		}
+
+		if errWait := s.txRetry.Wait(ctx, attempt+1); errWait != nil {
+			return &databases.TxRetryError{Attempts: attempt, Err: errors.Join(err, errWait)}
+		}
+	}
+}
 
-	beg := &HookInfo{Op: OpTxBegin, InTx: true}
-	ctx = s.callBefore(ctx, beg)
+// doTx runs a single attempt of a top-level transaction for DoTxContext.
+func (s *rdbms) doTx(
+	ctx context.Context,
+	opt pgx.TxOptions,
+	fn func(ctx context.Context, tx RDBMS) error,
+	attempt int,
+) (err error) {
+	ctx = databases.WithTxAttempt(ctx, attempt)
+	// After-commit/rollback callbacks run on the caller's context, not on the one
+	// returned by the TX_BEGIN hooks (e.g. a span that is ended by then).
+	outer := ctx
+
+	beg := &HookInfo{Op: OpTxBegin, InTx: true, Node: databases.NodePrimary, Attempt: attempt}
+	ctx, err = s.checkBefore(ctx, beg)
+	if err != nil {
+		beg.End = time.Now()
+		s.callAfter(ctx, beg)
+		return err
+	}
 	tx, err := s.db.BeginTx(ctx, opt)
 	beg.Err = err
 	beg.End = time.Now()
@@ -182,18 +496,22 @@ func (s *rdbms) DoTx(ctx context.Context, opt pgx.TxOptions, fn func(tx RDBMS) e
 		return err
 	}
 
+	child := s.newRDBMSWithExecutor(tx, 0)
+	child.txCallbacks = databases.NewTxCallbacks()
+
 	defer func() {
 		if p := recover(); p != nil {
-			roll := &HookInfo{Op: OpTxRollback, InTx: true, Err: fmt.Errorf("panic: %v", p)}
+			roll := &HookInfo{Op: OpTxRollback, InTx: true, Node: databases.NodePrimary, Attempt: attempt, Err: fmt.Errorf("panic: %v", p)}
 			ctx = s.callBefore(ctx, roll)
 			_ = tx.Rollback(ctx)
 			roll.End = time.Now()
 			s.callAfter(ctx, roll)
+			s.runTxCallbacks(outer, OpAfterRollback, child.txCallbacks.OnRollback(), attempt)
 			panic(p)
 		}
 
 		if err != nil {
-			roll := &HookInfo{Op: OpTxRollback, InTx: true, Err: err}
+			roll := &HookInfo{Op: OpTxRollback, InTx: true, Node: databases.NodePrimary, Attempt: attempt, Err: err}
 			ctx = s.callBefore(ctx, roll)
 			if errRollback := tx.Rollback(ctx); errRollback != nil && !errors.Is(err, sql.ErrTxDone) {
 				err = errors.Join(err, errRollback)
@@ -201,10 +519,11 @@ func (s *rdbms) DoTx(ctx context.Context, opt pgx.TxOptions, fn func(tx RDBMS) e
 			roll.Err = err
 			roll.End = time.Now()
 			s.callAfter(ctx, roll)
+			s.runTxCallbacks(outer, OpAfterRollback, child.txCallbacks.OnRollback(), attempt)
 			return
 		}
 
-		cm := &HookInfo{Op: OpTxCommit, InTx: true}
+		cm := &HookInfo{Op: OpTxCommit, InTx: true, Node: databases.NodePrimary, Attempt: attempt}
 		ctx = s.callBefore(ctx, cm)
 		if errCommit := tx.Commit(ctx); errCommit != nil && !errors.Is(errCommit, sql.ErrTxDone) {
 			err = errors.Join(err, errCommit)
@@ -212,75 +531,135 @@ func (s *rdbms) DoTx(ctx context.Context, opt pgx.TxOptions, fn func(tx RDBMS) e
 		cm.Err = err
 		cm.End = time.Now()
 		s.callAfter(ctx, cm)
+
+		if err != nil {
+			s.runTxCallbacks(outer, OpAfterRollback, child.txCallbacks.OnRollback(), attempt)
+			return
+		}
+		s.runTxCallbacks(outer, OpAfterCommit, child.txCallbacks.OnCommit(), attempt)
 	}()
 
-	return fn(newRDBMSWithExecutor(s.db, tx, s.hooks))
+	if err = child.applyTenant(ctx); err != nil {
+		return err
+	}
+	return fn(databases.WithTxCallbacks(withTx(ctx, child), child.txCallbacks), child)
 }
 
-// DoTxContext is like DoTx, but passes ctx along to the transactional function.
-func (s *rdbms) DoTxContext(
-	ctx context.Context,
-	opt pgx.TxOptions,
-	fn func(ctx context.Context, tx RDBMS) error,
-) (err error) {
-	if opt.IsoLevel == "" {
-		opt = pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite}
+// applyTenant exposes the tenant of ctx to row-level security policies for the rest
+// of the transaction, see WithTenantRLS. It is a no-op without WithTenantRLS.
+func (s *rdbms) applyTenant(ctx context.Context) error {
+	if s.tenantRLS == nil {
+		return nil
 	}
-
-	beg := &HookInfo{Op: OpTxBegin, InTx: true}
-	ctx = s.callBefore(ctx, beg)
-	tx, err := s.db.BeginTx(ctx, opt)
-	beg.Err = err
-	beg.End = time.Now()
-	s.callAfter(ctx, beg)
+	stmt, args, err := s.tenantRLS.Statement(ctx, databases.DialectPostgres)
 	if err != nil {
 		return err
 	}
+	stmt = databases.QuestionToDollar(stmt)
+
+	info := &HookInfo{Op: OpExec, SQL: stmt, Args: args, InTx: true, Node: databases.NodePrimary, Start: time.Now()}
+	ctx, err = s.checkBefore(ctx, info)
+	if err == nil {
+		_, err = s.queryExecutor.Exec(ctx, stmt, args...)
+	}
+	info.Err = err
+	info.End = time.Now()
+	s.callAfter(ctx, info)
+	return err
+}
+
+// doSavepoint runs fn as a nested transaction inside a SAVEPOINT of the current transaction.
+//
+// Behavior:
+//   - Panic inside fn: rolled back to the savepoint, AfterRollback callbacks of the
+//     savepoint run, then panic is rethrown.
+//   - fn returns error: rolled back to the savepoint, AfterRollback callbacks of the
+//     savepoint run, error is returned; the outer transaction stays usable.
+//   - fn returns nil: the savepoint is released and its callbacks move to the
+//     enclosing scope, waiting for its outcome.
+func (s *rdbms) doSavepoint(ctx context.Context, fn func(ctx context.Context, tx RDBMS) error) (err error) {
+	child := s.newRDBMSWithExecutor(s.queryExecutor, s.savepointDepth+1)
+	child.txCallbacks = databases.NewTxCallbacks()
+	name := "sp_" + strconv.Itoa(child.savepointDepth)
+
+	if err = s.execSavepoint(ctx, OpSavepoint, "SAVEPOINT "+name, nil); err != nil {
+		return err
+	}
 
 	defer func() {
 		if p := recover(); p != nil {
-			roll := &HookInfo{Op: OpTxRollback, InTx: true, Err: fmt.Errorf("panic: %v", p)}
-			ctx = s.callBefore(ctx, roll)
-			_ = tx.Rollback(ctx)
-			roll.End = time.Now()
-			s.callAfter(ctx, roll)
+			_ = s.execSavepoint(ctx, OpRollbackToSavepoint, "ROLLBACK TO SAVEPOINT "+name, fmt.Errorf("panic: %v", p))
+			s.runTxCallbacks(ctx, OpAfterRollback, child.txCallbacks.OnRollback(), 0)
 			panic(p)
 		}
 
 		if err != nil {
-			roll := &HookInfo{Op: OpTxRollback, InTx: true, Err: err}
-			ctx = s.callBefore(ctx, roll)
-			if errRollback := tx.Rollback(ctx); errRollback != nil && !errors.Is(err, sql.ErrTxDone) {
+			if errRollback := s.execSavepoint(ctx, OpRollbackToSavepoint, "ROLLBACK TO SAVEPOINT "+name, err); errRollback != nil {
 				err = errors.Join(err, errRollback)
 			}
-			roll.Err = err
-			roll.End = time.Now()
-			s.callAfter(ctx, roll)
+			s.runTxCallbacks(ctx, OpAfterRollback, child.txCallbacks.OnRollback(), 0)
 			return
 		}
 
-		cm := &HookInfo{Op: OpTxCommit, InTx: true}
-		ctx = s.callBefore(ctx, cm)
-		if errCommit := tx.Commit(ctx); errCommit != nil && !errors.Is(errCommit, sql.ErrTxDone) {
-			err = errors.Join(err, errCommit)
+		if err = s.execSavepoint(ctx, OpReleaseSavepoint, "RELEASE SAVEPOINT "+name, nil); err == nil && s.txCallbacks != nil {
+			child.txCallbacks.MergeInto(s.txCallbacks)
 		}
-		cm.Err = err
-		cm.End = time.Now()
-		s.callAfter(ctx, cm)
 	}()
 
-	return fn(ctx, newRDBMSWithExecutor(s.db, tx, s.hooks))
+	return fn(databases.WithTxCallbacks(withTx(ctx, child), child.txCallbacks), child)
 }
 
-func (s *rdbms) callBefore(ctx context.Context, info *HookInfo) context.Context {
-	for _, h := range s.hooks {
-		ctx = h.Before(ctx, info)
+// runTxCallbacks runs the AfterCommit or AfterRollback callbacks of a finished
+// transaction scope in registration order, surfacing each via hooks (op).
+// A failing or panicking callback is reported on HookInfo.Err and does not stop the others.
+func (s *rdbms) runTxCallbacks(ctx context.Context, op Op, callbacks []databases.TxCallback, attempt int) {
+	for _, fn := range callbacks {
+		info := &HookInfo{Op: op, InTx: s.isTx, Node: databases.NodePrimary, Attempt: attempt, Start: time.Now()}
+		cbCtx := s.callBefore(ctx, info)
+		info.Err = databases.RunTxCallback(cbCtx, fn)
+		info.End = time.Now()
+		s.callAfter(cbCtx, info)
 	}
+}
+
+// execSavepoint executes a savepoint statement on the current transaction, surfacing it via hooks.
+// cause is the error that triggered a rollback, reported on HookInfo.Err.
+func (s *rdbms) execSavepoint(ctx context.Context, op Op, stmt string, cause error) error {
+	info := &HookInfo{Op: op, SQL: stmt, InTx: true, Node: databases.NodePrimary, Start: time.Now()}
+	ctx, err := s.checkBefore(ctx, info)
+	if err != nil {
+		info.End = time.Now()
+		s.callAfter(ctx, info)
+		return err
+	}
+
+	_, err = s.queryExecutor.Exec(ctx, stmt)
+
+	info.Err = cause
+	if err != nil {
+		info.Err = errors.Join(cause, err)
+	}
+	info.End = time.Now()
+	s.callAfter(ctx, info)
+	return err
+}
+
+// callBefore runs DBHook.Before in registration order, threading context through.
+// Policy hooks are not checked; it is used for operations that cannot be vetoed.
+func (s *rdbms) callBefore(ctx context.Context, info *HookInfo) context.Context {
+	ctx, _ = s.runBefore(ctx, info, false)
 	return ctx
 }
 
+// checkBefore is callBefore for operations a PolicyHook may veto. On veto the error is
+// returned and recorded on info; the caller must skip the server call but still call callAfter.
+func (s *rdbms) checkBefore(ctx context.Context, info *HookInfo) (context.Context, error) {
+	return s.runBefore(ctx, info, vetoableOps[info.Op])
+}
+
+// callAfter runs DBHook.After in reverse registration order, for the hooks whose Before ran.
 func (s *rdbms) callAfter(ctx context.Context, info *HookInfo) {
-	for _, h := range s.hooks {
-		h.After(ctx, info)
+	for i := info.entered - 1; i >= 0; i-- {
+		s.hooks[i].After(ctx, info)
 	}
 }
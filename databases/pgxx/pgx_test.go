@@ -0,0 +1,92 @@
+package pgxx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// nodeHook records the node that served each operation.
+type nodeHook struct {
+	mu    sync.Mutex
+	nodes []string
+}
+
+func (h *nodeHook) Before(ctx context.Context, info *HookInfo) context.Context { return ctx }
+
+func (h *nodeHook) After(ctx context.Context, info *HookInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nodes = append(h.nodes, info.Node)
+}
+
+// newUnreachableRDBMS creates a routing RDBMS whose primary and replica refuse connections,
+// so that routing can be observed without a database server.
+func newUnreachableRDBMS(t *testing.T, opts ...Option) *rdbms {
+	t.Helper()
+	r, cleanup, err := NewRoutingRDBMS(
+		"postgres://app@127.0.0.1:1/primary?connect_timeout=1",
+		[]string{"postgres://app@127.0.0.1:1/replica?connect_timeout=1"},
+		opts...,
+	)
+	if err != nil {
+		t.Fatalf("NewRoutingRDBMS() error = %v", err)
+	}
+	t.Cleanup(cleanup)
+	return r
+}
+
+func TestRDBMS_QueryRowReturningUsesPrimary(t *testing.T) {
+	hook := &nodeHook{}
+	r := newUnreachableRDBMS(t, UseHook(hook))
+	ctx := databases.WithReadYourWrites(context.Background())
+
+	var id int64
+	_ = r.QueryRow(ctx, "SELECT id FROM users WHERE id = $1", 1).Scan(&id)
+	if databases.ShouldReadPrimary(ctx) {
+		t.Fatalf("ShouldReadPrimary() after a SELECT = true, want false")
+	}
+	_ = r.QueryRow(ctx, "INSERT INTO users (name) VALUES ($1) RETURNING id", "rama").Scan(&id)
+	if !databases.ShouldReadPrimary(ctx) {
+		t.Fatalf("ShouldReadPrimary() after INSERT ... RETURNING = false, want true")
+	}
+
+	if want := []string{"replica-0", databases.NodePrimary}; !reflect.DeepEqual(hook.nodes, want) {
+		t.Fatalf("nodes = %v, want %v", hook.nodes, want)
+	}
+}
+
+func TestRDBMS_QueryRowObservesReplica(t *testing.T) {
+	r := newUnreachableRDBMS(t, WithReplicaHealthCheck(time.Hour, 1))
+
+	var id int64
+	if err := r.QueryRow(context.Background(), "SELECT id FROM users WHERE id = $1", 1).Scan(&id); err == nil {
+		t.Fatalf("Scan() on an unreachable replica: want error")
+	}
+	if node := r.replicas.Nodes()[0]; node.Healthy() {
+		t.Fatalf("replica healthy after a connection error, want ejected")
+	}
+}
+
+func TestRDBMS_TenantRLSOutsideTx(t *testing.T) {
+	hook := &nodeHook{}
+	r := newUnreachableRDBMS(t, UseHook(hook), WithTenantRLS(databases.TenantRLS{}))
+
+	var id int64
+	err := r.QueryRow(context.Background(), "SELECT id FROM orders").Scan(&id)
+	if !errors.Is(err, databases.ErrMissingTenant) {
+		t.Fatalf("QueryRow() without tenant error = %v, want ErrMissingTenant", err)
+	}
+	ctx := databases.WithTenant(context.Background(), "acme")
+	if _, err = r.Exec(ctx, "DELETE FROM orders WHERE id = 1"); !errors.Is(err, databases.ErrTenantTxRequired) {
+		t.Fatalf("Exec() outside a transaction error = %v, want ErrTenantTxRequired", err)
+	}
+	if len(hook.nodes) != 2 {
+		t.Fatalf("hooks ran for %d operations, want 2 vetoed operations", len(hook.nodes))
+	}
+}
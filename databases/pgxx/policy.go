@@ -0,0 +1,187 @@
+package pgxx
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// PolicyHook is a DBHook that can veto operations before they reach the server.
+//
+// Check is called right after the hook's own Before, with the context it returned.
+// A non-nil error aborts the operation: remaining hooks are skipped, HookInfo.Err and
+// HookInfo.Vetoed are set, the After of the hooks already entered is still called,
+// and the error is returned to the caller (or reported by Scan for QueryRow, and by
+// the BatchReader for SendBatch).
+//
+// Commit, rollback and RELEASE / ROLLBACK TO SAVEPOINT are never
+// checked, so a policy cannot leave a transaction dangling.
+type PolicyHook interface {
+	DBHook
+	Check(ctx context.Context, info *HookInfo) error
+}
+
+// ReadOnlyPolicy rejects statements that modify data or schema
+// (see databases.IsWriteStatement) while enabled, e.g. during maintenance.
+type ReadOnlyPolicy struct {
+	enabled atomic.Bool
+}
+
+// NewReadOnlyPolicy creates a read-only policy, initially enabled or not.
+// Toggle it at runtime with SetEnabled.
+func NewReadOnlyPolicy(enabled bool) *ReadOnlyPolicy {
+	p := &ReadOnlyPolicy{}
+	p.enabled.Store(enabled)
+	return p
+}
+
+// SetEnabled switches the read-only mode on or off. It is safe for concurrent use.
+func (p *ReadOnlyPolicy) SetEnabled(enabled bool) {
+	p.enabled.Store(enabled)
+}
+
+// Enabled reports whether the read-only mode is on.
+func (p *ReadOnlyPolicy) Enabled() bool {
+	return p.enabled.Load()
+}
+
+func (p *ReadOnlyPolicy) Before(ctx context.Context, info *HookInfo) context.Context { return ctx }
+
+func (p *ReadOnlyPolicy) After(ctx context.Context, info *HookInfo) {}
+
+func (p *ReadOnlyPolicy) Check(ctx context.Context, info *HookInfo) error {
+	if !p.Enabled() || !databases.IsWriteStatement(databases.DialectPostgres, info.SQL) {
+		return nil
+	}
+	return &databases.PolicyError{Policy: "read_only", Reason: "database is in read-only mode"}
+}
+
+// UnboundedWritePolicy rejects UPDATE and DELETE statements without a WHERE clause
+// (see databases.IsUnboundedWrite). Add "WHERE true" to affect every row on purpose.
+type UnboundedWritePolicy struct{}
+
+func (UnboundedWritePolicy) Before(ctx context.Context, info *HookInfo) context.Context { return ctx }
+
+func (UnboundedWritePolicy) After(ctx context.Context, info *HookInfo) {}
+
+func (UnboundedWritePolicy) Check(ctx context.Context, info *HookInfo) error {
+	if !databases.IsUnboundedWrite(databases.DialectPostgres, info.SQL) {
+		return nil
+	}
+	return &databases.PolicyError{Policy: "unbounded_write", Reason: "UPDATE or DELETE without WHERE clause"}
+}
+
+// BudgetPolicy limits the number of statements (query, query_row, exec, copy_from and
+// send_batch operations) per key within a fixed time window, e.g. a query budget per tenant.
+type BudgetPolicy struct {
+	budget *databases.QueryBudget
+	limit  int
+	window time.Duration
+	key    func(ctx context.Context) string
+}
+
+// NewBudgetPolicy allows limit statements per key every window. key derives the budget
+// key from the operation context; nil uses the tenant (databases.WithTenant).
+// Statements whose key is empty are not limited.
+func NewBudgetPolicy(limit int, window time.Duration, key func(ctx context.Context) string) *BudgetPolicy {
+	if key == nil {
+		key = func(ctx context.Context) string {
+			tenant, _ := databases.TenantFromContext(ctx)
+			return tenant
+		}
+	}
+	return &BudgetPolicy{budget: databases.NewQueryBudget(limit, window), limit: limit, window: window, key: key}
+}
+
+func (p *BudgetPolicy) Before(ctx context.Context, info *HookInfo) context.Context { return ctx }
+
+func (p *BudgetPolicy) After(ctx context.Context, info *HookInfo) {}
+
+func (p *BudgetPolicy) Check(ctx context.Context, info *HookInfo) error {
+	switch info.Op {
+	case OpQuery, OpQueryRow, OpExec, OpCopyFrom, OpSendBatch:
+	default:
+		return nil
+	}
+
+	key := p.key(ctx)
+	if key == "" || p.budget.Allow(key) {
+		return nil
+	}
+	return &databases.PolicyError{
+		Policy: "query_budget",
+		Reason: fmt.Sprintf("budget of %d statements per %s exceeded for %q", p.limit, p.window, key),
+	}
+}
+
+// CircuitBreakerPolicy rejects operations while its circuit breaker is open and feeds
+// it the result of every operation that reached the database.
+type CircuitBreakerPolicy struct {
+	breaker *databases.CircuitBreaker
+}
+
+// NewCircuitBreakerPolicy creates a circuit breaker policy, see databases.CircuitBreaker.
+func NewCircuitBreakerPolicy(cfg databases.CircuitBreakerConfig) *CircuitBreakerPolicy {
+	return &CircuitBreakerPolicy{breaker: databases.NewCircuitBreaker(cfg)}
+}
+
+func (p *CircuitBreakerPolicy) Before(ctx context.Context, info *HookInfo) context.Context {
+	return ctx
+}
+
+func (p *CircuitBreakerPolicy) After(ctx context.Context, info *HookInfo) {
+	// Notification errors come from the handler, not from the server.
+	if info.Vetoed || info.Op == OpNotification {
+		return
+	}
+	p.breaker.Record(info.Err)
+}
+
+func (p *CircuitBreakerPolicy) Check(ctx context.Context, info *HookInfo) error {
+	return p.breaker.Allow()
+}
+
+// vetoableOps are the operations checked by PolicyHook.
+var vetoableOps = map[Op]bool{
+	OpQuery: true, OpQueryRow: true, OpExec: true, OpTxBegin: true, OpSavepoint: true,
+	OpCopyFrom: true, OpSendBatch: true, OpListen: true,
+	OpAdvisoryLock: true,
+}
+
+// runBefore runs the Before of every hook in registration order, checking each
+// PolicyHook after its Before when check is set. The first veto stops the chain.
+// With WithTenantRLS, statements outside a transaction are then vetoed, see tenantOps.
+func (s *rdbms) runBefore(ctx context.Context, info *HookInfo, check bool) (context.Context, error) {
+	info.entered = 0
+	info.explainers = s.explainers
+	for _, h := range s.hooks {
+		ctx = h.Before(ctx, info)
+		info.entered++
+
+		policy, ok := h.(PolicyHook)
+		if !check || !ok {
+			continue
+		}
+		if err := policy.Check(ctx, info); err != nil {
+			info.Err = err
+			info.Vetoed = true
+			return ctx, err
+		}
+	}
+	if check && s.tenantRLS != nil && !s.isTx && tenantOps[info.Op] {
+		err := s.tenantRLS.CheckOutsideTx(ctx)
+		info.Err = err
+		info.Vetoed = true
+		return ctx, err
+	}
+	return ctx, nil
+}
+
+// tenantOps are the statements rejected outside a transaction with WithTenantRLS,
+// as the tenant setting is only applied when a transaction begins.
+var tenantOps = map[Op]bool{
+	OpQuery: true, OpQueryRow: true, OpExec: true, OpCopyFrom: true, OpSendBatch: true,
+}
@@ -1,10 +1,11 @@
-//go:generate go tool mockgen -destination=../../.mocking/pgxx_mock/pgxx_mock.go -package=pgxx_mock . RDBMS,ReadQuery,WriterCommand,Tx
+//go:generate go tool mockgen -destination=../../.mocking/pgxx_mock/pgxx_mock.go -package=pgxx_mock . RDBMS,ReadQuery,WriterCommand,BulkCommand,Notifier,AdvisoryLocker,Tx
 package pgxx
 
 import (
 	"context"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -16,8 +17,14 @@ import (
 type RDBMS interface {
 	ReadQuery
 	WriterCommand
+	BulkCommand
+	Notifier
+	AdvisoryLocker
 	queryExecutor
 	GetDB() *pgxpool.Pool
+
+	// Dialect returns databases.DialectPostgres.
+	Dialect() databases.Dialect
 }
 
 // WriterCommand defines write operations (INSERT, UPDATE, DELETE) on the database.
@@ -25,6 +32,42 @@ type WriterCommand interface {
 	WriterCommandSquirrel
 }
 
+// BulkCommand defines bulk write operations using the COPY protocol and pipelined batches.
+type BulkCommand interface {
+	// CopyFrom copies rows from rowSrc into tableName and returns the number of rows copied.
+	// See CopyFromStructs to copy a slice of structs.
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+
+	// SendBatch sends all queued queries in a single round trip.
+	// The returned BatchReader must be closed after reading the results.
+	SendBatch(ctx context.Context, batch *pgx.Batch) *BatchReader
+}
+
+// Notifier sends PostgreSQL notifications, see Listener to receive them.
+type Notifier interface {
+	// Notify sends payload on channel with pg_notify. Inside a transaction the
+	// notification is delivered on commit.
+	Notify(ctx context.Context, channel, payload string) error
+}
+
+// AdvisoryLocker acquires PostgreSQL advisory locks, identified by string keys hashed
+// with AdvisoryLockKey. See LeaderElector for leader election.
+type AdvisoryLocker interface {
+	// AdvisoryLock waits for the session-level lock of key, held on a dedicated
+	// connection until AdvisoryLock.Unlock.
+	AdvisoryLock(ctx context.Context, key string) (*AdvisoryLock, error)
+
+	// TryAdvisoryLock acquires the session-level lock of key if it is available.
+	TryAdvisoryLock(ctx context.Context, key string) (*AdvisoryLock, bool, error)
+
+	// XactAdvisoryLock waits for the transaction-level lock of key, released at the end
+	// of the transaction. It returns ErrNotInTx outside a transaction.
+	XactAdvisoryLock(ctx context.Context, key string) error
+
+	// TryXactAdvisoryLock acquires the transaction-level lock of key if it is available.
+	TryXactAdvisoryLock(ctx context.Context, key string) (bool, error)
+}
+
 // ReadQuery defines read operations (SELECT) on the database.
 type ReadQuery interface {
 	ReadQuerySquirrel
@@ -35,6 +78,10 @@ type WriterCommandSquirrel interface {
 	// ExecSq executes a write query (INSERT, UPDATE, DELETE) built with Squirrel.
 	// Returns a pgconn.CommandTag which contains command metadata, such as number of rows affected.
 	ExecSq(ctx context.Context, query squirrel.Sqlizer) (pgconn.CommandTag, error)
+
+	// UpdateWithVersion executes an UPDATE guarded by a version column and returns the new version.
+	// It returns an apperror.Conflict if the version is stale and an apperror.NotFound if the row is missing.
+	UpdateWithVersion(ctx context.Context, update databases.VersionedUpdate) (databases.Version, error)
 }
 
 // ReadQuerySquirrel defines read operations using Squirrel SQL builders.
@@ -56,6 +103,20 @@ type ReadQuerySquirrel interface {
 		fn func(rows pgx.Rows) error,
 	) (primitive.PaginationOutput, error)
 
+	// QuerySqCursorPagination executes a keyset (cursor) paginated SELECT query using Squirrel.
+	//   - countQuery is only executed when paginationInput.WithTotal is true.
+	//   - query is the SELECT statement without ORDER BY/LIMIT; keyset defines the ordering.
+	//   - paginationInput defines the cursor and page size.
+	// fn is called once per row; it must Scan the row and return the keyset column values.
+	// Returns pagination metadata with signed next/prev cursors and any error encountered.
+	QuerySqCursorPagination(
+		ctx context.Context,
+		countQuery, query squirrel.SelectBuilder,
+		keyset primitive.Keyset,
+		paginationInput primitive.CursorPaginationInput,
+		fn func(rows pgx.Rows) ([]any, error),
+	) (primitive.CursorPaginationOutput, error)
+
 	// QueryRowSq executes a SELECT query built with Squirrel and returns a single row.
 	// Errors are typically reported when Scan is called on the returned pgx.Row.
 	QueryRowSq(ctx context.Context, query squirrel.Sqlizer) (pgx.Row, error)
@@ -0,0 +1,61 @@
+package pgxx
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ambientTxKey stores the transactional rdbms started by DoTx/DoTxContext.
+// It is keyed by the base pool so transactions of different databases never mix.
+type ambientTxKey struct {
+	db *pgxpool.Pool
+}
+
+// withoutTxKey marks a context that opted out of the ambient transaction.
+type withoutTxKey struct{}
+
+// WithoutTx returns a context whose operations bypass the ambient transaction
+// started by DoTxContext and run directly on the pool.
+// A later DoTxContext with the returned context starts a new, independent transaction.
+func WithoutTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutTxKey{}, true)
+}
+
+// TxFromContext returns the ambient transaction started by DoTxContext on the
+// given RDBMS, if any. It returns false when ctx carries no transaction for it
+// or when the context opted out with WithoutTx.
+func TxFromContext(ctx context.Context, db RDBMS) (RDBMS, bool) {
+	s, ok := db.(*rdbms)
+	if !ok {
+		return nil, false
+	}
+	if s.isTx {
+		return s, true
+	}
+	return s.ambientTx(ctx)
+}
+
+// withTx stores the transactional child in ctx and clears any WithoutTx opt-out,
+// so operations made with the returned context join the transaction.
+func withTx(ctx context.Context, child *rdbms) context.Context {
+	ctx = context.WithValue(ctx, ambientTxKey{db: child.db}, child)
+	if disabled, _ := ctx.Value(withoutTxKey{}).(bool); disabled {
+		ctx = context.WithValue(ctx, withoutTxKey{}, false)
+	}
+	return ctx
+}
+
+// ambientTx returns the transactional rdbms carried by ctx for s's pool.
+// It returns false when s is already transactional, no transaction is active
+// in ctx, or the context opted out with WithoutTx.
+func (s *rdbms) ambientTx(ctx context.Context) (*rdbms, bool) {
+	if s.isTx || ctx == nil {
+		return nil, false
+	}
+	if disabled, _ := ctx.Value(withoutTxKey{}).(bool); disabled {
+		return nil, false
+	}
+	child, ok := ctx.Value(ambientTxKey{db: s.db}).(*rdbms)
+	return child, ok && child != nil
+}
@@ -0,0 +1,215 @@
+package databases
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPolicyDenied is matched (errors.Is) by every *PolicyError.
+var ErrPolicyDenied = errors.New("operation denied by policy")
+
+// PolicyError is returned when a policy hook of the sqlx or pgxx RDBMS vetoes an
+// operation before it reaches the driver.
+type PolicyError struct {
+	Policy string // Name of the policy, e.g. "read_only"
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s: policy %s: %s", ErrPolicyDenied, e.Policy, e.Reason)
+}
+
+func (e *PolicyError) Unwrap() error { return ErrPolicyDenied }
+
+var (
+	leadingKeywordPattern = regexp.MustCompile(`^\s*(?:\(\s*)*([A-Za-z]+)`)
+	withPattern           = regexp.MustCompile(`(?i)\)\s*(INSERT|UPDATE|DELETE|MERGE)\b`)
+)
+
+var writeKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "UPSERT": true, "REPLACE": true,
+	"TRUNCATE": true, "CREATE": true, "ALTER": true, "DROP": true, "GRANT": true, "REVOKE": true,
+	"COPY": true, "LOCK": true, "RENAME": true,
+}
+
+// IsWriteStatement reports whether one of the ;-separated statements of query
+// modifies data or schema, judged by its leading keyword. WITH queries are writes
+// when a data-modifying statement follows a CTE. String literals, quoted identifiers
+// and comments, lexed with the rules of dialect d, are ignored.
+func IsWriteStatement(d Dialect, query string) bool {
+	for _, stmt := range splitStatements(query, d) {
+		keyword := leadingKeyword(stmt)
+		if writeKeywords[keyword] {
+			return true
+		}
+		if keyword == "WITH" && withPattern.MatchString(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUnboundedWrite reports whether one of the ;-separated statements of query is an
+// UPDATE or DELETE without a top-level WHERE clause, i.e. one that affects every row.
+// A WHERE inside a subquery, a string literal or a comment of dialect d does not count.
+func IsUnboundedWrite(d Dialect, query string) bool {
+	for _, stmt := range splitStatements(query, d) {
+		switch leadingKeyword(stmt) {
+		case "UPDATE", "DELETE":
+			if !hasTopLevelWhere(stmt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTopLevelWhere reports whether stmt, as returned by splitStatements, has a WHERE
+// keyword outside parentheses.
+func hasTopLevelWhere(stmt string) bool {
+	depth := 0
+	for i := 0; i < len(stmt); i++ {
+		switch c := stmt[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && (i == 0 || !isIdentByte(stmt[i-1])) && i+5 <= len(stmt) &&
+			strings.EqualFold(stmt[i:i+5], "WHERE") && (i+5 == len(stmt) || !isIdentByte(stmt[i+5])):
+			return true
+		}
+	}
+	return false
+}
+
+func leadingKeyword(stmt string) string {
+	m := leadingKeywordPattern.FindStringSubmatch(stmt)
+	if m == nil {
+		return ""
+	}
+	return strings.ToUpper(m[1])
+}
+
+// QueryBudget limits the number of operations per key within a fixed time window,
+// e.g. per tenant. It is safe for concurrent use.
+type QueryBudget struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	start   time.Time
+	counter map[string]int
+}
+
+// NewQueryBudget creates a budget allowing limit operations per key every window.
+func NewQueryBudget(limit int, window time.Duration) *QueryBudget {
+	return &QueryBudget{limit: limit, window: window, now: time.Now, counter: make(map[string]int)}
+}
+
+// Allow consumes one operation of key's budget and reports whether it was available.
+func (b *QueryBudget) Allow(key string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now := b.now(); now.Sub(b.start) >= b.window {
+		b.start = now
+		clear(b.counter)
+	}
+	if b.counter[key] >= b.limit {
+		return false
+	}
+	b.counter[key]++
+	return true
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold int                  // Consecutive failures opening the circuit. Default: 5
+	Cooldown         time.Duration        // Time the circuit stays open before letting trial operations through. Default: 10s
+	IsFailure        func(err error) bool // Errors counted as failures. Default: IsCircuitFailure
+}
+
+// CircuitBreaker stops sending operations to a database that keeps failing.
+// After FailureThreshold consecutive failures the circuit opens and Allow rejects
+// operations. After Cooldown the circuit is half-open: a single trial operation is
+// let through, others are still rejected, and its result either closes the circuit
+// (success) or reopens it (failure). A trial whose result is never recorded, e.g.
+// vetoed by another policy, is replaced by a new one after another Cooldown.
+// It is safe for concurrent use.
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool      // A trial operation of the half-open circuit is in flight
+	probedAt time.Time // Start of the trial operation
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 10 * time.Second
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = IsCircuitFailure
+	}
+	return &CircuitBreaker{cfg: cfg, now: time.Now}
+}
+
+// Allow returns a *PolicyError while the circuit is open, and while the trial
+// operation of the half-open circuit is in flight.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return nil
+	}
+	now := b.now()
+	if now.Sub(b.openedAt) >= b.cfg.Cooldown && (!b.probing || now.Sub(b.probedAt) >= b.cfg.Cooldown) {
+		b.probing = true
+		b.probedAt = now
+		return nil
+	}
+	return &PolicyError{Policy: "circuit_breaker", Reason: "database circuit is open"}
+}
+
+// Record reports the result of an operation let through by Allow.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil || !b.cfg.IsFailure(err) {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.cfg.FailureThreshold {
+		b.openedAt = b.now()
+	}
+}
+
+// IsCircuitFailure reports whether err indicates the database itself is unhealthy:
+// connection errors, PostgreSQL classes 08 (connection exception), 53 (insufficient
+// resources) and 57P (operator intervention), and MySQL 1040 (too many connections).
+func IsCircuitFailure(err error) bool {
+	if IsConnectionError(err) {
+		return true
+	}
+	code := ErrorCode(err)
+	return strings.HasPrefix(code, "08") || strings.HasPrefix(code, "53") ||
+		strings.HasPrefix(code, "57P") || code == "1040"
+}
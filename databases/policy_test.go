@@ -0,0 +1,198 @@
+package databases
+
+import (
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsWriteStatement(t *testing.T) {
+	tests := map[string]bool{
+		"SELECT * FROM users":                                        false,
+		"  select 1; DELETE FROM users WHERE id = 1":                 true,
+		"INSERT INTO users (id) VALUES ($1) RETURNING id":            true,
+		"(SELECT 1) UNION (SELECT 2)":                                false,
+		"WITH t AS (SELECT 1) SELECT * FROM t":                       false,
+		"WITH t AS (SELECT id FROM users) DELETE FROM x USING t":     true,
+		"COPY \"users\" (id) FROM STDIN":                             true,
+		"SELECT set_config('app.tenant_id', 'acme', true)":           false,
+		"truncate users":                                             true,
+		"SELECT id FROM users WHERE note = 'UPDATE users SET x = 1'": false,
+		"SELECT ';drop table x'":                                     false,
+		"SELECT $$;delete from x$$":                                  false,
+		"SELECT 1 /* ; DELETE FROM users */":                         false,
+		"-- note\nDELETE FROM users WHERE id = 1":                    true,
+	}
+	for query, want := range tests {
+		if got := IsWriteStatement(DialectPostgres, query); got != want {
+			t.Errorf("IsWriteStatement(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestIsUnboundedWrite(t *testing.T) {
+	tests := map[string]bool{
+		"DELETE FROM users":                                          true,
+		"delete from users where id = $1":                            false,
+		"UPDATE users SET active = false":                            true,
+		"UPDATE users SET active = false WHERE true":                 false,
+		"SELECT * FROM users":                                        false,
+		"SELECT 1; UPDATE users SET a = 1":                           true,
+		"UPDATE t SET a = (SELECT b FROM u WHERE u.id = t.id)":       true,
+		"DELETE FROM t -- WHERE id = 1":                              true,
+		"UPDATE t SET note = 'where'":                                true,
+		"DELETE FROM t /* where */":                                  true,
+		"UPDATE t SET a = (SELECT 1) WHERE id IN (SELECT id FROM u)": false,
+		"UPDATE t SET a = 1 WHERE note = ';' ":                       false,
+		"UPDATE \"where\" SET a = 1":                                 true,
+	}
+	for query, want := range tests {
+		if got := IsUnboundedWrite(DialectPostgres, query); got != want {
+			t.Errorf("IsUnboundedWrite(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestPolicy_MySQLLexing(t *testing.T) {
+	writes := map[string]bool{
+		`SELECT 'x\'; DELETE FROM t'`: false,
+		`SELECT "a; DELETE FROM t"`:   false,
+		"SELECT 1 # ; DELETE FROM t":  false,
+		"SELECT `a;b` FROM t":         false,
+		"SELECT 1; DELETE FROM t":     true,
+		"/* x */ UPDATE t SET a = 1":  true,
+		`SELECT '\\'; DELETE FROM t`:  true,
+	}
+	for query, want := range writes {
+		if got := IsWriteStatement(DialectMySQL, query); got != want {
+			t.Errorf("IsWriteStatement(mysql, %q) = %v, want %v", query, got, want)
+		}
+	}
+
+	unbounded := map[string]bool{
+		`UPDATE t SET a='\' WHERE 1=1 -- '`:   true,
+		"UPDATE `where` SET a = 1":            true,
+		"DELETE FROM t # WHERE id = 1":        true,
+		`UPDATE t SET a = "x\"" WHERE id = 1`: false,
+		"DELETE FROM t WHERE `id` = 1":        false,
+	}
+	for query, want := range unbounded {
+		if got := IsUnboundedWrite(DialectMySQL, query); got != want {
+			t.Errorf("IsUnboundedWrite(mysql, %q) = %v, want %v", query, got, want)
+		}
+	}
+
+	// The same statement is bounded on PostgreSQL, where '\' is a complete literal.
+	if IsUnboundedWrite(DialectPostgres, `UPDATE t SET a='\' WHERE 1=1 -- '`) {
+		t.Errorf("IsUnboundedWrite(postgres) of a backslash literal = true, want false")
+	}
+}
+
+func TestQueryBudget(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	budget := NewQueryBudget(2, time.Minute)
+	budget.now = func() time.Time { return now }
+
+	if !budget.Allow("a") || !budget.Allow("a") || !budget.Allow("b") {
+		t.Fatalf("Allow() within budget = false, want true")
+	}
+	if budget.Allow("a") {
+		t.Fatalf("Allow() over budget = true, want false")
+	}
+
+	now = now.Add(time.Minute)
+	if !budget.Allow("a") {
+		t.Fatalf("Allow() in next window = false, want true")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, Cooldown: time.Second})
+	breaker.now = func() time.Time { return now }
+
+	breaker.Record(driver.ErrBadConn)
+	breaker.Record(errors.New("syntax error"))
+	breaker.Record(driver.ErrBadConn)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after non-consecutive failures = %v, want nil", err)
+	}
+
+	breaker.Record(driver.ErrBadConn)
+	err := breaker.Allow()
+	var policyErr *PolicyError
+	if !errors.Is(err, ErrPolicyDenied) || !errors.As(err, &policyErr) || policyErr.Policy != "circuit_breaker" {
+		t.Fatalf("Allow() on open circuit = %v, want circuit_breaker PolicyError", err)
+	}
+
+	now = now.Add(time.Second)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after cooldown = %v, want nil", err)
+	}
+	if err := breaker.Allow(); err == nil {
+		t.Fatalf("Allow() while the trial is in flight = nil, want error")
+	}
+	breaker.Record(driver.ErrBadConn)
+	if err := breaker.Allow(); err == nil {
+		t.Fatalf("Allow() after failed trial = nil, want error")
+	}
+
+	now = now.Add(time.Second)
+	breaker.Record(nil)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after successful trial = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenSingleTrial(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Cooldown: time.Second})
+	breaker.now = clock
+	breaker.Record(driver.ErrBadConn)
+
+	mu.Lock()
+	now = now.Add(time.Second)
+	mu.Unlock()
+
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int32
+	)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if breaker.Allow() == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := allowed.Load(); n != 1 {
+		t.Fatalf("concurrent Allow() after cooldown let %d operations through, want 1", n)
+	}
+
+	mu.Lock()
+	now = now.Add(time.Second)
+	mu.Unlock()
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() once an unrecorded trial timed out = %v, want nil", err)
+	}
+	breaker.Record(nil)
+	for range 3 {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("Allow() after a successful trial = %v, want nil", err)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package databases
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RangeElement lists the element types supported by Range: int4range/int8range
+// (int, int32, int64), numrange (float64) and tsrange/tstzrange/daterange (time.Time).
+type RangeElement interface {
+	int | int32 | int64 | float64 | time.Time
+}
+
+// Range is a PostgreSQL range value (tstzrange, daterange, int4range, ...).
+// It implements sql.Scanner and driver.Valuer using the range text format, and
+// pgtype.RangeScanner / pgtype.RangeValuer so pgx can use its binary range codec.
+//
+// Bound types use pgx's constants: pgtype.Inclusive, pgtype.Exclusive and
+// pgtype.Unbounded. An empty range has both bound types set to pgtype.Empty.
+type Range[T RangeElement] struct {
+	Lower     T
+	Upper     T
+	LowerType pgtype.BoundType
+	UpperType pgtype.BoundType
+	Valid     bool // Valid true if the value is not NULL
+}
+
+// NewRange creates a range from lower to upper with the bounds given in PostgreSQL
+// notation: "[)" (the default for an empty string), "[]", "(]" or "()".
+//
+// Example:
+//
+//	slot := databases.NewRange(start, end, "[)")
+func NewRange[T RangeElement](lower, upper T, bounds string) Range[T] {
+	if bounds == "" {
+		bounds = "[)"
+	}
+	r := Range[T]{Lower: lower, Upper: upper, LowerType: pgtype.Exclusive, UpperType: pgtype.Exclusive, Valid: true}
+	if strings.HasPrefix(bounds, "[") {
+		r.LowerType = pgtype.Inclusive
+	}
+	if strings.HasSuffix(bounds, "]") {
+		r.UpperType = pgtype.Inclusive
+	}
+	return r
+}
+
+// RangeFrom creates the range [lower,) with no upper bound.
+func RangeFrom[T RangeElement](lower T) Range[T] {
+	return Range[T]{Lower: lower, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true}
+}
+
+// RangeUntil creates the range (,upper) with no lower bound.
+func RangeUntil[T RangeElement](upper T) Range[T] {
+	return Range[T]{Upper: upper, LowerType: pgtype.Unbounded, UpperType: pgtype.Exclusive, Valid: true}
+}
+
+// EmptyRange creates the empty range.
+func EmptyRange[T RangeElement]() Range[T] {
+	return Range[T]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}
+}
+
+// IsEmpty reports whether the range is the empty range.
+func (r Range[T]) IsEmpty() bool {
+	return r.LowerType == pgtype.Empty
+}
+
+// String returns the range in PostgreSQL text format, e.g. ["2024-01-01 00:00:00Z","2024-01-02 00:00:00Z").
+func (r Range[T]) String() string {
+	if r.IsEmpty() {
+		return "empty"
+	}
+
+	var b strings.Builder
+	if r.LowerType == pgtype.Inclusive {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	if r.LowerType != pgtype.Unbounded {
+		b.WriteString(formatRangeElement(r.Lower))
+	}
+	b.WriteByte(',')
+	if r.UpperType != pgtype.Unbounded {
+		b.WriteString(formatRangeElement(r.Upper))
+	}
+	if r.UpperType == pgtype.Inclusive {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// Scan implements the sql.Scanner interface.
+// It decodes a range from its PostgreSQL text format.
+func (r *Range[T]) Scan(value any) error {
+	if r == nil {
+		return fmt.Errorf("Range.Scan: receiver is nil")
+	}
+	if value == nil {
+		*r = Range[T]{}
+		return nil
+	}
+
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("Range.Scan: unsupported source type %T", value)
+	}
+
+	parsed, err := parseRange[T](s)
+	if err != nil {
+		return fmt.Errorf("Range.Scan: %w", err)
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// It encodes the range in PostgreSQL text format.
+func (r Range[T]) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+// ScanNull implements pgtype.RangeScanner.
+func (r *Range[T]) ScanNull() error {
+	*r = Range[T]{}
+	return nil
+}
+
+// ScanBounds implements pgtype.RangeScanner.
+func (r *Range[T]) ScanBounds() (lowerTarget, upperTarget any) {
+	return &r.Lower, &r.Upper
+}
+
+// SetBoundTypes implements pgtype.RangeScanner.
+func (r *Range[T]) SetBoundTypes(lower, upper pgtype.BoundType) error {
+	if lower == pgtype.Unbounded || lower == pgtype.Empty {
+		var zero T
+		r.Lower = zero
+	}
+	if upper == pgtype.Unbounded || upper == pgtype.Empty {
+		var zero T
+		r.Upper = zero
+	}
+	r.LowerType = lower
+	r.UpperType = upper
+	r.Valid = true
+	return nil
+}
+
+// IsNull implements pgtype.RangeValuer.
+func (r Range[T]) IsNull() bool {
+	return !r.Valid
+}
+
+// BoundTypes implements pgtype.RangeValuer.
+func (r Range[T]) BoundTypes() (lower, upper pgtype.BoundType) {
+	return r.LowerType, r.UpperType
+}
+
+// Bounds implements pgtype.RangeValuer.
+func (r Range[T]) Bounds() (lower, upper any) {
+	return &r.Lower, &r.Upper
+}
+
+// parseRange decodes the PostgreSQL range text format.
+func parseRange[T RangeElement](s string) (Range[T], error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "empty") {
+		return EmptyRange[T](), nil
+	}
+	if len(s) < 3 {
+		return Range[T]{}, fmt.Errorf("invalid range %q", s)
+	}
+
+	r := Range[T]{Valid: true}
+	switch s[0] {
+	case '[':
+		r.LowerType = pgtype.Inclusive
+	case '(':
+		r.LowerType = pgtype.Exclusive
+	default:
+		return Range[T]{}, fmt.Errorf("invalid range %q: missing lower bound", s)
+	}
+	switch s[len(s)-1] {
+	case ']':
+		r.UpperType = pgtype.Inclusive
+	case ')':
+		r.UpperType = pgtype.Exclusive
+	default:
+		return Range[T]{}, fmt.Errorf("invalid range %q: missing upper bound", s)
+	}
+
+	lower, present, rest, err := readRangeBound(s[1:len(s)-1], ',')
+	if err != nil {
+		return Range[T]{}, fmt.Errorf("invalid range %q: %w", s, err)
+	}
+	if !strings.HasPrefix(rest, ",") {
+		return Range[T]{}, fmt.Errorf("invalid range %q: missing comma", s)
+	}
+	if !present {
+		r.LowerType = pgtype.Unbounded
+	} else if r.Lower, err = parseRangeElement[T](lower); err != nil {
+		return Range[T]{}, fmt.Errorf("invalid range lower bound %q: %w", lower, err)
+	}
+
+	upper, present, rest, err := readRangeBound(rest[1:], 0)
+	if err != nil {
+		return Range[T]{}, fmt.Errorf("invalid range %q: %w", s, err)
+	}
+	if rest != "" {
+		return Range[T]{}, fmt.Errorf("invalid range %q: trailing data", s)
+	}
+	if !present {
+		r.UpperType = pgtype.Unbounded
+	} else if r.Upper, err = parseRangeElement[T](upper); err != nil {
+		return Range[T]{}, fmt.Errorf("invalid range upper bound %q: %w", upper, err)
+	}
+
+	return r, nil
+}
+
+// readRangeBound reads one bound, which is either double-quoted (with "" and
+// backslash escapes) or runs up to stop. present is false for an omitted bound.
+func readRangeBound(s string, stop byte) (value string, present bool, rest string, err error) {
+	if s == "" || s[0] == stop {
+		return "", false, s, nil
+	}
+	if s[0] != '"' {
+		i := strings.IndexByte(s, stop)
+		if stop == 0 || i < 0 {
+			return s, true, "", nil
+		}
+		return s[:i], true, s[i:], nil
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case c == '"' && i+1 < len(s) && s[i+1] == '"':
+			i++
+			b.WriteByte('"')
+		case c == '"':
+			return b.String(), true, s[i+1:], nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false, "", fmt.Errorf("unterminated quoted bound")
+}
+
+var rangeTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC3339Nano,
+	time.DateOnly,
+}
+
+func parseRangeElement[T RangeElement](s string) (T, error) {
+	var v T
+	var err error
+	switch p := any(&v).(type) {
+	case *int:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 0)
+		*p = int(n)
+	case *int32:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 32)
+		*p = int32(n)
+	case *int64:
+		*p, err = strconv.ParseInt(s, 10, 64)
+	case *float64:
+		*p, err = strconv.ParseFloat(s, 64)
+	case *time.Time:
+		for _, layout := range rangeTimeLayouts {
+			var t time.Time
+			if t, err = time.Parse(layout, s); err == nil {
+				*p = t
+				break
+			}
+		}
+	}
+	return v, err
+}
+
+func formatRangeElement[T RangeElement](v T) string {
+	switch val := any(v).(type) {
+	case int:
+		return strconv.Itoa(val)
+	case int32:
+		return strconv.FormatInt(int64(val), 10)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case time.Time:
+		return `"` + val.Format("2006-01-02 15:04:05.999999999Z07:00") + `"`
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,68 @@
+package databases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRange_Scan(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "tstzrange", input: `["2024-01-01 10:00:00+07","2024-01-01 11:30:00+07")`, want: `["2024-01-01 10:00:00+07:00","2024-01-01 11:30:00+07:00")`},
+		{name: "daterange", input: `[2024-01-01,2024-02-01)`, want: `["2024-01-01 00:00:00Z","2024-02-01 00:00:00Z")`},
+		{name: "unbounded upper", input: `["2024-01-01 00:00:00+00",)`, want: `["2024-01-01 00:00:00Z",)`},
+		{name: "empty", input: `empty`, want: `empty`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Range[time.Time]
+			if err := r.Scan(tt.input); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if !r.Valid {
+				t.Fatalf("Scan() Valid = false")
+			}
+			if got := r.String(); got != tt.want {
+				t.Fatalf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRange_ScanInt(t *testing.T) {
+	var r Range[int32]
+	if err := r.Scan([]byte("(,10]")); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if r.LowerType != pgtype.Unbounded || r.UpperType != pgtype.Inclusive || r.Upper != 10 {
+		t.Fatalf("Scan() = %+v", r)
+	}
+
+	if err := r.Scan(nil); err != nil || r.Valid {
+		t.Fatalf("Scan(nil) = %+v, %v; want invalid", r, err)
+	}
+	for _, bad := range []string{"[1,2", "1,2)", `["1,2)`, "[a,2)", "[1,2,3)"} {
+		if err := r.Scan(bad); err == nil {
+			t.Fatalf("Scan(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestRange_Value(t *testing.T) {
+	v, err := NewRange[int64](1, 5, "[]").Value()
+	if err != nil || v != "[1,5]" {
+		t.Fatalf("Value() = %v, %v; want [1,5]", v, err)
+	}
+	if v, _ := (Range[int64]{}).Value(); v != nil {
+		t.Fatalf("Value() of NULL range = %v, want nil", v)
+	}
+	if got := RangeUntil(2.5).String(); got != "(,2.5)" {
+		t.Fatalf("RangeUntil().String() = %q", got)
+	}
+}
@@ -0,0 +1,262 @@
+package databases
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// NodePrimary is the node name reported for operations served by the primary.
+const NodePrimary = "primary"
+
+// ReplicaPolicy selects which healthy replica serves a read query.
+type ReplicaPolicy string
+
+const (
+	// ReplicaRoundRobin cycles through healthy replicas in order.
+	ReplicaRoundRobin ReplicaPolicy = "round_robin"
+	// ReplicaLeastLatency picks the healthy replica with the lowest observed latency.
+	ReplicaLeastLatency ReplicaPolicy = "least_latency"
+)
+
+const (
+	defaultReplicaHealthCheckInterval = 5 * time.Second
+	defaultReplicaMaxFailures         = 3
+	replicaLatencyDecay               = 0.2
+)
+
+// ReplicaRouterConfig configures replica selection and health-based ejection.
+type ReplicaRouterConfig struct {
+	Policy              ReplicaPolicy // Selection policy. Default: round robin.
+	HealthCheckInterval time.Duration // Interval between replica pings. Default: 5s.
+	MaxFailures         int           // Consecutive connection failures before a replica is ejected. Default: 3.
+}
+
+// ReplicaNode is a single read replica tracked by a ReplicaRouter.
+type ReplicaNode[T any] struct {
+	Name string // Node name reported to hooks (e.g. replica-0)
+	DB   T      // The underlying connection pool
+
+	ejected  atomic.Bool
+	failures atomic.Int32
+	latency  atomic.Int64 // exponentially weighted moving average, in nanoseconds
+}
+
+// Healthy reports whether the node currently receives traffic.
+func (n *ReplicaNode[T]) Healthy() bool {
+	return !n.ejected.Load()
+}
+
+// Latency returns the moving average latency observed on the node.
+func (n *ReplicaNode[T]) Latency() time.Duration {
+	return time.Duration(n.latency.Load())
+}
+
+// ReplicaRouter distributes read traffic across replicas and ejects replicas
+// that fail health checks or repeatedly fail with connection errors.
+// A ReplicaRouter is safe for concurrent use.
+type ReplicaRouter[T any] struct {
+	nodes       []*ReplicaNode[T]
+	policy      ReplicaPolicy
+	interval    time.Duration
+	maxFailures int32
+	next        atomic.Uint64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewReplicaRouter creates a router over the given replicas. Replica names default
+// to "replica-<index>" when empty.
+func NewReplicaRouter[T any](nodes []*ReplicaNode[T], cfg ReplicaRouterConfig) *ReplicaRouter[T] {
+	if cfg.Policy == "" {
+		cfg.Policy = ReplicaRoundRobin
+	}
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = defaultReplicaHealthCheckInterval
+	}
+	if cfg.MaxFailures <= 0 {
+		cfg.MaxFailures = defaultReplicaMaxFailures
+	}
+
+	for i, n := range nodes {
+		if n.Name == "" {
+			n.Name = "replica-" + strconv.Itoa(i)
+		}
+	}
+
+	return &ReplicaRouter[T]{
+		nodes:       nodes,
+		policy:      cfg.Policy,
+		interval:    cfg.HealthCheckInterval,
+		maxFailures: int32(cfg.MaxFailures),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Nodes returns all replicas known to the router, including ejected ones.
+func (r *ReplicaRouter[T]) Nodes() []*ReplicaNode[T] {
+	return r.nodes
+}
+
+// Pick returns a healthy replica according to the router policy.
+// It returns false if no replica is healthy, in which case callers should use the primary.
+func (r *ReplicaRouter[T]) Pick() (*ReplicaNode[T], bool) {
+	if r == nil || len(r.nodes) == 0 {
+		return nil, false
+	}
+
+	switch r.policy {
+	case ReplicaLeastLatency:
+		var best *ReplicaNode[T]
+		for _, n := range r.nodes {
+			if !n.Healthy() {
+				continue
+			}
+			if best == nil || n.Latency() < best.Latency() {
+				best = n
+			}
+		}
+		return best, best != nil
+	default:
+		start := r.next.Add(1) - 1
+		for i := range r.nodes {
+			n := r.nodes[(start+uint64(i))%uint64(len(r.nodes))]
+			if n.Healthy() {
+				return n, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// Observe records the outcome of an operation served by node n.
+// Latency feeds the least-latency policy; connection errors count towards ejection.
+func (r *ReplicaRouter[T]) Observe(n *ReplicaNode[T], d time.Duration, err error) {
+	if n == nil {
+		return
+	}
+
+	if err != nil {
+		if IsConnectionError(err) && n.failures.Add(1) >= r.maxFailures {
+			n.ejected.Store(true)
+		}
+		return
+	}
+
+	n.failures.Store(0)
+	prev := n.latency.Load()
+	if prev == 0 {
+		n.latency.Store(int64(d))
+		return
+	}
+	n.latency.Store(int64(float64(prev)*(1-replicaLatencyDecay) + float64(d)*replicaLatencyDecay))
+}
+
+// StartHealthCheck pings every replica periodically in a background goroutine.
+// Failed replicas are ejected and restored once a ping succeeds again.
+// The returned function stops the health checker; it is safe to call more than once.
+func (r *ReplicaRouter[T]) StartHealthCheck(ping func(ctx context.Context, db T) error) func() {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.checkAll(ping)
+			}
+		}
+	}()
+
+	return func() {
+		r.stopOnce.Do(func() { close(r.stop) })
+	}
+}
+
+func (r *ReplicaRouter[T]) checkAll(ping func(ctx context.Context, db T) error) {
+	for _, n := range r.nodes {
+		ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+		start := time.Now()
+		err := ping(ctx, n.DB)
+		cancel()
+
+		if err != nil {
+			n.ejected.Store(true)
+			continue
+		}
+		n.ejected.Store(false)
+		r.Observe(n, time.Since(start), nil)
+	}
+}
+
+// IsConnectionError reports whether err indicates a broken or unreachable
+// database connection rather than a query-level failure.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var connErr *pgconn.ConnectError
+	if errors.As(err, &connErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+type readYourWritesKey struct{}
+type usePrimaryKey struct{}
+
+type readYourWritesSession struct {
+	wrote atomic.Bool
+}
+
+// WithReadYourWrites returns a context that tracks writes performed through it.
+// Once a write is executed with the returned context (or one derived from it),
+// subsequent reads using that context are routed to the primary.
+// Typically installed once per request by an HTTP middleware.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(readYourWritesKey{}).(*readYourWritesSession); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, readYourWritesKey{}, &readYourWritesSession{})
+}
+
+// UsePrimary returns a context that routes all reads to the primary.
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, usePrimaryKey{}, true)
+}
+
+// MarkWrite records that a write was executed for the read-your-writes session
+// carried by ctx. It is a no-op if ctx has no session.
+func MarkWrite(ctx context.Context) {
+	if s, ok := ctx.Value(readYourWritesKey{}).(*readYourWritesSession); ok {
+		s.wrote.Store(true)
+	}
+}
+
+// ShouldReadPrimary reports whether reads for ctx must be served by the primary,
+// either because UsePrimary was applied or because the read-your-writes session
+// has already written.
+func ShouldReadPrimary(ctx context.Context) bool {
+	if v, ok := ctx.Value(usePrimaryKey{}).(bool); ok && v {
+		return true
+	}
+	s, ok := ctx.Value(readYourWritesKey{}).(*readYourWritesSession)
+	return ok && s.wrote.Load()
+}
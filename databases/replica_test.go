@@ -0,0 +1,99 @@
+package databases
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestRouter(policy ReplicaPolicy) *ReplicaRouter[string] {
+	return NewReplicaRouter([]*ReplicaNode[string]{
+		{DB: "a"},
+		{DB: "b"},
+		{DB: "c"},
+	}, ReplicaRouterConfig{Policy: policy, MaxFailures: 2})
+}
+
+func TestReplicaRouter_RoundRobin(t *testing.T) {
+	r := newTestRouter(ReplicaRoundRobin)
+
+	got := make([]string, 0, 4)
+	for range 4 {
+		n, ok := r.Pick()
+		if !ok {
+			t.Fatalf("Pick() ok = false, want true")
+		}
+		got = append(got, n.DB)
+	}
+	if want := []string{"a", "b", "c", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("Pick() sequence = %v, want %v", got, want)
+	}
+
+	if name := r.Nodes()[1].Name; name != "replica-1" {
+		t.Fatalf("default name = %q, want replica-1", name)
+	}
+}
+
+func TestReplicaRouter_LeastLatency(t *testing.T) {
+	r := newTestRouter(ReplicaLeastLatency)
+	nodes := r.Nodes()
+	r.Observe(nodes[0], 30*time.Millisecond, nil)
+	r.Observe(nodes[1], 10*time.Millisecond, nil)
+	r.Observe(nodes[2], 20*time.Millisecond, nil)
+
+	n, ok := r.Pick()
+	if !ok || n.DB != "b" {
+		t.Fatalf("Pick() = %v, %v, want b", n, ok)
+	}
+}
+
+func TestReplicaRouter_Ejection(t *testing.T) {
+	r := NewReplicaRouter([]*ReplicaNode[string]{{DB: "a"}}, ReplicaRouterConfig{MaxFailures: 2})
+	node := r.Nodes()[0]
+
+	// query-level errors never eject a replica
+	r.Observe(node, time.Millisecond, errors.New("syntax error"))
+	r.Observe(node, time.Millisecond, errors.New("syntax error"))
+	if !node.Healthy() {
+		t.Fatalf("Healthy() = false after query errors, want true")
+	}
+
+	r.Observe(node, time.Millisecond, driver.ErrBadConn)
+	r.Observe(node, time.Millisecond, driver.ErrBadConn)
+	if node.Healthy() {
+		t.Fatalf("Healthy() = true after connection errors, want false")
+	}
+	if _, ok := r.Pick(); ok {
+		t.Fatalf("Pick() ok = true with all replicas ejected, want false")
+	}
+
+	r.checkAll(func(context.Context, string) error { return nil })
+	if !node.Healthy() {
+		t.Fatalf("Healthy() = false after successful ping, want true")
+	}
+}
+
+func TestReadYourWrites(t *testing.T) {
+	ctx := context.Background()
+	if ShouldReadPrimary(ctx) {
+		t.Fatalf("ShouldReadPrimary(background) = true, want false")
+	}
+
+	MarkWrite(ctx) // no session: no-op
+	session := WithReadYourWrites(ctx)
+	if ShouldReadPrimary(session) {
+		t.Fatalf("ShouldReadPrimary(new session) = true, want false")
+	}
+
+	MarkWrite(context.WithValue(session, struct{}{}, "derived"))
+	if !ShouldReadPrimary(session) {
+		t.Fatalf("ShouldReadPrimary(after write) = false, want true")
+	}
+
+	if !ShouldReadPrimary(UsePrimary(ctx)) {
+		t.Fatalf("ShouldReadPrimary(UsePrimary) = false, want true")
+	}
+}
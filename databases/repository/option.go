@@ -0,0 +1,43 @@
+package repository
+
+// Option configures a Repository.
+type Option interface {
+	apply(*config)
+}
+
+type optFunc func(*config)
+
+func (o optFunc) apply(c *config) {
+	o(c)
+}
+
+const defaultChunkSize = 500
+
+type config struct {
+	table     string
+	chunkSize int
+}
+
+func defaultConfig() *config {
+	return &config{
+		chunkSize: defaultChunkSize,
+	}
+}
+
+// WithTable sets the table name, overriding the `table` struct tag of the entity.
+func WithTable(table string) Option {
+	return optFunc(func(c *config) {
+		c.table = table
+	})
+}
+
+// WithChunkSize sets the maximum number of rows per INSERT statement of InsertMany.
+// Chunks are further reduced so a statement never exceeds 65535 bind parameters nor,
+// on MySQL, max_allowed_packet. Default: 500.
+func WithChunkSize(n int) Option {
+	return optFunc(func(c *config) {
+		if n > 0 {
+			c.chunkSize = n
+		}
+	})
+}
@@ -0,0 +1,403 @@
+// Package repository provides a generic Repository implementing the usual CRUD
+// operations of an entity on top of the pgxx or sqlx RDBMS, for PostgreSQL and MySQL.
+//
+// The table mapping is read from struct tags:
+//
+//   - `table:"name"` on any field (typically `_ struct{}`) names the table, see also WithTable;
+//   - `db:"column"` names the column of a field; untagged fields use the snake_cased
+//     field name, `db:"-"` and unexported fields are skipped, embedded structs are flattened;
+//   - the options pk (exactly one field, the primary key), readonly (generated by the
+//     database: never written, read back after writes) and insertonly (written by
+//     Insert, never by Update) follow the column name.
+//
+// Statements run through the RDBMS, so hooks and tracing apply and operations made
+// with a context carrying a DoTxContext transaction join it.
+//
+// Example:
+//
+//	type User struct {
+//		_         struct{}  `table:"users"`
+//		ID        int64     `db:"id,pk,readonly"`
+//		Email     string    `db:"email"`
+//		Name      string    `db:"name"`
+//		CreatedAt time.Time `db:"created_at,insertonly"`
+//	}
+//
+//	users, err := repository.NewPgx[User, int64](db)
+//
+//	err = users.Insert(ctx, &user) // sets user.ID
+//	user, err = users.FindByID(ctx, user.ID)
+//	err = users.Update(ctx, &user, "name")
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/filter"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/sqlx"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
+)
+
+// Repository implements CRUD operations for the entity struct T whose primary key has type ID.
+// It is safe for concurrent use.
+type Repository[T any, ID comparable] struct {
+	store       store
+	schema      *schema
+	cfg         *config
+	placeholder squirrel.PlaceholderFormat
+}
+
+// Query selects the entities returned by FindMany.
+type Query struct {
+	Filters    []filter.Filter           // Combined with AND; build them for the repository dialect
+	Sorting    primitive.Sorting         // Resolve it with primitive.SortSpec first. Default: primary key ascending
+	Pagination primitive.PaginationInput // PageSize 0 returns every matching entity
+}
+
+// NewPgx creates a Repository on a pgxx RDBMS.
+func NewPgx[T any, ID comparable](db pgxx.RDBMS, opts ...Option) (*Repository[T, ID], error) {
+	return newRepository[T, ID](&pgxStore{db: db}, opts...)
+}
+
+// NewSQL creates a Repository on a sqlx RDBMS, for its dialect (see sqlx.WithDialect).
+func NewSQL[T any, ID comparable](db sqlx.RDBMS, opts ...Option) (*Repository[T, ID], error) {
+	return newRepository[T, ID](&sqlStore{db: db}, opts...)
+}
+
+func newRepository[T any, ID comparable](s store, opts ...Option) (*Repository[T, ID], error) {
+	cfg := defaultConfig()
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+
+	sch, err := parseSchema(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	if cfg.table != "" {
+		sch.table = cfg.table
+	}
+	if sch.table == "" {
+		return nil, fmt.Errorf("repository: no table for %s, add a `table` struct tag or use WithTable", reflect.TypeFor[T]())
+	}
+
+	switch d := s.dialect(); d {
+	case databases.DialectPostgres, databases.DialectMySQL:
+	default:
+		return nil, fmt.Errorf("repository: unsupported dialect %q", d)
+	}
+
+	return &Repository[T, ID]{
+		store:       s,
+		schema:      sch,
+		cfg:         cfg,
+		placeholder: s.dialect().Placeholder(),
+	}, nil
+}
+
+// Table returns the table name.
+func (r *Repository[T, ID]) Table() string {
+	return r.schema.table
+}
+
+// FindByID returns the entity with the primary key id, or an apperror.NotFound
+// caused by databases.ErrNoRowFound.
+func (r *Repository[T, ID]) FindByID(ctx context.Context, id ID) (T, error) {
+	var zero T
+
+	items, err := r.find(ctx, r.selectQuery().Where(squirrel.Eq{r.schema.pk.name: id}).Limit(1))
+	if err != nil {
+		return zero, err
+	}
+	if len(items) == 0 {
+		return zero, apperror.NotFound(
+			fmt.Sprintf("%s: row %v not found", r.schema.table, id),
+			apperror.WithCause(databases.ErrNoRowFound),
+			apperror.WithPublicMessage("resource not found"),
+		)
+	}
+	return items[0], nil
+}
+
+// FindMany returns the entities matching q, one page at a time when q.Pagination.PageSize
+// is set. The total number of matching entities is counted with a separate query.
+func (r *Repository[T, ID]) FindMany(ctx context.Context, q Query) ([]T, primitive.PaginationOutput, error) {
+	query := r.selectQuery()
+	count := squirrel.Select("COUNT(*)").From(r.schema.table).PlaceholderFormat(r.placeholder)
+	for _, f := range q.Filters {
+		pred, err := f.BuildSquirrel()
+		if err != nil {
+			return nil, primitive.PaginationOutput{}, err
+		}
+		if pred != nil {
+			query = query.Where(pred)
+			count = count.Where(pred)
+		}
+	}
+
+	if len(q.Sorting.SortFields) > 0 {
+		query = q.Sorting.BuildSquirrel(query)
+	} else {
+		query = query.OrderBy(r.schema.pk.name)
+	}
+
+	if q.Pagination.PageSize <= 0 {
+		items, err := r.find(ctx, query)
+		if err != nil {
+			return nil, primitive.PaginationOutput{}, err
+		}
+		return items, primitive.CreatePaginationOutput(q.Pagination, int64(len(items))), nil
+	}
+
+	var total int64
+	rawCount, args, err := count.ToSql()
+	if err != nil {
+		return nil, primitive.PaginationOutput{}, fmt.Errorf("failed parse squirrel: %w", err)
+	}
+	err = r.store.query(ctx, rawCount, args, func(scan func(dest ...any) error) error {
+		return scan(&total)
+	})
+	if err != nil {
+		return nil, primitive.PaginationOutput{}, fmt.Errorf("failed count data: %w", err)
+	}
+
+	offset := primitive.GetOffsetValue(q.Pagination.Page, q.Pagination.PageSize)
+	items, err := r.find(ctx, query.Limit(uint64(q.Pagination.PageSize)).Offset(uint64(offset)))
+	if err != nil {
+		return nil, primitive.PaginationOutput{}, err
+	}
+	return items, primitive.CreatePaginationOutput(q.Pagination, total), nil
+}
+
+// Insert inserts entity, writing every column except the readonly ones. The readonly
+// columns are read back into entity with RETURNING on PostgreSQL; on MySQL only a
+// readonly integer primary key is, from the last insert id.
+func (r *Repository[T, ID]) Insert(ctx context.Context, entity *T) error {
+	v := reflect.ValueOf(entity).Elem()
+	columns := r.schema.names(insertable)
+
+	query := squirrel.Insert(r.schema.table).
+		Columns(columns...).
+		Values(r.schema.values(v, columns)...).
+		PlaceholderFormat(r.placeholder)
+	_, err := r.write(ctx, v, query)
+	return err
+}
+
+// InsertMany inserts entities with multi-row INSERT statements of at most
+// WithChunkSize rows, within the placeholder and packet limits of the dialect (see
+// databases.BatchInsert). Generated columns are not read back. Chunks are separate
+// statements: run InsertMany inside DoTxContext to insert all or nothing.
+func (r *Repository[T, ID]) InsertMany(ctx context.Context, entities []T) error {
+	columns := r.schema.names(insertable)
+	b := databases.NewBatchInsert(r.store.dialect(), r.schema.table).
+		Columns(columns...).
+		MaxRows(r.cfg.chunkSize)
+	for i := range entities {
+		b.Values(r.schema.values(reflect.ValueOf(&entities[i]).Elem(), columns)...)
+	}
+
+	batches, err := b.Batches()
+	if err != nil {
+		return err
+	}
+	for _, batch := range batches {
+		rawQuery, args, err := batch.ToSql()
+		if err != nil {
+			return fmt.Errorf("failed parse squirrel: %w", err)
+		}
+		if _, err = r.store.exec(ctx, rawQuery, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update writes the given columns of entity to the row with its primary key; without
+// columns, every column that is not the primary key, readonly or insertonly is written.
+// Readonly columns are read back on PostgreSQL. It returns an apperror.NotFound caused
+// by databases.ErrNoUpdateRow if the row does not exist.
+func (r *Repository[T, ID]) Update(ctx context.Context, entity *T, columns ...string) error {
+	if len(columns) == 0 {
+		columns = r.schema.names(updatable)
+	}
+	for _, name := range columns {
+		if c, ok := r.schema.column(name); !ok || !updatable(c) {
+			return fmt.Errorf("repository: column %q of %s cannot be updated", name, r.schema.table)
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	v := reflect.ValueOf(entity).Elem()
+	id := v.FieldByIndex(r.schema.pk.index).Interface()
+	query := squirrel.Update(r.schema.table).PlaceholderFormat(r.placeholder)
+	for i, value := range r.schema.values(v, columns) {
+		query = query.Set(columns[i], value)
+	}
+	query = query.Where(squirrel.Eq{r.schema.pk.name: id})
+
+	ok, err := r.write(ctx, v, query)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// MySQL does not count matched rows whose values did not change.
+		if r.store.dialect() == databases.DialectMySQL {
+			if exists, err := r.exists(ctx, id); err != nil || exists {
+				return err
+			}
+		}
+		return apperror.NotFound(
+			fmt.Sprintf("%s: row %v to update not found", r.schema.table, id),
+			apperror.WithCause(databases.ErrNoUpdateRow),
+			apperror.WithPublicMessage("resource not found"),
+		)
+	}
+	return nil
+}
+
+// Upsert inserts entity, or updates the row with the same primary key: every column
+// that is not the primary key, readonly or insertonly is overwritten. The primary key
+// is always written, so it must be set, even when readonly. Readonly columns are read
+// back on PostgreSQL.
+func (r *Repository[T, ID]) Upsert(ctx context.Context, entity *T) error {
+	v := reflect.ValueOf(entity).Elem()
+	columns := r.schema.names(func(c column) bool { return c.pk || insertable(c) })
+
+	query := databases.NewBatchInsert(r.store.dialect(), r.schema.table).
+		Columns(columns...).
+		Values(r.schema.values(v, columns)...).
+		OnConflict(r.schema.pk.name)
+	if updates := r.schema.names(updatable); len(updates) > 0 {
+		query.DoUpdate(updates...)
+	} else {
+		query.DoNothing()
+	}
+	_, err := r.write(ctx, v, query)
+	return err
+}
+
+// Delete deletes the row with the primary key id. It returns an apperror.NotFound
+// caused by databases.ErrNoDeleteRow if the row does not exist.
+func (r *Repository[T, ID]) Delete(ctx context.Context, id ID) error {
+	rawQuery, args, err := squirrel.Delete(r.schema.table).
+		Where(squirrel.Eq{r.schema.pk.name: id}).
+		PlaceholderFormat(r.placeholder).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed parse squirrel: %w", err)
+	}
+
+	res, err := r.store.exec(ctx, rawQuery, args...)
+	if err != nil {
+		return err
+	}
+	if res.rowsAffected == 0 {
+		return apperror.NotFound(
+			fmt.Sprintf("%s: row %v to delete not found", r.schema.table, id),
+			apperror.WithCause(databases.ErrNoDeleteRow),
+			apperror.WithPublicMessage("resource not found"),
+		)
+	}
+	return nil
+}
+
+func (r *Repository[T, ID]) selectQuery() squirrel.SelectBuilder {
+	return squirrel.Select(r.schema.names(all)...).From(r.schema.table).PlaceholderFormat(r.placeholder)
+}
+
+// find runs a SELECT of every column and scans the rows into entities.
+func (r *Repository[T, ID]) find(ctx context.Context, query squirrel.SelectBuilder) ([]T, error) {
+	rawQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed parse squirrel: %w", err)
+	}
+
+	columns := r.schema.names(all)
+	items := make([]T, 0)
+	err = r.store.query(ctx, rawQuery, args, func(scan func(dest ...any) error) error {
+		var item T
+		if err := scan(r.schema.pointers(reflect.ValueOf(&item).Elem(), columns)...); err != nil {
+			return err
+		}
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// exists reports whether the row with the primary key id exists.
+func (r *Repository[T, ID]) exists(ctx context.Context, id any) (bool, error) {
+	rawQuery, args, err := squirrel.Select("1").
+		From(r.schema.table).
+		Where(squirrel.Eq{r.schema.pk.name: id}).
+		Limit(1).
+		PlaceholderFormat(r.placeholder).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed parse squirrel: %w", err)
+	}
+
+	found := false
+	err = r.store.query(databases.UsePrimary(ctx), rawQuery, args, func(scan func(dest ...any) error) error {
+		found = true
+		var one int
+		return scan(&one)
+	})
+	return found, err
+}
+
+// write executes an INSERT or UPDATE of the entity v and reports whether a row was
+// affected. On PostgreSQL the readonly columns are read back into v with RETURNING;
+// on MySQL a readonly integer primary key is set from the last insert id.
+func (r *Repository[T, ID]) write(ctx context.Context, v reflect.Value, query squirrel.Sqlizer) (bool, error) {
+	rawQuery, args, err := query.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed parse squirrel: %w", err)
+	}
+
+	returning := r.schema.names(readonly)
+	if r.store.dialect() == databases.DialectPostgres && len(returning) > 0 {
+		found := false
+		rawQuery += " RETURNING " + strings.Join(returning, ", ")
+		// A write returning rows must not be routed to a read replica.
+		databases.MarkWrite(ctx)
+		err = r.store.query(databases.UsePrimary(ctx), rawQuery, args, func(scan func(dest ...any) error) error {
+			found = true
+			return scan(r.schema.pointers(v, returning)...)
+		})
+		return found, err
+	}
+
+	res, err := r.store.exec(ctx, rawQuery, args...)
+	if err != nil {
+		return false, err
+	}
+
+	pk := v.FieldByIndex(r.schema.pk.index)
+	_, isInsert := query.(squirrel.InsertBuilder)
+	_, isUpsert := query.(*databases.BatchInsert)
+	if (isInsert || isUpsert) && r.schema.pk.readonly && pk.IsZero() {
+		if id, err := res.lastInsertID(); err == nil && id != 0 {
+			switch {
+			case pk.CanInt():
+				pk.SetInt(id)
+			case pk.CanUint():
+				pk.SetUint(uint64(id))
+			}
+		}
+	}
+	return res.rowsAffected > 0, nil
+}
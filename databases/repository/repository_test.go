@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/filter"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
+)
+
+type Audit struct {
+	CreatedAt time.Time `db:"created_at,insertonly"`
+}
+
+type user struct {
+	_     struct{} `table:"users"`
+	ID    int64    `db:"id,pk,readonly"`
+	Email string
+	Name  string `db:"name"`
+	Note  string `db:"-"`
+	Audit
+}
+
+type statement struct {
+	sql     string
+	args    []any
+	primary bool // Whether a routing RDBMS would serve a query on the primary
+}
+
+// fakeStore records statements; queries return rows, a row being the values scanned
+// into the leading destinations.
+type fakeStore struct {
+	d        databases.Dialect
+	stmts    []statement
+	rows     [][]any
+	affected int64
+	lastID   int64
+}
+
+func (s *fakeStore) dialect() databases.Dialect { return s.d }
+
+func (s *fakeStore) exec(_ context.Context, query string, args ...any) (execResult, error) {
+	s.stmts = append(s.stmts, statement{sql: query, args: args, primary: true})
+	return execResult{
+		rowsAffected: s.affected,
+		lastInsertID: func() (int64, error) { return s.lastID, nil },
+	}, nil
+}
+
+func (s *fakeStore) query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error {
+	s.stmts = append(s.stmts, statement{query, args, databases.ShouldReadPrimary(ctx)})
+	for _, row := range s.rows {
+		err := fn(func(dest ...any) error {
+			for i := range min(len(dest), len(row)) {
+				reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(row[i]))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestRepository(t *testing.T, s *fakeStore, opts ...Option) *Repository[user, int64] {
+	t.Helper()
+	r, err := newRepository[user, int64](s, opts...)
+	if err != nil {
+		t.Fatalf("newRepository() error = %v", err)
+	}
+	return r
+}
+
+func assertStatement(t *testing.T, got statement, wantSQL string, wantArgs ...any) {
+	t.Helper()
+	if got.sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", got.sql, wantSQL)
+	}
+	if len(wantArgs) == 0 {
+		wantArgs = nil
+	}
+	if len(got.args) == 0 {
+		got.args = nil
+	}
+	if !reflect.DeepEqual(got.args, wantArgs) {
+		t.Fatalf("args = %v, want %v", got.args, wantArgs)
+	}
+}
+
+func TestNewRepository_Schema(t *testing.T) {
+	type noPK struct {
+		_    struct{} `table:"t"`
+		Name string
+	}
+	if _, err := newRepository[noPK, int64](&fakeStore{d: databases.DialectPostgres}); err == nil {
+		t.Fatalf("newRepository() without pk: want error")
+	}
+	if _, err := newRepository[user, int64](&fakeStore{d: databases.DialectPostgres}, WithTable("")); err != nil {
+		t.Fatalf("newRepository() error = %v", err)
+	}
+
+	r := newTestRepository(t, &fakeStore{d: databases.DialectPostgres}, WithTable("app.users"))
+	if r.Table() != "app.users" {
+		t.Fatalf("Table() = %q, want app.users", r.Table())
+	}
+	if got, want := r.schema.names(all), []string{"id", "email", "name", "created_at"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("columns = %v, want %v", got, want)
+	}
+}
+
+func TestRepository_Insert(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	pg := &fakeStore{d: databases.DialectPostgres, rows: [][]any{{int64(7)}}}
+	u := user{Email: "a@b.c", Name: "rama", Audit: Audit{CreatedAt: now}}
+	if err := newTestRepository(t, pg).Insert(context.Background(), &u); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	assertStatement(t, pg.stmts[0],
+		"INSERT INTO users (email,name,created_at) VALUES ($1,$2,$3) RETURNING id", "a@b.c", "rama", now)
+	if u.ID != 7 {
+		t.Fatalf("ID = %d, want 7 from RETURNING", u.ID)
+	}
+
+	my := &fakeStore{d: databases.DialectMySQL, affected: 1, lastID: 9}
+	u = user{Email: "a@b.c", Name: "rama", Audit: Audit{CreatedAt: now}}
+	if err := newTestRepository(t, my).Insert(context.Background(), &u); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	assertStatement(t, my.stmts[0], "INSERT INTO users (email,name,created_at) VALUES (?,?,?)", "a@b.c", "rama", now)
+	if u.ID != 9 {
+		t.Fatalf("ID = %d, want 9 from LastInsertId", u.ID)
+	}
+}
+
+func TestRepository_InsertMany(t *testing.T) {
+	s := &fakeStore{d: databases.DialectPostgres}
+	users := []user{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := newTestRepository(t, s, WithChunkSize(2)).InsertMany(context.Background(), users); err != nil {
+		t.Fatalf("InsertMany() error = %v", err)
+	}
+	if len(s.stmts) != 2 {
+		t.Fatalf("statements = %d, want 2 chunks", len(s.stmts))
+	}
+	assertStatement(t, s.stmts[1], "INSERT INTO users (email,name,created_at) VALUES ($1,$2,$3)", "", "c", time.Time{})
+}
+
+func TestRepository_Update(t *testing.T) {
+	s := &fakeStore{d: databases.DialectMySQL, affected: 1}
+	r := newTestRepository(t, s)
+	u := user{ID: 3, Email: "x@y.z", Name: "rama"}
+
+	if err := r.Update(context.Background(), &u, "name"); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	assertStatement(t, s.stmts[0], "UPDATE users SET name = ? WHERE id = ?", "rama", int64(3))
+
+	if err := r.Update(context.Background(), &u); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	assertStatement(t, s.stmts[1], "UPDATE users SET email = ?, name = ? WHERE id = ?", "x@y.z", "rama", int64(3))
+
+	for _, column := range []string{"id", "created_at", "unknown"} {
+		if err := r.Update(context.Background(), &u, column); err == nil {
+			t.Fatalf("Update(%q): want error", column)
+		}
+	}
+
+	s.affected = 0
+	err := r.Update(context.Background(), &u, "name")
+	if !apperror.IsNotFound(err) || !errors.Is(err, databases.ErrNoUpdateRow) {
+		t.Fatalf("Update() of missing row error = %v, want NotFound", err)
+	}
+	assertStatement(t, s.stmts[len(s.stmts)-1], "SELECT 1 FROM users WHERE id = ? LIMIT 1", int64(3))
+}
+
+func TestRepository_Upsert(t *testing.T) {
+	u := user{ID: 3, Email: "x@y.z", Name: "rama"}
+
+	pg := &fakeStore{d: databases.DialectPostgres, rows: [][]any{{int64(3)}}}
+	if err := newTestRepository(t, pg).Upsert(context.Background(), &u); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	assertStatement(t, pg.stmts[0],
+		"INSERT INTO users (id,email,name,created_at) VALUES ($1,$2,$3,$4) "+
+			"ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email, name = EXCLUDED.name RETURNING id",
+		int64(3), "x@y.z", "rama", time.Time{})
+
+	my := &fakeStore{d: databases.DialectMySQL, affected: 2}
+	if err := newTestRepository(t, my).Upsert(context.Background(), &u); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	assertStatement(t, my.stmts[0],
+		"INSERT INTO users (id,email,name,created_at) VALUES (?,?,?,?) "+
+			"ON DUPLICATE KEY UPDATE email = VALUES(email), name = VALUES(name)",
+		int64(3), "x@y.z", "rama", time.Time{})
+}
+
+func TestRepository_FindAndDelete(t *testing.T) {
+	s := &fakeStore{d: databases.DialectPostgres}
+	r := newTestRepository(t, s)
+
+	_, err := r.FindByID(context.Background(), 1)
+	if !apperror.IsNotFound(err) || !errors.Is(err, databases.ErrNoRowFound) {
+		t.Fatalf("FindByID() error = %v, want NotFound", err)
+	}
+	assertStatement(t, s.stmts[0], "SELECT id, email, name, created_at FROM users WHERE id = $1 LIMIT 1", int64(1))
+
+	s.rows = [][]any{{int64(12)}}
+	_, page, err := r.FindMany(context.Background(), Query{
+		Filters:    []filter.Filter{filter.NewStringFilter().Column("name").Eq("rama")},
+		Sorting:    primitive.Sorting{SortFields: []string{"name"}, SortDirections: []string{"desc"}},
+		Pagination: primitive.PaginationInput{Page: 2, PageSize: 5},
+	})
+	if err != nil {
+		t.Fatalf("FindMany() error = %v", err)
+	}
+	assertStatement(t, s.stmts[1], "SELECT COUNT(*) FROM users WHERE (name = $1)", "rama")
+	assertStatement(t, s.stmts[2],
+		"SELECT id, email, name, created_at FROM users WHERE (name = $1) ORDER BY name DESC LIMIT 5 OFFSET 5", "rama")
+	if page.TotalData != 12 || page.PageCount != 3 {
+		t.Fatalf("page = %+v, want 12 rows in 3 pages", page)
+	}
+
+	err = r.Delete(context.Background(), 1)
+	if !apperror.IsNotFound(err) || !errors.Is(err, databases.ErrNoDeleteRow) {
+		t.Fatalf("Delete() error = %v, want NotFound", err)
+	}
+	assertStatement(t, s.stmts[len(s.stmts)-1], "DELETE FROM users WHERE id = $1", int64(1))
+}
+
+func TestRepository_ReturningUsesPrimary(t *testing.T) {
+	s := &fakeStore{d: databases.DialectPostgres, rows: [][]any{{int64(7)}}}
+	r := newTestRepository(t, s)
+	ctx := databases.WithReadYourWrites(context.Background())
+
+	u := user{ID: 7, Name: "rama"}
+	if err := r.Insert(ctx, &u); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	if err := r.Update(ctx, &u, "name"); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := r.Upsert(ctx, &u); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	for _, stmt := range s.stmts {
+		if !stmt.primary {
+			t.Fatalf("%q routed to a replica, want primary", stmt.sql)
+		}
+	}
+	if !databases.ShouldReadPrimary(ctx) {
+		t.Fatalf("write not marked on the read-your-writes session")
+	}
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// column is a struct field mapped to a table column.
+type column struct {
+	name       string
+	index      []int
+	pk         bool
+	readonly   bool // Generated by the database, never written
+	insertOnly bool // Written by Insert, never by Update
+}
+
+// schema is the mapping of an entity struct to its table.
+type schema struct {
+	table   string
+	columns []column
+	pk      column
+}
+
+// parseSchema reads the table mapping of the struct type t from its tags:
+//
+//   - `table:"name"` on any field (typically `_ struct{}`) names the table;
+//   - `db:"column,opt,..."` names the column of a field, with the options pk, readonly
+//     and insertonly; untagged fields use the snake_cased field name;
+//   - `db:"-"` and unexported fields are skipped, embedded structs (and struct
+//     pointers) are flattened.
+func parseSchema(t reflect.Type) (*schema, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("repository: entity must be a struct, got %s", t)
+	}
+
+	fields, err := databases.StructFields(t)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schema{table: tableTag(t)}
+	pks := 0
+	for _, f := range fields {
+		c := column{name: f.Column, index: f.Index}
+		for _, opt := range f.Options {
+			switch opt {
+			case "pk":
+				c.pk = true
+			case "readonly":
+				c.readonly = true
+			case "insertonly":
+				c.insertOnly = true
+			default:
+				return nil, fmt.Errorf("repository: column %s: unknown db tag option %q", f.Column, opt)
+			}
+		}
+		if c.pk {
+			s.pk = c
+			pks++
+		}
+		s.columns = append(s.columns, c)
+	}
+	if pks != 1 {
+		return nil, fmt.Errorf("repository: %s must have exactly one `db:\",pk\"` field, found %d", t, pks)
+	}
+	return s, nil
+}
+
+// tableTag returns the first `table` tag of the fields of t, embedded structs included.
+func tableTag(t reflect.Type) string {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if table, ok := f.Tag.Lookup("table"); ok {
+			return table
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct {
+			if table := tableTag(ft); table != "" {
+				return table
+			}
+		}
+	}
+	return ""
+}
+
+// names returns the names of the columns matching keep.
+func (s *schema) names(keep func(column) bool) []string {
+	names := make([]string, 0, len(s.columns))
+	for _, c := range s.columns {
+		if keep(c) {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// column returns the column named name.
+func (s *schema) column(name string) (column, bool) {
+	for _, c := range s.columns {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+// values returns the field values of v for the given columns.
+func (s *schema) values(v reflect.Value, names []string) []any {
+	values := make([]any, len(names))
+	for i, name := range names {
+		c, _ := s.column(name)
+		if fv, err := v.FieldByIndexErr(c.index); err == nil {
+			values[i] = fv.Interface()
+		} // else promoted through a nil embedded pointer: NULL
+	}
+	return values
+}
+
+// pointers returns pointers to the fields of v for the given columns, as Scan destinations.
+func (s *schema) pointers(v reflect.Value, names []string) []any {
+	dest := make([]any, len(names))
+	for i, name := range names {
+		c, _ := s.column(name)
+		dest[i] = fieldAlloc(v, c.index).Addr().Interface()
+	}
+	return dest
+}
+
+// fieldAlloc returns the field of v at index, allocating the nil embedded struct
+// pointers it is promoted through.
+func fieldAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func all(column) bool { return true }
+
+func insertable(c column) bool { return !c.readonly }
+
+func readonly(c column) bool { return c.readonly }
+
+// updatable reports whether c is written by Update and the update part of Upsert.
+func updatable(c column) bool { return !c.pk && !c.readonly && !c.insertOnly }
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/sqlx"
+)
+
+// errNoLastInsertID is returned by stores whose driver does not report generated ids.
+var errNoLastInsertID = errors.New("repository: driver does not report the last insert id")
+
+// store adapts the pgxx and sqlx RDBMS to the few operations the repository needs.
+// Operations go through the RDBMS, so hooks, tracing and ambient transactions apply.
+type store interface {
+	dialect() databases.Dialect
+	exec(ctx context.Context, query string, args ...any) (execResult, error)
+	query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error
+}
+
+// execResult is the outcome of a statement run with store.exec.
+type execResult struct {
+	rowsAffected int64
+	lastInsertID func() (int64, error)
+}
+
+type pgxStore struct {
+	db pgxx.RDBMS
+}
+
+func (s *pgxStore) dialect() databases.Dialect {
+	return databases.DialectPostgres
+}
+
+func (s *pgxStore) exec(ctx context.Context, query string, args ...any) (execResult, error) {
+	tag, err := s.db.Exec(ctx, query, args...)
+	if err != nil {
+		return execResult{}, err
+	}
+	return execResult{
+		rowsAffected: tag.RowsAffected(),
+		lastInsertID: func() (int64, error) { return 0, errNoLastInsertID },
+	}, nil
+}
+
+func (s *pgxStore) query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = fn(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+type sqlStore struct {
+	db sqlx.RDBMS
+}
+
+func (s *sqlStore) dialect() databases.Dialect {
+	return s.db.Dialect()
+}
+
+func (s *sqlStore) exec(ctx context.Context, query string, args ...any) (execResult, error) {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return execResult{}, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return execResult{}, err
+	}
+	return execResult{rowsAffected: rows, lastInsertID: res.LastInsertId}, nil
+}
+
+func (s *sqlStore) query(ctx context.Context, query string, args []any, fn func(scan func(dest ...any) error) error) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = fn(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
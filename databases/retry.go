@@ -0,0 +1,127 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DefaultTxRetryCodes are the error codes retried by a TxRetryPolicy without RetryableCodes:
+// PostgreSQL serialization_failure (40001) and deadlock_detected (40P01),
+// and MySQL ER_LOCK_DEADLOCK (1213).
+var DefaultTxRetryCodes = []string{"40001", "40P01", "1213"}
+
+const (
+	defaultTxRetryBaseDelay = 10 * time.Millisecond
+	defaultTxRetryMaxDelay  = time.Second
+)
+
+// TxRetryPolicy configures how a transaction is re-run after a transient failure,
+// such as a serialization failure or a deadlock.
+// The whole transactional function is re-executed on a fresh transaction.
+type TxRetryPolicy struct {
+	MaxAttempts    int           // Total attempts including the first one. Values <= 1 disable retries.
+	BaseDelay      time.Duration // Backoff before the second attempt, doubled on each retry. Default: 10ms.
+	MaxDelay       time.Duration // Upper bound of the backoff. Default: 1s.
+	RetryableCodes []string      // SQLSTATE codes (PostgreSQL) or error numbers (MySQL). Default: DefaultTxRetryCodes.
+}
+
+// IsRetryable reports whether err carries one of the policy's retryable error codes.
+func (p TxRetryPolicy) IsRetryable(err error) bool {
+	code := ErrorCode(err)
+	if code == "" {
+		return false
+	}
+	codes := p.RetryableCodes
+	if len(codes) == 0 {
+		codes = DefaultTxRetryCodes
+	}
+	return slices.Contains(codes, code)
+}
+
+// Backoff returns the delay before the given attempt (2 for the first retry).
+// It uses exponential backoff with full jitter, capped at MaxDelay.
+func (p TxRetryPolicy) Backoff(attempt int) time.Duration {
+	base, maxDelay := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = defaultTxRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultTxRetryMaxDelay
+	}
+
+	d := maxDelay
+	if shift := attempt - 2; shift < 30 {
+		d = min(base<<max(shift, 0), maxDelay)
+	}
+	return rand.N(d) + 1
+}
+
+// Wait sleeps for the backoff of the given attempt, returning early with
+// ctx.Err() if ctx is done.
+func (p TxRetryPolicy) Wait(ctx context.Context, attempt int) error {
+	t := time.NewTimer(p.Backoff(attempt))
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// TxRetryError is returned when a transaction retried by a TxRetryPolicy still fails.
+// It reports how many attempts were made and wraps the error of the last attempt.
+type TxRetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *TxRetryError) Error() string {
+	return fmt.Sprintf("transaction failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *TxRetryError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the database error code carried by err: the SQLSTATE for
+// PostgreSQL errors or the error number for MySQL errors. It returns "" otherwise.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return strconv.Itoa(int(mysqlErr.Number))
+	}
+	return ""
+}
+
+type txAttemptKey struct{}
+
+// WithTxAttempt returns a context recording the 1-based attempt number of the
+// transaction it belongs to. It is set by DoTxContext so tracers can report it.
+func WithTxAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, txAttemptKey{}, attempt)
+}
+
+// TxAttemptFromContext returns the transaction attempt recorded by WithTxAttempt.
+func TxAttemptFromContext(ctx context.Context) (int, bool) {
+	attempt, ok := ctx.Value(txAttemptKey{}).(int)
+	return attempt, ok
+}
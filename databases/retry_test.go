@@ -0,0 +1,66 @@
+package databases
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestTxRetryPolicy_IsRetryable(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy TxRetryPolicy
+		err    error
+		want   bool
+	}{
+		{name: "pg serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "pg deadlock wrapped", err: fmt.Errorf("update: %w", &pgconn.PgError{Code: "40P01"}), want: true},
+		{name: "mysql deadlock", err: &mysql.MySQLError{Number: 1213}, want: true},
+		{name: "pg unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+		{name: "nil", err: nil, want: false},
+		{
+			name:   "custom codes",
+			policy: TxRetryPolicy{RetryableCodes: []string{"55P03"}},
+			err:    &pgconn.PgError{Code: "40001"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.IsRetryable(tt.err); got != tt.want {
+				t.Fatalf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTxRetryPolicy_Backoff(t *testing.T) {
+	p := TxRetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	for attempt, limit := range map[int]time.Duration{2: 10 * time.Millisecond, 3: 20 * time.Millisecond, 10: 50 * time.Millisecond, 100: 50 * time.Millisecond} {
+		for range 50 {
+			if d := p.Backoff(attempt); d <= 0 || d > limit {
+				t.Fatalf("Backoff(%d) = %v, want (0, %v]", attempt, d, limit)
+			}
+		}
+	}
+}
+
+func TestTxRetryError(t *testing.T) {
+	cause := &pgconn.PgError{Code: "40001"}
+	err := error(&TxRetryError{Attempts: 3, Err: cause})
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr != cause {
+		t.Fatalf("errors.As(TxRetryError) did not find the wrapped error")
+	}
+	if want := "transaction failed after 3 attempts: " + cause.Error(); err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+}
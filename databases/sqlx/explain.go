@@ -0,0 +1,98 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// explainTracerName is the instrumentation name of the db.explain spans.
+const explainTracerName = "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/sqlx"
+
+// explainer captures the plan of the slow queries sampled by an ObservabilityHook.
+type explainer struct {
+	sampler *databases.ExplainSampler
+	dialect databases.Dialect
+	db      *sql.DB
+}
+
+// newExplainers creates the explainers of the ObservabilityHooks configured with Explain,
+// keyed by hook. Their EXPLAIN statements run on db, outside of any transaction and
+// without hooks. Explainers belong to the RDBMS, so a hook shared by several RDBMS
+// explains each query on the database that ran it.
+// Dialects other than PostgreSQL and MySQL are not explained.
+func newExplainers(hooks []DBHook, db *sql.DB, dialect databases.Dialect) map[DBHook]*explainer {
+	explainers := make(map[DBHook]*explainer)
+	if dialect != databases.DialectPostgres && dialect != databases.DialectMySQL {
+		return explainers
+	}
+	for _, h := range hooks {
+		oh, ok := h.(*ObservabilityHook)
+		if !ok || oh.Explain == nil {
+			continue
+		}
+		explainers[h] = &explainer{
+			sampler: databases.NewExplainSampler(*oh.Explain),
+			dialect: dialect,
+			db:      db,
+		}
+	}
+	return explainers
+}
+
+// explain returns the plan summary of the query query.
+func (x *explainer) explain(ctx context.Context, query string, args []any) (databases.PlanSummary, error) {
+	explainQuery, err := databases.ExplainQuery(x.dialect, query)
+	if err != nil {
+		return databases.PlanSummary{}, err
+	}
+
+	var plan []byte
+	if err = x.db.QueryRowContext(ctx, explainQuery, args...).Scan(&plan); err != nil {
+		return databases.PlanSummary{}, err
+	}
+	return databases.ParsePlan(x.dialect, plan)
+}
+
+// logWithPlan explains query, then emits e with the plan summary. It runs in its own
+// goroutine, so the query that was slow is not delayed further. By then the span of
+// the query has usually ended, so the EXPLAIN and its plan are recorded on a
+// db.explain child span, started with the tracer provider of the query span.
+func (x *explainer) logWithPlan(ctx context.Context, e *zerolog.Event, query string, args []any, done func()) {
+	defer done()
+	ctx = context.WithoutCancel(ctx)
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer(explainTracerName).
+		Start(ctx, "db.explain", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	explainCtx, cancel := context.WithTimeout(ctx, x.sampler.Timeout())
+	defer cancel()
+
+	plan, err := x.explain(explainCtx, query, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		e.Str("explain_err", truncateString(err.Error(), defaultLogFieldMaxSize)).Msg("[SQL]")
+		return
+	}
+
+	dict := zerolog.Dict().
+		Float64("cost", plan.Cost).
+		Float64("rows", plan.Rows).
+		Strs("seq_scans", plan.SeqScans)
+	if len(plan.Plan) <= defaultLogFieldMaxSize {
+		dict = dict.RawJSON("json", plan.Plan)
+	}
+	e.Dict("plan", dict).Msg("[SQL]")
+
+	span.SetAttributes(
+		attribute.Float64("db.plan.cost", plan.Cost),
+		attribute.Float64("db.plan.rows", plan.Rows),
+		attribute.StringSlice("db.plan.seq_scans", plan.SeqScans),
+	)
+}
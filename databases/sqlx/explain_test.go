@@ -0,0 +1,45 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/rs/zerolog"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExplainer_RecordsPlanSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, query := tp.Tracer("test").Start(context.Background(), "query")
+	query.End()
+
+	d := &fakeDriver{result: []byte(`[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "users", "Total Cost": 42, "Plan Rows": 7}}]`)}
+	db := d.open("primary")
+	defer db.Close()
+	x := &explainer{
+		sampler: databases.NewExplainSampler(databases.ExplainConfig{}),
+		dialect: databases.DialectPostgres,
+		db:      db,
+	}
+	logger := zerolog.Nop()
+	x.logWithPlan(ctx, logger.Warn(), "SELECT * FROM users", nil, func() {})
+
+	for _, span := range recorder.Ended() {
+		if span.Name() != "db.explain" {
+			continue
+		}
+		if span.Parent().SpanID() != query.SpanContext().SpanID() {
+			t.Fatalf("db.explain parent = %s, want the query span", span.Parent().SpanID())
+		}
+		for _, attr := range span.Attributes() {
+			if attr.Key == "db.plan.cost" && attr.Value.AsFloat64() == 42 {
+				return
+			}
+		}
+		t.Fatalf("db.explain attributes = %v, want db.plan.cost 42", span.Attributes())
+	}
+	t.Fatalf("no db.explain span recorded")
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/observability"
 	"github.com/rs/zerolog"
 )
@@ -24,31 +25,50 @@ const (
 	OpTxBegin    Op = "tx_begin"    // Beginning a transaction
 	OpTxCommit   Op = "tx_commit"   // Committing a transaction
 	OpTxRollback Op = "tx_rollback" // Rolling back a transaction
+
+	OpSavepoint           Op = "savepoint"             // Creating a savepoint for a nested transaction
+	OpRollbackToSavepoint Op = "rollback_to_savepoint" // Rolling back a nested transaction to its savepoint
+	OpReleaseSavepoint    Op = "release_savepoint"     // Releasing the savepoint of a successful nested transaction
+
+	OpAfterCommit   Op = "after_commit"   // Running a callback registered with databases.AfterCommit
+	OpAfterRollback Op = "after_rollback" // Running a callback registered with databases.AfterRollback
 )
 
 // HookInfo contains detailed information about a database operation,
 // passed to hooks before and after the operation is executed.
 type HookInfo struct {
-	Op       Op        // The type of operation (query, exec, prepare, transaction)
-	SQL      string    // The SQL query string
-	Args     []any     // Query arguments, if any
-	InTx     bool      // True if the operation is executed inside a transaction
-	Prepared bool      // True if executed using a prepared statement or cache
-	CacheHit *bool     // Optional: true if the prepared statement was retrieved from cache, false if newly prepared
-	Start    time.Time // Start time of the operation (set in Before hook)
-	End      time.Time // End time of the operation (set in After hook)
-	Err      error     // Any error returned from the operation
-	Rows     *int64    // Optional: number of rows affected (Exec) or returned (Query)
+	Op       Op                // The type of operation (query, exec, prepare, transaction)
+	SQL      string            // The SQL query string
+	Args     []any             // Query arguments, if any
+	InTx     bool              // True if the operation is executed inside a transaction
+	Node     string            // Node that served the operation ("primary" or a replica name)
+	Attempt  int               // Transaction attempt (1-based) for transaction hooks; 0 otherwise
+	Prepared bool              // True if executed using a prepared statement or cache
+	CacheHit *bool             // Optional: true if the prepared statement was retrieved from cache, false if newly prepared
+	Start    time.Time         // Start time of the operation (set in Before hook)
+	End      time.Time         // End time of the operation (set in After hook)
+	Err      error             // Any error returned from the operation
+	Rows     *int64            // Optional: number of rows affected (Exec) or returned (Query)
+	Dialect  databases.Dialect // SQL dialect of the RDBMS, see WithDialect
+	Vetoed   bool              // True if a PolicyHook (or WithTenantRLS) vetoed the operation; it never reached the driver and Err holds the veto
+
+	entered    int                   // Number of hooks whose Before ran, see callAfter
+	explainers map[DBHook]*explainer // Explainers of the RDBMS running the operation, by hook
 }
 
 // DBHook defines the interface for database hooks.
 // Hooks can be used for logging, tracing, or metrics collection.
 //
 // The sequence for hook calls:
-//  1. Before(ctx, info) is called before the database operation starts.
+//  1. Before(ctx, info) is called before the database operation starts, in
+//     registration order (UseHook).
 //     - Can modify context or enrich HookInfo.
-//  2. After(ctx, info) is called after the database operation ends.
+//     - A PolicyHook is checked right after its own Before and may veto the operation.
+//  2. After(ctx, info) is called after the database operation ends, in reverse
+//     registration order, so the first registered hook wraps all others.
 //     - Has access to execution results, duration, and any errors.
+//     - Only hooks whose Before ran are called: after a veto, hooks registered
+//     after the vetoing policy see neither Before nor After.
 type DBHook interface {
 	// Before is called before the SQL operation begins.
 	// Can modify and return a new context.
@@ -90,6 +110,7 @@ func (h *DebugHook) After(ctx context.Context, info *HookInfo) {
 	attrs := []slog.Attr{
 		slog.String("op", string(info.Op)),
 		slog.Bool("in_tx", info.InTx),
+		slog.String("node", info.Node),
 		slog.Bool("prepared", info.Prepared),
 		slog.Any("cache_hit", boolPtrVal(info.CacheHit)),
 		slog.Duration("duration", dur),
@@ -111,6 +132,9 @@ type ObservabilityHook struct {
 	WithArgs      bool                 // If true, include SQL args in the log
 	Mode          ObservabilityLogMode // Controls which SQL operations are logged. Default: all.
 	SlowThreshold time.Duration        // Minimum duration treated as slow. Default: 500ms.
+
+	// Explain enables the EXPLAIN capture of slow queries, see WithObservabilityExplain.
+	Explain *databases.ExplainConfig
 }
 
 func (h *ObservabilityHook) Before(ctx context.Context, info *HookInfo) context.Context {
@@ -134,6 +158,12 @@ func (h *ObservabilityHook) After(ctx context.Context, info *HookInfo) {
 		Dur("duration", dur).
 		Str("sql", truncateString(info.SQL, defaultLogFieldMaxSize))
 
+	if info.Node != "" {
+		e = e.Str("node", info.Node)
+	}
+	if info.Attempt > 0 {
+		e = e.Int("attempt", info.Attempt)
+	}
 	if info.CacheHit != nil {
 		e = e.Bool("cache_hit", *info.CacheHit)
 	}
@@ -151,6 +181,12 @@ func (h *ObservabilityHook) After(ctx context.Context, info *HookInfo) {
 		e = e.Interface("args", truncateArgs(info.Args, defaultLogFieldMaxSize))
 	}
 
+	if x := info.explainers[h]; x != nil && isSlow && !info.Vetoed && (info.Op == OpQuery || info.Op == OpQueryRow) {
+		if done, ok := x.sampler.Acquire(x.dialect, info.SQL); ok {
+			go x.logWithPlan(ctx, e, info.SQL, info.Args, done)
+			return
+		}
+	}
 	e.Msg("[SQL]")
 }
 
@@ -2,6 +2,8 @@ package sqlx
 
 import (
 	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 )
 
 // Option defines a configuration option for RDBMS.
@@ -47,6 +49,73 @@ func UseDebug(withArgs bool) Option {
 	})
 }
 
+// WithCursorSecret sets the secret used to sign and verify the opaque cursors
+// produced by QuerySqCursorPagination. Cursor pagination fails without it.
+func WithCursorSecret(secret string) Option {
+	return optFunc(func(rc *rdbmsConfig) {
+		rc.cursorSecret = secret
+	})
+}
+
+// WithReplicaPolicy sets how NewRoutingRDBMS selects a replica for reads.
+// Default: databases.ReplicaRoundRobin.
+func WithReplicaPolicy(policy databases.ReplicaPolicy) Option {
+	return optFunc(func(rc *rdbmsConfig) {
+		rc.replicaConfig.Policy = policy
+	})
+}
+
+// WithReplicaHealthCheck configures replica health-based ejection for NewRoutingRDBMS.
+// Replicas are pinged every interval and ejected when a ping fails or after maxFailures
+// consecutive connection errors. Defaults: 5s interval, 3 failures.
+func WithReplicaHealthCheck(interval time.Duration, maxFailures int) Option {
+	return optFunc(func(rc *rdbmsConfig) {
+		rc.replicaConfig.HealthCheckInterval = interval
+		rc.replicaConfig.MaxFailures = maxFailures
+	})
+}
+
+// WithTxRetry enables automatic retries of top-level DoTxContext transactions that fail
+// with a retryable error code, such as PostgreSQL 40001/40P01 or MySQL 1213.
+// See databases.TxRetryPolicy for the defaults.
+func WithTxRetry(policy databases.TxRetryPolicy) Option {
+	return optFunc(func(rc *rdbmsConfig) {
+		rc.txRetry = policy
+	})
+}
+
+// WithStmtCache enables an LRU cache of up to size prepared statements keyed by SQL text.
+// Queries and execs served by the primary reuse the cached statement (bound to the
+// transaction with StmtContext inside DoTxContext); replica reads are not prepared.
+// Statements are re-prepared after connection errors and closed by Close.
+// HookInfo.Prepared and HookInfo.CacheHit report cache usage.
+func WithStmtCache(size int) Option {
+	return optFunc(func(rc *rdbmsConfig) {
+		rc.stmtCacheSize = size
+	})
+}
+
+// WithTenantRLS applies the tenant of the context (databases.WithTenant) at the beginning
+// of every transaction so PostgreSQL row-level security policies can enforce it.
+// Statements outside a transaction fail closed with databases.ErrMissingTenant or
+// databases.ErrTenantTxRequired, without reaching the server.
+// A transaction begun without a tenant or databases.WithTenantBypass fails with
+// databases.ErrMissingTenant; on other dialects every transaction fails.
+// See databases.TenantRLS for the settings and policies.
+func WithTenantRLS(rls databases.TenantRLS) Option {
+	return optFunc(func(rc *rdbmsConfig) {
+		rc.tenantRLS = &rls
+	})
+}
+
+// WithDialect sets the SQL dialect reported by Dialect, e.g. to configure
+// dialect-aware filters. Default: detected from the database driver.
+func WithDialect(d databases.Dialect) Option {
+	return optFunc(func(rc *rdbmsConfig) {
+		rc.dialect = d
+	})
+}
+
 type ObservabilityHookOption func(*ObservabilityHook)
 
 // UseObservability is a helper option to attach an ObservabilityHook for SQL logs.
@@ -86,3 +155,16 @@ func WithObservabilitySlowThreshold(d time.Duration) ObservabilityHookOption {
 		h.SlowThreshold = d
 	}
 }
+
+// WithObservabilityExplain captures the plan of sampled slow SELECTs: an EXPLAIN
+// (EXPLAIN (FORMAT JSON) on PostgreSQL, EXPLAIN FORMAT=JSON on MySQL) runs
+// asynchronously on a separate connection of the primary, and the slow query log entry
+// is emitted once it completes, with the plan summary (cost, estimated rows, full
+// table scans). The EXPLAIN and the summary are also recorded on a "db.explain" span,
+// child of the span of the query context. See databases.ExplainConfig for the sampling,
+// rate limit and denylist.
+func WithObservabilityExplain(cfg databases.ExplainConfig) ObservabilityHookOption {
+	return func(h *ObservabilityHook) {
+		h.Explain = &cfg
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 )
 
@@ -20,6 +21,9 @@ type RDBMS interface {
 	queryExecutor
 	Close() error
 	Ping(ctx context.Context) error
+
+	// Dialect returns the SQL dialect of the database.
+	Dialect() databases.Dialect
 }
 
 // WriterCommand defines write operations (INSERT, UPDATE, DELETE) on the database.
@@ -36,6 +40,10 @@ type ReadQuery interface {
 type WriterCommandSquirrel interface {
 	// ExecSq executes a write query (INSERT, UPDATE, DELETE) built with Squirrel.
 	ExecSq(ctx context.Context, query squirrel.Sqlizer) (sql.Result, error)
+
+	// UpdateWithVersion executes an UPDATE guarded by a version column and returns the new version.
+	// It returns an apperror.Conflict if the version is stale and an apperror.NotFound if the row is missing.
+	UpdateWithVersion(ctx context.Context, update databases.VersionedUpdate) (databases.Version, error)
 }
 
 // ReadQuerySquirrel defines read operations using Squirrel SQL builders.
@@ -56,6 +64,19 @@ type ReadQuerySquirrel interface {
 		fn func(rows *sql.Rows) error,
 	) (primitive.PaginationOutput, error)
 
+	// QuerySqCursorPagination executes a keyset (cursor) paginated SELECT query built with Squirrel.
+	// keyset defines the ordering; query MUST NOT contain ORDER BY or LIMIT.
+	// countQuery is only executed when paginationInput.WithTotal is true.
+	// fn is called once per row of the page: it must Scan the row and return the
+	// values of the keyset columns (in keyset order) used to build the cursors.
+	QuerySqCursorPagination(
+		ctx context.Context,
+		countQuery, query squirrel.SelectBuilder,
+		keyset primitive.Keyset,
+		paginationInput primitive.CursorPaginationInput,
+		fn func(rows *sql.Rows) ([]any, error),
+	) (primitive.CursorPaginationOutput, error)
+
 	// QueryRowSq executes a SELECT query built with Squirrel and returns a single row.
 	// If no rows are found, sql.ErrNoRows is returned.
 	QueryRowSq(ctx context.Context, query squirrel.Sqlizer) (*sql.Row, error)
@@ -3,18 +3,45 @@ package sqlx
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 )
 
 type rdbms struct {
-	db    *sql.DB
-	tx    *sql.Tx
-	hooks []DBHook
+	db           *sql.DB
+	tx           *sql.Tx
+	hooks        []DBHook
+	cursorSecret string
+	replicas     *databases.ReplicaRouter[*sql.DB]
+	stopHealth   func()
+	stmts        *stmtCache
+
+	// savepointDepth is the nesting level of DoTxContext calls inside tx.
+	savepointDepth int
+	txRetry        databases.TxRetryPolicy
+	dialect        databases.Dialect
+	tenantRLS      *databases.TenantRLS
+	explainers     map[DBHook]*explainer
+
+	// txCallbacks collects AfterCommit / AfterRollback callbacks of the transaction
+	// scope r belongs to; nil outside a transaction.
+	txCallbacks *databases.TxCallbacks
 }
 
 type rdbmsConfig struct {
-	hooks []DBHook
+	hooks         []DBHook
+	cursorSecret  string
+	replicaConfig databases.ReplicaRouterConfig
+	txRetry       databases.TxRetryPolicy
+	stmtCacheSize int
+	dialect       databases.Dialect
+	tenantRLS     *databases.TenantRLS
 }
 
 // NewRDBMS constructs an RDBMS instance on top of *sql.DB with optional hooks
@@ -25,37 +52,140 @@ func NewRDBMS(db *sql.DB, opts ...Option) *rdbms {
 		o.apply(cfg)
 	}
 
-	return &rdbms{
-		db:    db,
-		hooks: cfg.hooks,
+	r := &rdbms{
+		db:           db,
+		hooks:        cfg.hooks,
+		cursorSecret: cfg.cursorSecret,
+		txRetry:      cfg.txRetry,
+		tenantRLS:    cfg.tenantRLS,
+		stmts:        newStmtCache(cfg.stmtCacheSize),
+		dialect:      resolveDialect(db, cfg.dialect),
 	}
+	r.explainers = newExplainers(r.hooks, db, r.dialect)
+	return r
+}
+
+// NewRoutingRDBMS constructs an RDBMS that sends writes and transactions to primary
+// and spreads QueryContext/QueryRowContext (and the Squirrel variants) across replicas.
+//
+// Routing rules:
+//   - Everything inside DoTxContext is served by the primary.
+//   - Reads go to the primary when no replica is healthy, when ctx was marked with
+//     databases.UsePrimary, or after a write within a databases.WithReadYourWrites context.
+//   - Replicas failing health checks (see WithReplicaHealthCheck) are ejected until they recover.
+//
+// The node that served each operation is reported in HookInfo.Node.
+func NewRoutingRDBMS(primary *sql.DB, replicas []*sql.DB, opts ...Option) *rdbms {
+	cfg := defaultConfig()
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+
+	r := &rdbms{
+		db:           primary,
+		hooks:        cfg.hooks,
+		cursorSecret: cfg.cursorSecret,
+		txRetry:      cfg.txRetry,
+		tenantRLS:    cfg.tenantRLS,
+		stmts:        newStmtCache(cfg.stmtCacheSize),
+		dialect:      resolveDialect(primary, cfg.dialect),
+	}
+	r.explainers = newExplainers(r.hooks, primary, r.dialect)
+	if len(replicas) == 0 {
+		return r
+	}
+
+	nodes := make([]*databases.ReplicaNode[*sql.DB], len(replicas))
+	for i, db := range replicas {
+		nodes[i] = &databases.ReplicaNode[*sql.DB]{DB: db}
+	}
+	r.replicas = databases.NewReplicaRouter(nodes, cfg.replicaConfig)
+	r.stopHealth = r.replicas.StartHealthCheck(func(ctx context.Context, db *sql.DB) error {
+		return db.PingContext(ctx)
+	})
+	return r
+}
+
+// readNode selects the connection pool that serves a query outside a transaction.
+// A query that writes (e.g. INSERT ... RETURNING) is served by the primary and marks
+// the read-your-writes session of ctx.
+// It returns a nil replica node when the primary is selected.
+func (r *rdbms) readNode(ctx context.Context, query string) (*sql.DB, *databases.ReplicaNode[*sql.DB]) {
+	if databases.IsWriteStatement(r.dialect, query) {
+		databases.MarkWrite(ctx)
+		return r.db, nil
+	}
+	if r.replicas == nil || databases.ShouldReadPrimary(ctx) {
+		return r.db, nil
+	}
+	node, ok := r.replicas.Pick()
+	if !ok {
+		return r.db, nil
+	}
+	return node.DB, node
 }
 
 // QueryContext executes a query that returns rows, typically a SELECT.
-// If r is inside a transaction, it uses that tx; otherwise it uses the base *sql.DB.
-// Hook timing and error are recorded in HookInfo (OpQuery).
+// If r is inside a transaction, it uses that tx; otherwise it uses the base *sql.DB
+// or, for a routing RDBMS, a healthy replica.
+// Hook timing, error and serving node are recorded in HookInfo (OpQuery).
 func (r *rdbms) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if tx, ok := r.ambientTx(ctx); ok {
+		return tx.QueryContext(ctx, query, args...)
+	}
+
 	info := &HookInfo{
 		Op:       OpQuery,
 		SQL:      query,
 		Args:     args,
 		InTx:     r.tx != nil,
 		Prepared: false,
+		Node:     databases.NodePrimary,
 		Start:    time.Now(),
 	}
-	ctx = r.callBefore(ctx, info)
+
+	var (
+		db   *sql.DB
+		node *databases.ReplicaNode[*sql.DB]
+	)
+	if r.tx == nil {
+		db, node = r.readNode(ctx, query)
+		if node != nil {
+			info.Node = node.Name
+		}
+	}
+	cached, useStmt := r.lookupStmt(db, query, info)
+	ctx, err := r.checkBefore(ctx, info)
+	if err != nil {
+		info.End = time.Now()
+		r.callAfter(ctx, info)
+		return nil, err
+	}
 
 	var (
 		rows *sql.Rows
-		err  error
+		stmt *sql.Stmt
 	)
-	if r.tx != nil {
-		rows, err = r.tx.QueryContext(ctx, query, args...)
-	} else {
-		rows, err = r.db.QueryContext(ctx, query, args...)
+	if useStmt {
+		var base *sql.Stmt
+		if stmt, base = r.prepareStmt(ctx, query, cached, info); stmt != nil {
+			rows, err = stmt.QueryContext(ctx, args...)
+			r.invalidateStmt(query, base, err)
+			if isStmtClosedError(err) {
+				stmt = nil
+			}
+		}
+	}
+	if stmt == nil {
+		if r.tx != nil {
+			rows, err = r.tx.QueryContext(ctx, query, args...)
+		} else {
+			rows, err = db.QueryContext(ctx, query, args...)
+		}
 	}
 	info.Err = err
 	info.End = time.Now()
+	r.replicas.Observe(node, info.End.Sub(info.Start), err)
 	r.callAfter(ctx, info)
 	return rows, err
 }
@@ -63,47 +193,101 @@ func (r *rdbms) QueryContext(ctx context.Context, query string, args ...any) (*s
 // QueryRowContext executes a query that is expected to return at most one row.
 // Errors from the underlying driver are usually reported at Scan time on the returned *sql.Row.
 // Hook timing is recorded (OpQueryRow). Any immediate driver error will be set on HookInfo.Err.
+// Outside a transaction, a routing RDBMS serves the query from a healthy replica.
 func (r *rdbms) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if tx, ok := r.ambientTx(ctx); ok {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+
 	info := &HookInfo{
 		Op:       OpQueryRow,
 		SQL:      query,
 		Args:     args,
 		InTx:     r.tx != nil,
 		Prepared: false,
+		Node:     databases.NodePrimary,
 		Start:    time.Now(),
 	}
-	ctx = r.callBefore(ctx, info)
+
+	var (
+		db   *sql.DB
+		node *databases.ReplicaNode[*sql.DB]
+	)
+	if r.tx == nil {
+		db, node = r.readNode(ctx, query)
+		if node != nil {
+			info.Node = node.Name
+		}
+	}
+	cached, useStmt := r.lookupStmt(db, query, info)
+	ctx, err := r.checkBefore(ctx, info)
 	defer func() { info.End = time.Now(); r.callAfter(ctx, info) }()
+	if err != nil {
+		return vetoedRow(ctx, err)
+	}
 
+	if useStmt {
+		if stmt, base := r.prepareStmt(ctx, query, cached, info); stmt != nil {
+			row := stmt.QueryRowContext(ctx, args...)
+			r.invalidateStmt(query, base, row.Err())
+			if !isStmtClosedError(row.Err()) {
+				return row
+			}
+		}
+	}
 	if r.tx != nil {
 		return r.tx.QueryRowContext(ctx, query, args...)
 	}
-	return r.db.QueryRowContext(ctx, query, args...)
+	row := db.QueryRowContext(ctx, query, args...)
+	r.replicas.Observe(node, time.Since(info.Start), row.Err())
+	return row
 }
 
 // ExecContext executes a statement that does not return rows (INSERT/UPDATE/DELETE/DDL).
 // If inside a transaction, the tx is used; otherwise the base *sql.DB is used.
 // Hook timing and RowsAffected (if available) are recorded (OpExec).
 func (r *rdbms) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if tx, ok := r.ambientTx(ctx); ok {
+		return tx.ExecContext(ctx, query, args...)
+	}
+
 	info := &HookInfo{
 		Op:       OpExec,
 		SQL:      query,
 		Args:     args,
 		InTx:     r.tx != nil,
 		Prepared: false,
+		Node:     databases.NodePrimary,
 		Start:    time.Now(),
 	}
-	ctx = r.callBefore(ctx, info)
+	databases.MarkWrite(ctx)
+	cached, useStmt := r.lookupStmt(r.db, query, info)
+	ctx, err := r.checkBefore(ctx, info)
 	defer func() { info.End = time.Now(); r.callAfter(ctx, info) }()
+	if err != nil {
+		return nil, err
+	}
 
 	var (
-		res sql.Result
-		err error
+		res  sql.Result
+		stmt *sql.Stmt
 	)
-	if r.tx != nil {
-		res, err = r.tx.ExecContext(ctx, query, args...)
-	} else {
-		res, err = r.db.ExecContext(ctx, query, args...)
+	if useStmt {
+		var base *sql.Stmt
+		if stmt, base = r.prepareStmt(ctx, query, cached, info); stmt != nil {
+			res, err = stmt.ExecContext(ctx, args...)
+			r.invalidateStmt(query, base, err)
+			if isStmtClosedError(err) {
+				stmt = nil
+			}
+		}
+	}
+	if stmt == nil {
+		if r.tx != nil {
+			res, err = r.tx.ExecContext(ctx, query, args...)
+		} else {
+			res, err = r.db.ExecContext(ctx, query, args...)
+		}
 	}
 	if err != nil {
 		info.Err = err
@@ -118,50 +302,99 @@ func (r *rdbms) ExecContext(ctx context.Context, query string, args ...any) (sql
 	return res, nil
 }
 
-// PrepareContext creates a prepared statement on the base *sql.DB.
-// NOTE: The tx-bound variant is commented out—if you want statements bound to the
-// current transaction, you may switch to r.tx.PrepareContext when r.tx != nil.
+// PrepareContext creates a prepared statement, bound to the transaction when r is
+// inside one, otherwise on the base *sql.DB. The caller owns the returned statement
+// and must close it; it never comes from the statement cache (see WithStmtCache).
 // Hook timing and error are recorded (OpPrepare).
 func (r *rdbms) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if tx, ok := r.ambientTx(ctx); ok {
+		return tx.PrepareContext(ctx, query)
+	}
+
 	info := &HookInfo{
 		Op:    OpPrepare,
 		SQL:   query,
 		InTx:  r.tx != nil,
+		Node:  databases.NodePrimary,
 		Start: time.Now(),
 	}
-	ctx = r.callBefore(ctx, info)
+	ctx, err := r.checkBefore(ctx, info)
 	defer func() { info.End = time.Now(); r.callAfter(ctx, info) }()
+	if err != nil {
+		return nil, err
+	}
 
-	// If you need tx-scoped prepared statements, uncomment:
-	// if r.tx != nil {
-	// 	st, err := r.tx.PrepareContext(ctx, query)
-	// 	info.Err = err
-	// 	return st, err
-	// }
-	st, err := r.db.PrepareContext(ctx, query)
+	var st *sql.Stmt
+	if r.tx != nil {
+		st, err = r.tx.PrepareContext(ctx, query)
+	} else {
+		st, err = r.db.PrepareContext(ctx, query)
+	}
 	info.Err = err
 	return st, err
 }
 
 // DoTxContext runs fn within a database transaction.
-// If r is already inside a transaction, fn is executed using the current receiver (no new tx).
+// If r is already inside a transaction, or ctx carries an ambient transaction for this
+// database, fn is executed as a nested transaction inside a SAVEPOINT of that
+// transaction: if fn fails, only its work is rolled back (ROLLBACK TO SAVEPOINT) and
+// the outer transaction continues. Savepoint events are surfaced via hooks
+// (OpSavepoint, OpRollbackToSavepoint, OpReleaseSavepoint).
 // Otherwise, a child rdbms with tx-bound context is created. Commit/rollback events are
 // surfaced via hooks (OpTxBegin, OpTxCommit, OpTxRollback).
 //
+// The ctx passed to fn carries the transaction, so any call made with it on the base
+// RDBMS (e.g. repositories holding the non-transactional RDBMS) transparently joins
+// the transaction. Use WithoutTx to opt out for a specific call. Callbacks registered
+// with it via databases.AfterCommit / databases.AfterRollback run once the transaction
+// has ended (OpAfterCommit, OpAfterRollback).
+//
 // Behavior:
 //   - Panic inside fn: transaction is rolled back, then panic is rethrown.
 //   - fn returns error: transaction is rolled back, error is returned.
 //   - fn returns nil: transaction is committed; commit error (if any) is returned.
+//
+// With WithTxRetry, a top-level transaction failing with a retryable error code
+// (e.g. serialization failure or deadlock) is rolled back and fn is re-run on a fresh
+// transaction after a jittered backoff. The attempt number is reported in HookInfo.Attempt
+// and via databases.TxAttemptFromContext; if the last attempt still fails, the error is
+// a *databases.TxRetryError carrying the number of attempts.
 func (r *rdbms) DoTxContext(ctx context.Context, opt *sql.TxOptions, fn func(ctx context.Context, tx RDBMS) error) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
 	if r.tx != nil {
-		return fn(ctx, r)
+		return r.doSavepoint(ctx, fn)
+	}
+	if tx, ok := r.ambientTx(ctx); ok {
+		return tx.doSavepoint(ctx, fn)
+	}
+
+	if opt == nil || !opt.ReadOnly {
+		databases.MarkWrite(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = r.doTx(ctx, opt, fn, attempt)
+		if err == nil || attempt >= r.txRetry.MaxAttempts || !r.txRetry.IsRetryable(err) {
+			if err != nil && attempt > 1 {
+				err = &databases.TxRetryError{Attempts: attempt, Err: err}
+			}
+			return err
+		}
+
+		if errWait := r.txRetry.Wait(ctx, attempt+1); errWait != nil {
+			return &databases.TxRetryError{Attempts: attempt, Err: errors.Join(err, errWait)}
+		}
 	}
+}
+
+// doTx runs a single attempt of a top-level transaction for DoTxContext.
+func (r *rdbms) doTx(ctx context.Context, opt *sql.TxOptions, fn func(ctx context.Context, tx RDBMS) error, attempt int) (err error) {
+	ctx = databases.WithTxAttempt(ctx, attempt)
 
-	wrapperHook := &HookInfo{Op: "TX_WRAPPER", Start: time.Now()}
+	wrapperHook := &HookInfo{Op: "TX_WRAPPER", Node: databases.NodePrimary, Attempt: attempt, Start: time.Now()}
 	ctx = r.callBefore(ctx, wrapperHook)
 	defer func() {
 		wrapperHook.End = time.Now()
@@ -169,8 +402,13 @@ func (r *rdbms) DoTxContext(ctx context.Context, opt *sql.TxOptions, fn func(ctx
 		r.callAfter(ctx, wrapperHook)
 	}()
 
-	beginHook := &HookInfo{Op: OpTxBegin, Start: time.Now()}
-	ctxBegin := r.callBefore(ctx, beginHook)
+	beginHook := &HookInfo{Op: OpTxBegin, Node: databases.NodePrimary, Attempt: attempt, Start: time.Now()}
+	ctxBegin, err := r.checkBefore(ctx, beginHook)
+	if err != nil {
+		beginHook.End = time.Now()
+		r.callAfter(ctxBegin, beginHook)
+		return err
+	}
 
 	tx, err := r.db.BeginTx(ctxBegin, opt)
 	beginHook.Err, beginHook.End = err, time.Now()
@@ -180,35 +418,39 @@ func (r *rdbms) DoTxContext(ctx context.Context, opt *sql.TxOptions, fn func(ctx
 		return err
 	}
 
-	child := &rdbms{db: r.db, tx: tx, hooks: r.hooks}
+	child := r.txChild(tx, 0)
+	child.txCallbacks = databases.NewTxCallbacks()
+	txCtx := databases.WithTxCallbacks(withTx(ctx, child), child.txCallbacks)
 
 	defer func() {
 		if p := recover(); p != nil {
-			rollHook := &HookInfo{Op: OpTxRollback, Start: time.Now()}
-			ctxRoll := r.callBefore(ctx, rollHook)
+			rollHook := &HookInfo{Op: OpTxRollback, Node: databases.NodePrimary, Attempt: attempt, Start: time.Now()}
+			ctxRoll := r.callBefore(txCtx, rollHook)
 
 			_ = tx.Rollback()
 
 			rollHook.Err = fmt.Errorf("panic: %v", p)
 			rollHook.End = time.Now()
 			r.callAfter(ctxRoll, rollHook)
+			r.runTxCallbacks(ctx, OpAfterRollback, child.txCallbacks.OnRollback(), attempt)
 			panic(p)
 		}
 
 		if err != nil {
-			rollHook := &HookInfo{Op: OpTxRollback, Start: time.Now()}
-			ctxRoll := r.callBefore(ctx, rollHook)
+			rollHook := &HookInfo{Op: OpTxRollback, Node: databases.NodePrimary, Attempt: attempt, Start: time.Now()}
+			ctxRoll := r.callBefore(txCtx, rollHook)
 
 			_ = tx.Rollback()
 
 			rollHook.Err = err
 			rollHook.End = time.Now()
 			r.callAfter(ctxRoll, rollHook)
+			r.runTxCallbacks(ctx, OpAfterRollback, child.txCallbacks.OnRollback(), attempt)
 			return
 		}
 
-		commitHook := &HookInfo{Op: OpTxCommit, Start: time.Now()}
-		ctxCommit := r.callBefore(ctx, commitHook)
+		commitHook := &HookInfo{Op: OpTxCommit, Node: databases.NodePrimary, Attempt: attempt, Start: time.Now()}
+		ctxCommit := r.callBefore(txCtx, commitHook)
 
 		cerr := tx.Commit()
 
@@ -217,34 +459,207 @@ func (r *rdbms) DoTxContext(ctx context.Context, opt *sql.TxOptions, fn func(ctx
 
 		if cerr != nil {
 			err = cerr
+			r.runTxCallbacks(ctx, OpAfterRollback, child.txCallbacks.OnRollback(), attempt)
+			return
+		}
+		r.runTxCallbacks(ctx, OpAfterCommit, child.txCallbacks.OnCommit(), attempt)
+	}()
+
+	if err = child.applyTenant(txCtx); err != nil {
+		return err
+	}
+	return fn(txCtx, child)
+}
+
+// applyTenant exposes the tenant of ctx to row-level security policies for the rest
+// of the transaction, see WithTenantRLS. It is a no-op without WithTenantRLS.
+func (r *rdbms) applyTenant(ctx context.Context) error {
+	if r.tenantRLS == nil {
+		return nil
+	}
+	stmt, args, err := r.tenantRLS.Statement(ctx, r.dialect)
+	if err != nil {
+		return err
+	}
+	stmt = r.dialect.Rebind(stmt)
+
+	info := &HookInfo{Op: OpExec, SQL: stmt, Args: args, InTx: true, Node: databases.NodePrimary, Start: time.Now()}
+	ctx, err = r.checkBefore(ctx, info)
+	if err == nil {
+		_, err = r.tx.ExecContext(ctx, stmt, args...)
+	}
+	info.Err = err
+	info.End = time.Now()
+	r.callAfter(ctx, info)
+	return err
+}
+
+// doSavepoint runs fn as a nested transaction inside a SAVEPOINT of r.tx.
+//
+// Behavior:
+//   - Panic inside fn: rolled back to the savepoint, AfterRollback callbacks of the
+//     savepoint run, then panic is rethrown.
+//   - fn returns error: rolled back to the savepoint, AfterRollback callbacks of the
+//     savepoint run, error is returned; the outer transaction stays usable.
+//   - fn returns nil: the savepoint is released and its callbacks move to the
+//     enclosing scope, waiting for its outcome.
+func (r *rdbms) doSavepoint(ctx context.Context, fn func(ctx context.Context, tx RDBMS) error) (err error) {
+	child := r.txChild(r.tx, r.savepointDepth+1)
+	child.txCallbacks = databases.NewTxCallbacks()
+	name := "sp_" + strconv.Itoa(child.savepointDepth)
+
+	if err = r.execSavepoint(ctx, OpSavepoint, "SAVEPOINT "+name, nil); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = r.execSavepoint(ctx, OpRollbackToSavepoint, "ROLLBACK TO SAVEPOINT "+name, fmt.Errorf("panic: %v", p))
+			r.runTxCallbacks(ctx, OpAfterRollback, child.txCallbacks.OnRollback(), 0)
+			panic(p)
+		}
+
+		if err != nil {
+			if rerr := r.execSavepoint(ctx, OpRollbackToSavepoint, "ROLLBACK TO SAVEPOINT "+name, err); rerr != nil {
+				err = errors.Join(err, rerr)
+			}
+			r.runTxCallbacks(ctx, OpAfterRollback, child.txCallbacks.OnRollback(), 0)
+			return
+		}
+
+		if err = r.execSavepoint(ctx, OpReleaseSavepoint, "RELEASE SAVEPOINT "+name, nil); err == nil && r.txCallbacks != nil {
+			child.txCallbacks.MergeInto(r.txCallbacks)
 		}
 	}()
 
-	return fn(ctx, child)
+	return fn(databases.WithTxCallbacks(withTx(ctx, child), child.txCallbacks), child)
+}
+
+// runTxCallbacks runs the AfterCommit or AfterRollback callbacks of a finished
+// transaction scope in registration order, surfacing each via hooks (op).
+// A failing or panicking callback is reported on HookInfo.Err and does not stop the others.
+func (r *rdbms) runTxCallbacks(ctx context.Context, op Op, callbacks []databases.TxCallback, attempt int) {
+	for _, fn := range callbacks {
+		info := &HookInfo{Op: op, InTx: r.tx != nil, Node: databases.NodePrimary, Attempt: attempt, Start: time.Now()}
+		cbCtx := r.callBefore(ctx, info)
+		info.Err = databases.RunTxCallback(cbCtx, fn)
+		info.End = time.Now()
+		r.callAfter(cbCtx, info)
+	}
+}
+
+// execSavepoint executes a savepoint statement on r.tx, surfacing it via hooks.
+// cause is the error that triggered a rollback, reported on HookInfo.Err.
+func (r *rdbms) execSavepoint(ctx context.Context, op Op, stmt string, cause error) error {
+	info := &HookInfo{Op: op, SQL: stmt, InTx: true, Node: databases.NodePrimary, Start: time.Now()}
+	ctx, err := r.checkBefore(ctx, info)
+	if err != nil {
+		info.End = time.Now()
+		r.callAfter(ctx, info)
+		return err
+	}
+
+	_, err = r.tx.ExecContext(ctx, stmt)
+
+	info.Err = cause
+	if err != nil {
+		info.Err = errors.Join(cause, err)
+	}
+	info.End = time.Now()
+	r.callAfter(ctx, info)
+	return err
+}
+
+// txChild returns a transactional rdbms bound to tx at the given savepoint depth.
+func (r *rdbms) txChild(tx *sql.Tx, savepointDepth int) *rdbms {
+	return &rdbms{
+		db:             r.db,
+		tx:             tx,
+		hooks:          r.hooks,
+		cursorSecret:   r.cursorSecret,
+		stmts:          r.stmts,
+		savepointDepth: savepointDepth,
+		dialect:        r.dialect,
+		tenantRLS:      r.tenantRLS,
+		explainers:     r.explainers,
+	}
+}
+
+// Dialect returns the SQL dialect of the database, as set with WithDialect
+// or detected from the driver.
+func (r *rdbms) Dialect() databases.Dialect {
+	return r.dialect
+}
+
+// resolveDialect returns configured, or detects the dialect from the driver of db
+// (go-sql-driver/mysql, jackc/pgx stdlib, lib/pq). It returns "" when unknown.
+func resolveDialect(db *sql.DB, configured databases.Dialect) databases.Dialect {
+	if configured != "" || db == nil {
+		return configured
+	}
+
+	t := reflect.TypeOf(db.Driver())
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch pkg := t.PkgPath(); {
+	case strings.Contains(pkg, "go-sql-driver/mysql"):
+		return databases.DialectMySQL
+	case strings.Contains(pkg, "jackc/pgx"), strings.Contains(pkg, "lib/pq"):
+		return databases.DialectPostgres
+	default:
+		return ""
+	}
 }
 
 // Close releases all cached statements across all shards and close db.
+// For a routing RDBMS, the replica health checker is stopped and replicas are closed too.
 func (c *rdbms) Close() error {
-	return c.db.Close()
+	if c.stopHealth != nil {
+		c.stopHealth()
+	}
+
+	errs := make([]error, 0)
+	if c.stmts != nil {
+		if err := c.stmts.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.replicas != nil {
+		for _, n := range c.replicas.Nodes() {
+			if err := n.DB.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := c.db.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
 }
 
 func (c *rdbms) Ping(ctx context.Context) error {
 	return c.db.PingContext(ctx)
 }
 
-// callBefore executes all registered DBHook.Before in order, threading context through.
-// Any hook may enrich the context (e.g., tracing IDs, timeouts).
+// callBefore executes all registered DBHook.Before in registration order, threading
+// context through. Any hook may enrich the context (e.g., tracing IDs, timeouts).
+// Policy hooks are not checked; it is used for operations that cannot be vetoed.
 func (r *rdbms) callBefore(ctx context.Context, info *HookInfo) context.Context {
-	for _, h := range r.hooks {
-		ctx = h.Before(ctx, info)
-	}
+	ctx, _ = r.runBefore(ctx, info, false)
 	return ctx
 }
 
-// callAfter executes all registered DBHook.After in order.
-// Hooks should be non-blocking and panic-safe at implementation site.
+// checkBefore is callBefore for operations a PolicyHook may veto. On veto the error is
+// returned and recorded on info; the caller must skip the driver call but still call callAfter.
+func (r *rdbms) checkBefore(ctx context.Context, info *HookInfo) (context.Context, error) {
+	return r.runBefore(ctx, info, vetoableOps[info.Op])
+}
+
+// callAfter executes DBHook.After in reverse registration order, for the hooks whose
+// Before ran. Hooks should be non-blocking and panic-safe at implementation site.
 func (r *rdbms) callAfter(ctx context.Context, info *HookInfo) {
-	for _, h := range r.hooks {
-		h.After(ctx, info)
+	for i := info.entered - 1; i >= 0; i-- {
+		r.hooks[i].After(ctx, info)
 	}
 }
@@ -0,0 +1,186 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// fakeDriver is an in-memory driver recording the statements run on each database,
+// identified by name. Queries return a single row with a single column, holding
+// result or 1 when unset.
+type fakeDriver struct {
+	mu     sync.Mutex
+	stmts  []fakeStmtLog
+	result driver.Value
+}
+
+type fakeStmtLog struct {
+	db    string
+	query string
+}
+
+func (d *fakeDriver) open(name string) *sql.DB {
+	return sql.OpenDB(fakeConnector{d: d, name: name})
+}
+
+func (d *fakeDriver) record(db, query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stmts = append(d.stmts, fakeStmtLog{db: db, query: query})
+}
+
+func (d *fakeDriver) log() []fakeStmtLog {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]fakeStmtLog(nil), d.stmts...)
+}
+
+type fakeConnector struct {
+	d    *fakeDriver
+	name string
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.d.Open(c.name)
+}
+
+func (c fakeConnector) Driver() driver.Driver { return c.d }
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{d: d, db: name}, nil
+}
+
+type fakeConn struct {
+	d  *fakeDriver
+	db string
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.d.record(c.db, "BEGIN")
+	return fakeTx{c: c}, nil
+}
+
+type fakeTx struct{ c *fakeConn }
+
+func (tx fakeTx) Commit() error {
+	tx.c.d.record(tx.c.db, "COMMIT")
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.c.d.record(tx.c.db, "ROLLBACK")
+	return nil
+}
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.c.d.record(s.c.db, s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.c.d.record(s.c.db, s.query)
+	return &fakeRows{value: s.c.d.result}, nil
+}
+
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	if r.value == nil {
+		dest[0] = int64(1)
+	}
+	return nil
+}
+
+func TestRDBMS_QueryRowReturningUsesPrimary(t *testing.T) {
+	d := &fakeDriver{}
+	r := NewRoutingRDBMS(d.open("primary"), []*sql.DB{d.open("replica")})
+	defer r.Close()
+	ctx := databases.WithReadYourWrites(context.Background())
+
+	var id int64
+	if err := r.QueryRowContext(ctx, "SELECT id FROM users WHERE id = ?", 1).Scan(&id); err != nil {
+		t.Fatalf("QueryRowContext(SELECT) error = %v", err)
+	}
+	if databases.ShouldReadPrimary(ctx) {
+		t.Fatalf("ShouldReadPrimary() after a SELECT = true, want false")
+	}
+	if err := r.QueryRowContext(ctx, "INSERT INTO users (name) VALUES (?) RETURNING id", "rama").Scan(&id); err != nil {
+		t.Fatalf("QueryRowContext(INSERT ... RETURNING) error = %v", err)
+	}
+	if !databases.ShouldReadPrimary(ctx) {
+		t.Fatalf("ShouldReadPrimary() after INSERT ... RETURNING = false, want true")
+	}
+
+	want := []fakeStmtLog{
+		{db: "replica", query: "SELECT id FROM users WHERE id = ?"},
+		{db: "primary", query: "INSERT INTO users (name) VALUES (?) RETURNING id"},
+	}
+	if got := d.log(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("statements = %v, want %v", got, want)
+	}
+}
+
+func TestRDBMS_TenantRLSOutsideTx(t *testing.T) {
+	d := &fakeDriver{}
+	r := NewRDBMS(d.open("primary"), WithDialect(databases.DialectPostgres), WithTenantRLS(databases.TenantRLS{}))
+	defer r.Close()
+
+	var id int64
+	err := r.QueryRowContext(context.Background(), "SELECT id FROM orders").Scan(&id)
+	if !errors.Is(err, databases.ErrMissingTenant) {
+		t.Fatalf("QueryRowContext() without tenant error = %v, want ErrMissingTenant", err)
+	}
+	ctx := databases.WithTenant(context.Background(), "acme")
+	if _, err = r.ExecContext(ctx, "DELETE FROM orders WHERE id = 1"); !errors.Is(err, databases.ErrTenantTxRequired) {
+		t.Fatalf("ExecContext() outside a transaction error = %v, want ErrTenantTxRequired", err)
+	}
+	if got := d.log(); len(got) != 0 {
+		t.Fatalf("statements = %v, want none to reach the driver", got)
+	}
+
+	err = r.DoTxContext(ctx, nil, func(ctx context.Context, tx RDBMS) error {
+		return tx.QueryRowContext(ctx, "SELECT id FROM orders").Scan(&id)
+	})
+	if err != nil {
+		t.Fatalf("DoTxContext() error = %v", err)
+	}
+	got := d.log()
+	if len(got) != 4 || got[0].query != "BEGIN" || !strings.Contains(got[1].query, "set_config") ||
+		got[2].query != "SELECT id FROM orders" || got[3].query != "COMMIT" {
+		t.Fatalf("statements = %v, want BEGIN, set_config, SELECT, COMMIT", got)
+	}
+}
@@ -3,9 +3,11 @@ package sqlx
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 )
 
@@ -103,6 +105,130 @@ func (r *rdbms) QuerySqPagination(
 	return primitive.CreatePaginationOutput(paginationInput, totalData), fn(rows)
 }
 
+// QuerySqCursorPagination executes a keyset (cursor) paginated SELECT using Squirrel builders:
+//   - countQuery: SELECT COUNT(*) ... executed only if paginationInput.WithTotal is true.
+//   - query:      the base SELECT without ORDER BY/LIMIT; the keyset predicate,
+//     ORDER BY and LIMIT are applied here.
+//
+// The provided fn is called once per row of the page. It must Scan the current
+// row and return the values of the keyset columns (sorting fields followed by
+// the tie-breaker) which are used to build the signed next/prev cursors.
+//
+// Returns:
+//   - primitive.CursorPaginationOutput with cursors signed by the WithCursorSecret secret.
+//   - primitive.ErrInvalidCursor if the input cursor is malformed or tampered.
+//   - error from building queries, count/select execution, or fn.
+//
+// Notes:
+//   - When paging backward (output.Reversed), rows are delivered in reverse order.
+func (r *rdbms) QuerySqCursorPagination(
+	ctx context.Context,
+	countQuery, query squirrel.SelectBuilder,
+	keyset primitive.Keyset,
+	paginationInput primitive.CursorPaginationInput,
+	fn func(rows *sql.Rows) ([]any, error),
+) (primitive.CursorPaginationOutput, error) {
+	if paginationInput.PageSize <= 0 {
+		paginationInput.PageSize = 20
+	}
+
+	cursor, err := primitive.DecodeCursor(keyset, paginationInput.Cursor, r.cursorSecret)
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+
+	query, err = keyset.BuildSquirrel(query, cursor, paginationInput.PageSize)
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+	q, args, err := query.ToSql()
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, fmt.Errorf("failed parse squirrel: %w", err)
+	}
+
+	var totalData *int64
+	if paginationInput.WithTotal {
+		qCount, argsCount, err := countQuery.ToSql()
+		if err != nil {
+			return primitive.CursorPaginationOutput{}, fmt.Errorf("failed parse squirrel: %w", err)
+		}
+
+		var total int64
+		row := r.QueryRowContext(ctx, qCount, argsCount...)
+		if err = row.Scan(&total); err != nil {
+			return primitive.CursorPaginationOutput{}, fmt.Errorf("failed count data: %w", err)
+		}
+		totalData = &total
+	}
+
+	rows, err := r.QueryContext(ctx, q, args...)
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+	defer rows.Close()
+
+	var (
+		keys    = make([][]any, 0, paginationInput.PageSize)
+		hasMore bool
+	)
+	for rows.Next() {
+		if int64(len(keys)) == paginationInput.PageSize {
+			hasMore = true
+			break
+		}
+		key, err := fn(rows)
+		if err != nil {
+			return primitive.CursorPaginationOutput{}, err
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+
+	output, err := primitive.NewCursorPaginationOutput(keyset, paginationInput, cursor, keys, hasMore, r.cursorSecret)
+	if err != nil {
+		return primitive.CursorPaginationOutput{}, err
+	}
+	output.TotalData = totalData
+	return output, nil
+}
+
+// UpdateWithVersion executes update.UpdateQuery, which sets the columns and increments
+// the version only while it still equals update.Version, and returns the new version.
+//
+// Errors:
+//   - apperror.Conflict (cause databases.ErrVersionConflict) if the row exists with another version.
+//   - apperror.NotFound (cause databases.ErrNoUpdateRow) if no row matches update.Where.
+//   - returns underlying Exec/QueryRow errors.
+func (r *rdbms) UpdateWithVersion(ctx context.Context, update databases.VersionedUpdate) (databases.Version, error) {
+	res, err := r.ExecSq(ctx, update.UpdateQuery(r.Dialect()))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		return update.Version.Next(), nil
+	}
+
+	// The existence check must see the latest state, never a lagging replica.
+	row, err := r.QueryRowSq(databases.UsePrimary(ctx), update.ExistsQuery(r.Dialect()))
+	if err != nil {
+		return 0, err
+	}
+	var exists int
+	if err = row.Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, update.NoRowsError(false)
+		}
+		return 0, err
+	}
+	return 0, update.NoRowsError(true)
+}
+
 // QueryRowSq executes a SELECT (single-row) using a Squirrel builder.
 //
 // Returns:
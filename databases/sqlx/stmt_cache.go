@@ -0,0 +1,188 @@
+package sqlx
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// stmtCache is a fixed-size LRU cache of statements prepared on the primary *sql.DB,
+// keyed by SQL text. It is safe for concurrent use.
+type stmtCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List // front = most recently used
+	items map[string]*list.Element
+}
+
+type stmtCacheEntry struct {
+	query string
+	stmt  *sql.Stmt
+}
+
+// newStmtCache returns a cache holding up to size statements, or nil (cache disabled)
+// when size is not positive.
+func newStmtCache(size int) *stmtCache {
+	if size <= 0 {
+		return nil
+	}
+	return &stmtCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached statement for query, marking it as recently used.
+func (c *stmtCache) get(query string) (*sql.Stmt, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[query]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*stmtCacheEntry).stmt, true
+}
+
+// add caches stmt for query and returns the statement to use. If another goroutine
+// cached the same query first, stmt is closed and the cached one is returned.
+// The least recently used statement is closed when the cache is full.
+// Statements are closed after unlocking, as Close waits for their in-flight queries.
+func (c *stmtCache) add(query string, stmt *sql.Stmt) *sql.Stmt {
+	c.mu.Lock()
+	if el, ok := c.items[query]; ok {
+		c.ll.MoveToFront(el)
+		cached := el.Value.(*stmtCacheEntry).stmt
+		c.mu.Unlock()
+		_ = stmt.Close()
+		return cached
+	}
+
+	c.items[query] = c.ll.PushFront(&stmtCacheEntry{query: query, stmt: stmt})
+	var evicted []*sql.Stmt
+	for c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		entry := c.ll.Remove(oldest).(*stmtCacheEntry)
+		delete(c.items, entry.query)
+		evicted = append(evicted, entry.stmt)
+	}
+	c.mu.Unlock()
+
+	for _, old := range evicted {
+		_ = old.Close()
+	}
+	return stmt
+}
+
+// invalidate drops and closes the cached statement for query if it is still stmt.
+func (c *stmtCache) invalidate(query string, stmt *sql.Stmt) {
+	c.mu.Lock()
+	el, ok := c.items[query]
+	if !ok || el.Value.(*stmtCacheEntry).stmt != stmt {
+		c.mu.Unlock()
+		return
+	}
+	c.ll.Remove(el)
+	delete(c.items, query)
+	c.mu.Unlock()
+
+	_ = stmt.Close()
+}
+
+// close closes and drops every cached statement.
+func (c *stmtCache) close() error {
+	c.mu.Lock()
+	stmts := make([]*sql.Stmt, 0, c.ll.Len())
+	for el := c.ll.Front(); el != nil; el = el.Next() {
+		stmts = append(stmts, el.Value.(*stmtCacheEntry).stmt)
+	}
+	c.ll.Init()
+	clear(c.items)
+	c.mu.Unlock()
+
+	errs := make([]error, 0)
+	for _, stmt := range stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookupStmt reports whether an operation served by db (nil inside a transaction) goes
+// through the statement cache and returns the cached statement, if any.
+// The outcome is recorded in info.Prepared and info.CacheHit.
+// Only statements on the primary are cached; replica reads are never prepared.
+func (r *rdbms) lookupStmt(db *sql.DB, query string, info *HookInfo) (*sql.Stmt, bool) {
+	if r.stmts == nil || (r.tx == nil && db != r.db) {
+		return nil, false
+	}
+
+	cached, hit := r.stmts.get(query)
+	info.Prepared = true
+	info.CacheHit = &hit
+	return cached, true
+}
+
+// prepareStmt returns the statement to execute for query: the cached statement, or a
+// newly prepared and cached one on a miss. Inside a transaction it is bound to r.tx with
+// StmtContext. base is the cached statement on the primary, used for invalidation.
+// A nil stmt means preparing failed and the caller should run the query unprepared.
+func (r *rdbms) prepareStmt(ctx context.Context, query string, cached *sql.Stmt, info *HookInfo) (stmt, base *sql.Stmt) {
+	base = cached
+	if base == nil {
+		prepared, err := r.db.PrepareContext(ctx, query)
+		if err != nil {
+			info.Prepared = false
+			return nil, nil
+		}
+		base = r.stmts.add(query, prepared)
+	}
+
+	if r.tx != nil {
+		return r.tx.StmtContext(ctx, base), base
+	}
+	return base, base
+}
+
+// invalidateStmt drops a cached statement after an error that may leave it unusable,
+// such as a broken connection or a statement the server no longer knows.
+func (r *rdbms) invalidateStmt(query string, base *sql.Stmt, err error) {
+	if r.stmts == nil || base == nil || !isStmtInvalidatingError(err) {
+		return
+	}
+	r.stmts.invalidate(query, base)
+}
+
+// isStmtInvalidatingError reports whether err indicates that a prepared statement
+// must be re-prepared:
+//   - broken or closed connections
+//   - PostgreSQL 0A000 (cached plan must not change result type) and 26000 (invalid statement name)
+//   - MySQL 1243 (unknown prepared statement handler) and 1615 (statement needs to be re-prepared)
+func isStmtInvalidatingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if databases.IsConnectionError(err) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+
+	switch databases.ErrorCode(err) {
+	case "0A000", "26000", "1243", "1615":
+		return true
+	default:
+		return false
+	}
+}
+
+// isStmtClosedError reports whether a cached statement was closed (evicted or
+// invalidated) by another goroutine while in use; the query is then run unprepared.
+func isStmtClosedError(err error) bool {
+	return err != nil && err.Error() == "sql: statement is closed"
+}
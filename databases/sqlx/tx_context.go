@@ -0,0 +1,60 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ambientTxKey stores the transactional rdbms started by DoTxContext.
+// It is keyed by the base *sql.DB so transactions of different databases never mix.
+type ambientTxKey struct {
+	db *sql.DB
+}
+
+// withoutTxKey marks a context that opted out of the ambient transaction.
+type withoutTxKey struct{}
+
+// WithoutTx returns a context whose operations bypass the ambient transaction
+// started by DoTxContext and run directly on the base *sql.DB.
+// A later DoTxContext with the returned context starts a new, independent transaction.
+func WithoutTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutTxKey{}, true)
+}
+
+// TxFromContext returns the ambient transaction started by DoTxContext on the
+// given RDBMS, if any. It returns false when ctx carries no transaction for it
+// or when the context opted out with WithoutTx.
+func TxFromContext(ctx context.Context, db RDBMS) (RDBMS, bool) {
+	r, ok := db.(*rdbms)
+	if !ok {
+		return nil, false
+	}
+	if r.tx != nil {
+		return r, true
+	}
+	return r.ambientTx(ctx)
+}
+
+// withTx stores the transactional child in ctx and clears any WithoutTx opt-out,
+// so operations made with the returned context join the transaction.
+func withTx(ctx context.Context, child *rdbms) context.Context {
+	ctx = context.WithValue(ctx, ambientTxKey{db: child.db}, child)
+	if disabled, _ := ctx.Value(withoutTxKey{}).(bool); disabled {
+		ctx = context.WithValue(ctx, withoutTxKey{}, false)
+	}
+	return ctx
+}
+
+// ambientTx returns the transactional rdbms carried by ctx for r's database.
+// It returns false when r is already transactional, no transaction is active
+// in ctx, or the context opted out with WithoutTx.
+func (r *rdbms) ambientTx(ctx context.Context) (*rdbms, bool) {
+	if r.tx != nil || ctx == nil {
+		return nil, false
+	}
+	if disabled, _ := ctx.Value(withoutTxKey{}).(bool); disabled {
+		return nil, false
+	}
+	child, ok := ctx.Value(ambientTxKey{db: r.db}).(*rdbms)
+	return child, ok && child != nil
+}
@@ -0,0 +1,90 @@
+package databases
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// StructField is a struct field mapped to a column by its `db` tag.
+type StructField struct {
+	Column  string   // Column name
+	Index   []int    // Field index, for reflect.Value.FieldByIndex
+	Options []string // Tag options following the column name, e.g. "pk" in `db:"id,pk"`
+}
+
+// HasOption reports whether the field's tag carries opt.
+func (f StructField) HasOption(opt string) bool {
+	return slices.Contains(f.Options, opt)
+}
+
+// StructFields returns the column mapping of the struct type t (or pointer to it).
+// The `db` tag names the column of a field, optionally followed by comma separated
+// options; untagged fields use the snake_cased field name ("UserID" -> "user_id").
+// Fields tagged `db:"-"` and unexported fields are skipped; untagged embedded
+// structs and struct pointers are flattened. The Index of a field promoted through a
+// pointer crosses it, so use reflect.Value.FieldByIndexErr when it may be nil.
+func StructFields(t reflect.Type) ([]StructField, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct type, got %s", t)
+	}
+	return structFields(t, nil), nil
+}
+
+func structFields(t reflect.Type, parent []int) []StructField {
+	fields := make([]StructField, 0, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		tag, hasTag := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && !hasTag && ft.Kind() == reflect.Struct {
+			fields = append(fields, structFields(ft, index)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = toSnakeCase(f.Name)
+		}
+		field := StructField{Column: name, Index: index}
+		for _, opt := range strings.Split(opts, ",") {
+			if opt = strings.TrimSpace(opt); opt != "" {
+				field.Options = append(field.Options, opt)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// toSnakeCase converts a Go field name such as "CreatedAt" or "UserID" to "created_at" / "user_id".
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package databases
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStructFields(t *testing.T) {
+	type Audit struct {
+		CreatedAt string `db:"created_at,insertonly"`
+	}
+	type user struct {
+		ID     int64 `db:"id,pk, readonly"`
+		UserID string
+		Note   string `db:"-"`
+		secret string
+		Audit
+	}
+
+	fields, err := StructFields(reflect.TypeFor[*user]())
+	if err != nil {
+		t.Fatalf("StructFields() error = %v", err)
+	}
+	want := []StructField{
+		{Column: "id", Index: []int{0}, Options: []string{"pk", "readonly"}},
+		{Column: "user_id", Index: []int{1}},
+		{Column: "created_at", Index: []int{4, 0}, Options: []string{"insertonly"}},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("StructFields() = %+v, want %+v", fields, want)
+	}
+	if !fields[0].HasOption("readonly") || fields[1].HasOption("pk") {
+		t.Fatalf("HasOption() mismatch")
+	}
+
+	type Base struct {
+		ID int64 `db:"id"`
+	}
+	type order struct {
+		*Base
+		Total int
+	}
+	fields, err = StructFields(reflect.TypeFor[order]())
+	if err != nil {
+		t.Fatalf("StructFields() error = %v", err)
+	}
+	want = []StructField{
+		{Column: "id", Index: []int{0, 0}},
+		{Column: "total", Index: []int{1}},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("StructFields() of a pointer embed = %+v, want %+v", fields, want)
+	}
+
+	if _, err := StructFields(reflect.TypeFor[int]()); err == nil {
+		t.Fatalf("StructFields(int): want error")
+	}
+}
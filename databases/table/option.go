@@ -0,0 +1,107 @@
+package table
+
+import (
+	"time"
+
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+// Option configures a Table.
+type Option interface {
+	apply(*config)
+}
+
+type optFunc func(*config)
+
+func (o optFunc) apply(c *config) {
+	o(c)
+}
+
+type config struct {
+	dialect   databases.Dialect
+	clock     func() time.Time
+	createdAt string
+	updatedAt string
+	deletedAt string
+	createdBy string
+	updatedBy string
+	tenant    string
+}
+
+func defaultConfig() *config {
+	return &config{
+		dialect:   databases.DialectPostgres,
+		clock:     time.Now,
+		createdAt: "created_at",
+		updatedAt: "updated_at",
+		deletedAt: "deleted_at",
+		createdBy: "created_by",
+		updatedBy: "updated_by",
+	}
+}
+
+// WithDialect sets the dialect whose placeholder format the builders use,
+// typically db.Dialect() of the sqlx or pgxx RDBMS. Default: PostgreSQL.
+func WithDialect(d databases.Dialect) Option {
+	return optFunc(func(c *config) {
+		if d != "" {
+			c.dialect = d
+		}
+	})
+}
+
+// WithClock sets the function returning the time stamped into the audit columns. Default: time.Now.
+func WithClock(clock func() time.Time) Option {
+	return optFunc(func(c *config) {
+		if clock != nil {
+			c.clock = clock
+		}
+	})
+}
+
+// WithCreatedAt sets the creation timestamp column. Default: created_at; "" disables it.
+func WithCreatedAt(column string) Option {
+	return optFunc(func(c *config) {
+		c.createdAt = column
+	})
+}
+
+// WithUpdatedAt sets the modification timestamp column. Default: updated_at; "" disables it.
+func WithUpdatedAt(column string) Option {
+	return optFunc(func(c *config) {
+		c.updatedAt = column
+	})
+}
+
+// WithDeletedAt sets the soft-delete timestamp column. Default: deleted_at;
+// "" disables soft delete, so Delete removes rows and no scope predicate is added.
+func WithDeletedAt(column string) Option {
+	return optFunc(func(c *config) {
+		c.deletedAt = column
+	})
+}
+
+// WithCreatedBy sets the column receiving the actor on insert. Default: created_by; "" disables it.
+func WithCreatedBy(column string) Option {
+	return optFunc(func(c *config) {
+		c.createdBy = column
+	})
+}
+
+// WithUpdatedBy sets the column receiving the actor on insert, update and soft delete.
+// Default: updated_by; "" disables it.
+func WithUpdatedBy(column string) Option {
+	return optFunc(func(c *config) {
+		c.updatedBy = column
+	})
+}
+
+// WithTenantColumn makes the table tenant-scoped: every statement is restricted to
+// column = the tenant of the context (databases.WithTenant) and Insert stamps it.
+// Statements built without a tenant fail closed with databases.ErrMissingTenant at
+// ToSql, unless the context carries databases.WithTenantBypass. Default: disabled.
+func WithTenantColumn(column string) Option {
+	return optFunc(func(c *config) {
+		c.tenant = column
+	})
+}
@@ -0,0 +1,335 @@
+// Package table provides table descriptors that build squirrel queries aware of the
+// soft-delete and audit columns shared by most tables (created_at, updated_at,
+// deleted_at, created_by, updated_by).
+//
+// Reads and updates exclude soft-deleted rows unless the WithDeleted or OnlyDeleted
+// scope is used, Delete stamps deleted_at instead of removing the row, and audit
+// columns are stamped from the configured clock and the actor set with
+// databases.WithActor. Tables configured with WithTenantColumn are additionally
+// restricted to the tenant set with databases.WithTenant. The builders are executed
+// with the sqlx or pgxx RDBMS (ExecSq, QuerySq, QueryRowSq, ...).
+//
+// Example:
+//
+//	users := table.New("users", table.WithDialect(db.Dialect()))
+//
+//	query := users.For(ctx).Select("id", "name").Where(squirrel.Eq{"id": id})
+//	row, err := db.QueryRowSq(ctx, query)
+//
+//	_, err = db.ExecSq(ctx, users.Delete(ctx).Where(squirrel.Eq{"id": id}))
+package table
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+type scope int
+
+const (
+	scopeActive scope = iota
+	scopeWithDeleted
+	scopeOnlyDeleted
+)
+
+// Table describes a table and builds queries against it.
+// A Table is immutable; For, WithDeleted, OnlyDeleted and As return modified copies.
+type Table struct {
+	name   string
+	alias  string
+	scope  scope
+	tenant tenantScope
+	cfg    *config
+}
+
+// tenantScope is the tenant a statement is restricted to.
+type tenantScope struct {
+	id     string
+	bypass bool
+}
+
+func tenantScopeOf(ctx context.Context) tenantScope {
+	if databases.IsTenantBypassed(ctx) {
+		return tenantScope{bypass: true}
+	}
+	id, _ := databases.TenantFromContext(ctx)
+	return tenantScope{id: id}
+}
+
+// New creates a table descriptor for name.
+func New(name string, opts ...Option) *Table {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	return &Table{name: name, cfg: cfg}
+}
+
+// Name returns the table name.
+func (t *Table) Name() string {
+	return t.name
+}
+
+// SoftDelete reports whether the table has a soft-delete column.
+func (t *Table) SoftDelete() bool {
+	return t.cfg.deletedAt != ""
+}
+
+// As returns a copy of the table aliased as alias; the soft-delete predicate is
+// qualified with the alias so the builders can be used in joins.
+func (t *Table) As(alias string) *Table {
+	c := *t
+	c.alias = alias
+	return &c
+}
+
+// For returns a copy of the table whose Select is restricted to the tenant of ctx.
+// It is required before Select on tables configured with WithTenantColumn; Insert,
+// Update and Delete read the tenant from their own ctx.
+func (t *Table) For(ctx context.Context) *Table {
+	c := *t
+	c.tenant = tenantScopeOf(ctx)
+	return &c
+}
+
+// WithDeleted returns a copy of the table whose builders include soft-deleted rows.
+func (t *Table) WithDeleted() *Table {
+	c := *t
+	c.scope = scopeWithDeleted
+	return &c
+}
+
+// OnlyDeleted returns a copy of the table whose builders only match soft-deleted rows.
+func (t *Table) OnlyDeleted() *Table {
+	c := *t
+	c.scope = scopeOnlyDeleted
+	return &c
+}
+
+// Scope returns the soft-delete predicate of the current scope, or nil if none applies.
+// Use it to filter joined tables, e.g. LeftJoin("orders o ON o.user_id = u.id AND "+...).
+func (t *Table) Scope() squirrel.Sqlizer {
+	if !t.SoftDelete() {
+		return nil
+	}
+
+	column := t.cfg.deletedAt
+	if t.alias != "" {
+		column = t.alias + "." + column
+	}
+	switch t.scope {
+	case scopeWithDeleted:
+		return nil
+	case scopeOnlyDeleted:
+		return squirrel.NotEq{column: nil}
+	default:
+		return squirrel.Eq{column: nil}
+	}
+}
+
+// Select returns a SELECT builder for columns, restricted to the current scope.
+func (t *Table) Select(columns ...string) squirrel.SelectBuilder {
+	from := t.name
+	if t.alias != "" {
+		from += " " + t.alias
+	}
+
+	query := squirrel.Select(columns...).From(from).PlaceholderFormat(t.placeholder())
+	if pred := t.tenantPredicate(t.tenant, t.alias); pred != nil {
+		query = query.Where(pred)
+	}
+	if pred := t.Scope(); pred != nil {
+		query = query.Where(pred)
+	}
+	return query
+}
+
+// Insert returns an INSERT builder for values, stamping created_at, updated_at and,
+// when ctx carries an actor, created_by and updated_by. Columns already present in
+// values are left untouched, except the tenant column, which always gets the tenant of ctx.
+func (t *Table) Insert(ctx context.Context, values map[string]any) squirrel.InsertBuilder {
+	row := make(map[string]any, len(values)+4)
+	for k, v := range values {
+		row[k] = v
+	}
+
+	now := t.cfg.clock()
+	setDefault(row, t.cfg.createdAt, now)
+	setDefault(row, t.cfg.updatedAt, now)
+	if actor, ok := databases.ActorFromContext(ctx); ok {
+		setDefault(row, t.cfg.createdBy, actor)
+		setDefault(row, t.cfg.updatedBy, actor)
+	}
+	if tenant := tenantScopeOf(ctx); t.cfg.tenant != "" && !tenant.bypass {
+		if tenant.id == "" {
+			row[t.cfg.tenant] = errSqlizer{databases.ErrMissingTenant}
+		} else {
+			row[t.cfg.tenant] = tenant.id
+		}
+	}
+
+	return squirrel.Insert(t.name).SetMap(row).PlaceholderFormat(t.placeholder())
+}
+
+// Update returns an UPDATE builder setting values, stamping updated_at and, when ctx
+// carries an actor, updated_by. Soft-deleted rows are not updated unless the
+// WithDeleted or OnlyDeleted scope is used.
+func (t *Table) Update(ctx context.Context, values map[string]any) squirrel.UpdateBuilder {
+	row := make(map[string]any, len(values)+2)
+	for k, v := range values {
+		row[k] = v
+	}
+	t.stampUpdate(ctx, row)
+
+	return t.update(ctx, row)
+}
+
+// Delete returns a builder that soft deletes the matched rows by stamping deleted_at
+// (and updated_at / updated_by). Rows already deleted keep their original deleted_at.
+// On tables without a soft-delete column it builds a plain DELETE.
+func (t *Table) Delete(ctx context.Context) DeleteBuilder {
+	if !t.SoftDelete() {
+		return DeleteBuilder{hard: t.hardDelete(ctx)}
+	}
+
+	row := map[string]any{t.cfg.deletedAt: t.cfg.clock()}
+	t.stampUpdate(ctx, row)
+
+	active := *t
+	active.scope = scopeActive
+	return DeleteBuilder{soft: active.update(ctx, row), isSoft: true}
+}
+
+// Restore returns an UPDATE builder clearing deleted_at of the matched soft-deleted rows.
+// On tables without a soft-delete column the builder has no SET clause and ToSql fails.
+func (t *Table) Restore(ctx context.Context) squirrel.UpdateBuilder {
+	if !t.SoftDelete() {
+		return squirrel.Update(t.name).PlaceholderFormat(t.placeholder())
+	}
+
+	row := map[string]any{t.cfg.deletedAt: nil}
+	t.stampUpdate(ctx, row)
+
+	deleted := *t
+	deleted.scope = scopeOnlyDeleted
+	return deleted.update(ctx, row)
+}
+
+// HardDelete returns a DELETE builder that physically removes the matched rows,
+// regardless of the soft-delete scope.
+func (t *Table) HardDelete(ctx context.Context) squirrel.DeleteBuilder {
+	return t.hardDelete(ctx)
+}
+
+func (t *Table) hardDelete(ctx context.Context) squirrel.DeleteBuilder {
+	query := squirrel.Delete(t.name).PlaceholderFormat(t.placeholder())
+	if pred := t.tenantPredicate(tenantScopeOf(ctx), ""); pred != nil {
+		query = query.Where(pred)
+	}
+	return query
+}
+
+func (t *Table) update(ctx context.Context, row map[string]any) squirrel.UpdateBuilder {
+	query := squirrel.Update(t.name).SetMap(row).PlaceholderFormat(t.placeholder())
+	if pred := t.tenantPredicate(tenantScopeOf(ctx), ""); pred != nil {
+		query = query.Where(pred)
+	}
+
+	unaliased := *t
+	unaliased.alias = ""
+	if pred := unaliased.Scope(); pred != nil {
+		query = query.Where(pred)
+	}
+	return query
+}
+
+func (t *Table) stampUpdate(ctx context.Context, row map[string]any) {
+	setDefault(row, t.cfg.updatedAt, t.cfg.clock())
+	if actor, ok := databases.ActorFromContext(ctx); ok {
+		setDefault(row, t.cfg.updatedBy, actor)
+	}
+}
+
+// tenantPredicate returns the tenant restriction for s, qualified with alias, or nil
+// if the table is not tenant-scoped or s bypasses it. A missing tenant yields a
+// predicate failing with databases.ErrMissingTenant.
+func (t *Table) tenantPredicate(s tenantScope, alias string) squirrel.Sqlizer {
+	if t.cfg.tenant == "" || s.bypass {
+		return nil
+	}
+	if s.id == "" {
+		return errSqlizer{databases.ErrMissingTenant}
+	}
+
+	column := t.cfg.tenant
+	if alias != "" {
+		column = alias + "." + column
+	}
+	return squirrel.Eq{column: s.id}
+}
+
+func (t *Table) placeholder() squirrel.PlaceholderFormat {
+	return t.cfg.dialect.Placeholder()
+}
+
+// setDefault sets row[column] to value unless column is disabled or already set.
+func setDefault(row map[string]any, column string, value any) {
+	if column == "" {
+		return
+	}
+	if _, ok := row[column]; !ok {
+		row[column] = value
+	}
+}
+
+// DeleteBuilder builds the statement returned by Table.Delete: an UPDATE of the
+// soft-delete column, or a DELETE on tables without one.
+type DeleteBuilder struct {
+	soft   squirrel.UpdateBuilder
+	hard   squirrel.DeleteBuilder
+	isSoft bool
+}
+
+// Where adds a WHERE expression, see squirrel.UpdateBuilder.Where.
+func (b DeleteBuilder) Where(pred any, args ...any) DeleteBuilder {
+	if b.isSoft {
+		b.soft = b.soft.Where(pred, args...)
+	} else {
+		b.hard = b.hard.Where(pred, args...)
+	}
+	return b
+}
+
+// Suffix adds an expression to the end of the statement, e.g. "RETURNING id".
+func (b DeleteBuilder) Suffix(sql string, args ...any) DeleteBuilder {
+	if b.isSoft {
+		b.soft = b.soft.Suffix(sql, args...)
+	} else {
+		b.hard = b.hard.Suffix(sql, args...)
+	}
+	return b
+}
+
+// IsSoft reports whether the statement is a soft delete.
+func (b DeleteBuilder) IsSoft() bool {
+	return b.isSoft
+}
+
+// ToSql builds the statement, implementing squirrel.Sqlizer.
+func (b DeleteBuilder) ToSql() (string, []any, error) {
+	if b.isSoft {
+		return b.soft.ToSql()
+	}
+	return b.hard.ToSql()
+}
+
+// errSqlizer fails the statement it is part of, so it cannot run unscoped.
+type errSqlizer struct {
+	err error
+}
+
+func (e errSqlizer) ToSql() (string, []any, error) {
+	return "", nil, e.err
+}
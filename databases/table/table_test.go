@@ -0,0 +1,107 @@
+package table
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+)
+
+var testNow = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestTable(opts ...Option) *Table {
+	return New("users", append([]Option{WithClock(func() time.Time { return testNow })}, opts...)...)
+}
+
+func assertSQL(t *testing.T, query squirrel.Sqlizer, wantSQL string, wantArgs ...any) {
+	t.Helper()
+	sql, args, err := query.ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	if sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", sql, wantSQL)
+	}
+	if len(wantArgs) == 0 {
+		wantArgs = nil
+	}
+	if len(args) == 0 {
+		args = nil
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestTable_SelectScopes(t *testing.T) {
+	users := newTestTable()
+
+	assertSQL(t, users.Select("id").Where(squirrel.Eq{"id": 1}),
+		"SELECT id FROM users WHERE deleted_at IS NULL AND id = $1", 1)
+	assertSQL(t, users.WithDeleted().Select("id"), "SELECT id FROM users")
+	assertSQL(t, users.OnlyDeleted().Select("id"), "SELECT id FROM users WHERE deleted_at IS NOT NULL")
+	assertSQL(t, users.As("u").Select("u.id"), "SELECT u.id FROM users u WHERE u.deleted_at IS NULL")
+	assertSQL(t, newTestTable(WithDeletedAt(""), WithDialect(databases.DialectMySQL)).Select("id").Where("id = ?", 1),
+		"SELECT id FROM users WHERE id = ?", 1)
+}
+
+func TestTable_InsertUpdate(t *testing.T) {
+	users := newTestTable()
+	ctx := databases.WithActor(context.Background(), "admin")
+
+	assertSQL(t, users.Insert(ctx, map[string]any{"name": "rama", "created_by": "import"}),
+		"INSERT INTO users (created_at,created_by,name,updated_at,updated_by) VALUES ($1,$2,$3,$4,$5)",
+		testNow, "import", "rama", testNow, "admin")
+
+	assertSQL(t, users.Update(context.Background(), map[string]any{"name": "rama"}).Where(squirrel.Eq{"id": 1}),
+		"UPDATE users SET name = $1, updated_at = $2 WHERE deleted_at IS NULL AND id = $3",
+		"rama", testNow, 1)
+}
+
+func TestTable_Delete(t *testing.T) {
+	users := newTestTable()
+	ctx := databases.WithActor(context.Background(), 7)
+
+	del := users.WithDeleted().Delete(ctx).Where(squirrel.Eq{"id": 1})
+	if !del.IsSoft() {
+		t.Fatalf("Delete() IsSoft = false, want true")
+	}
+	assertSQL(t, del,
+		"UPDATE users SET deleted_at = $1, updated_at = $2, updated_by = $3 WHERE deleted_at IS NULL AND id = $4",
+		testNow, testNow, 7, 1)
+
+	assertSQL(t, users.Restore(ctx).Where(squirrel.Eq{"id": 1}),
+		"UPDATE users SET deleted_at = $1, updated_at = $2, updated_by = $3 WHERE deleted_at IS NOT NULL AND id = $4",
+		nil, testNow, 7, 1)
+
+	assertSQL(t, users.HardDelete(context.Background()).Where(squirrel.Eq{"id": 1}), "DELETE FROM users WHERE id = $1", 1)
+	assertSQL(t, newTestTable(WithDeletedAt("")).Delete(ctx).Where(squirrel.Eq{"id": 1}),
+		"DELETE FROM users WHERE id = $1", 1)
+}
+
+func TestTable_Tenant(t *testing.T) {
+	orders := New("orders", WithTenantColumn("tenant_id"), WithDeletedAt(""), WithCreatedAt(""), WithUpdatedAt(""))
+	ctx := databases.WithTenant(context.Background(), "acme")
+
+	assertSQL(t, orders.For(ctx).As("o").Select("o.id"), "SELECT o.id FROM orders o WHERE o.tenant_id = $1", "acme")
+	assertSQL(t, orders.Insert(ctx, map[string]any{"id": 1, "tenant_id": "other"}),
+		"INSERT INTO orders (id,tenant_id) VALUES ($1,$2)", 1, "acme")
+	assertSQL(t, orders.Delete(ctx).Where(squirrel.Eq{"id": 1}),
+		"DELETE FROM orders WHERE tenant_id = $1 AND id = $2", "acme", 1)
+	assertSQL(t, orders.For(databases.WithTenantBypass(ctx)).Select("id"), "SELECT id FROM orders")
+
+	missing := []squirrel.Sqlizer{
+		orders.Select("id"),
+		orders.Insert(context.Background(), map[string]any{"id": 1}),
+		orders.Update(context.Background(), map[string]any{"status": "paid"}),
+	}
+	for _, query := range missing {
+		if _, _, err := query.ToSql(); !errors.Is(err, databases.ErrMissingTenant) {
+			t.Fatalf("ToSql() without tenant error = %v, want ErrMissingTenant", err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrMissingTenant is returned when a tenant-scoped operation runs with a context
+// carrying neither a tenant (WithTenant) nor the admin bypass (WithTenantBypass).
+var ErrMissingTenant = errors.New("tenant missing from context")
+
+// ErrTenantTxRequired is returned by an RDBMS configured with WithTenantRLS for a
+// statement run outside a transaction, where the tenant setting cannot be applied.
+var ErrTenantTxRequired = errors.New("tenant rls: statement must run inside a transaction")
+
+const (
+	// DefaultTenantSetting is the PostgreSQL setting holding the tenant ID for RLS policies.
+	DefaultTenantSetting = "app.tenant_id"
+	// DefaultTenantBypassSetting is the PostgreSQL setting set to 'on' for cross-tenant jobs.
+	DefaultTenantBypassSetting = "app.tenant_bypass"
+)
+
+type tenantKey struct{}
+type tenantBypassKey struct{}
+
+// WithTenant returns a context scoped to tenantID.
+// Typically installed by an authentication middleware.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant set by WithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// WithTenantBypass returns a context allowed to access every tenant, for admin and
+// cross-tenant background jobs. It takes precedence over WithTenant.
+func WithTenantBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantBypassKey{}, true)
+}
+
+// IsTenantBypassed reports whether WithTenantBypass was applied to ctx.
+func IsTenantBypassed(ctx context.Context) bool {
+	v, _ := ctx.Value(tenantBypassKey{}).(bool)
+	return v
+}
+
+// TenantRLS exposes the tenant of the context to PostgreSQL row-level security
+// policies. The sqlx and pgxx RDBMS configured with WithTenantRLS apply it at the
+// beginning of every transaction with set_config(..., true), the parameterized
+// equivalent of SET LOCAL, so the values never leak to other pooled sessions.
+//
+// They fail closed: statements run outside a transaction are rejected without
+// reaching the server, with ErrMissingTenant when the context has no tenant and
+// ErrTenantTxRequired otherwise. Policies should still treat an empty setting as
+// no tenant, for sessions opened by other clients:
+//
+//	CREATE POLICY tenant_isolation ON orders USING (
+//	    current_setting('app.tenant_bypass', true) = 'on'
+//	    OR tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::uuid
+//	);
+type TenantRLS struct {
+	Setting       string // Setting holding the tenant ID. Default: DefaultTenantSetting
+	BypassSetting string // Setting set to 'on' by WithTenantBypass. Default: DefaultTenantBypassSetting
+}
+
+// Statement returns the statement applying the tenant of ctx, with ? placeholders.
+// It fails closed with ErrMissingTenant when ctx has neither a tenant nor the bypass,
+// and with an error on dialects other than PostgreSQL.
+func (t TenantRLS) Statement(ctx context.Context, d Dialect) (string, []any, error) {
+	if d != DialectPostgres {
+		return "", nil, fmt.Errorf("tenant rls: row-level security is not supported by dialect %q", d)
+	}
+
+	setting, bypassSetting := t.Setting, t.BypassSetting
+	if setting == "" {
+		setting = DefaultTenantSetting
+	}
+	if bypassSetting == "" {
+		bypassSetting = DefaultTenantBypassSetting
+	}
+
+	if IsTenantBypassed(ctx) {
+		return "SELECT set_config(?, 'on', true)", []any{bypassSetting}, nil
+	}
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", nil, ErrMissingTenant
+	}
+	return "SELECT set_config(?, ?, true), set_config(?, 'off', true)", []any{setting, tenantID, bypassSetting}, nil
+}
+
+// CheckOutsideTx returns the error of a statement run outside a transaction on ctx:
+// ErrMissingTenant when ctx has neither a tenant nor the bypass, ErrTenantTxRequired otherwise.
+func (t TenantRLS) CheckOutsideTx(ctx context.Context) error {
+	if _, ok := TenantFromContext(ctx); !ok && !IsTenantBypassed(ctx) {
+		return ErrMissingTenant
+	}
+	return ErrTenantTxRequired
+}
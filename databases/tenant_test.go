@@ -0,0 +1,50 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTenantRLS_Statement(t *testing.T) {
+	rls := TenantRLS{}
+
+	if _, _, err := rls.Statement(context.Background(), DialectPostgres); !errors.Is(err, ErrMissingTenant) {
+		t.Fatalf("Statement() without tenant error = %v, want ErrMissingTenant", err)
+	}
+
+	ctx := WithTenant(context.Background(), "acme")
+	stmt, args, err := rls.Statement(ctx, DialectPostgres)
+	if err != nil {
+		t.Fatalf("Statement() error = %v", err)
+	}
+	if want := "SELECT set_config(?, ?, true), set_config(?, 'off', true)"; stmt != want {
+		t.Fatalf("stmt = %q, want %q", stmt, want)
+	}
+	if want := []any{DefaultTenantSetting, "acme", DefaultTenantBypassSetting}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+
+	_, args, err = TenantRLS{BypassSetting: "app.admin"}.Statement(WithTenantBypass(ctx), DialectPostgres)
+	if err != nil || !reflect.DeepEqual(args, []any{"app.admin"}) {
+		t.Fatalf("Statement() with bypass = %v, %v", args, err)
+	}
+
+	if _, _, err = rls.Statement(ctx, DialectMySQL); err == nil {
+		t.Fatalf("Statement() on mysql: want error")
+	}
+}
+
+func TestTenantRLS_CheckOutsideTx(t *testing.T) {
+	rls := TenantRLS{}
+	if err := rls.CheckOutsideTx(context.Background()); !errors.Is(err, ErrMissingTenant) {
+		t.Fatalf("CheckOutsideTx() without tenant = %v, want ErrMissingTenant", err)
+	}
+	if err := rls.CheckOutsideTx(WithTenant(context.Background(), "acme")); !errors.Is(err, ErrTenantTxRequired) {
+		t.Fatalf("CheckOutsideTx() with tenant = %v, want ErrTenantTxRequired", err)
+	}
+	if err := rls.CheckOutsideTx(WithTenantBypass(context.Background())); !errors.Is(err, ErrTenantTxRequired) {
+		t.Fatalf("CheckOutsideTx() with bypass = %v, want ErrTenantTxRequired", err)
+	}
+}
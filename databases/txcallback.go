@@ -0,0 +1,106 @@
+package databases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// TxCallback is a function registered with AfterCommit or AfterRollback.
+type TxCallback func(ctx context.Context) error
+
+// TxCallbacks collects the callbacks registered with AfterCommit and AfterRollback
+// during one transaction scope: a top-level transaction or a savepoint. It is
+// managed by the sqlx and pgxx RDBMS; it is safe for concurrent use.
+type TxCallbacks struct {
+	mu       sync.Mutex
+	commit   []TxCallback
+	rollback []TxCallback
+}
+
+// NewTxCallbacks creates an empty callback scope.
+func NewTxCallbacks() *TxCallbacks {
+	return &TxCallbacks{}
+}
+
+// OnCommit returns the callbacks registered with AfterCommit, in registration order.
+func (c *TxCallbacks) OnCommit() []TxCallback {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]TxCallback(nil), c.commit...)
+}
+
+// OnRollback returns the callbacks registered with AfterRollback, in registration order.
+func (c *TxCallbacks) OnRollback() []TxCallback {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]TxCallback(nil), c.rollback...)
+}
+
+// MergeInto appends the callbacks of c to parent, used when a savepoint is released:
+// its work, and so its callbacks, now depend on the outcome of the parent scope.
+func (c *TxCallbacks) MergeInto(parent *TxCallbacks) {
+	commit, rollback := c.OnCommit(), c.OnRollback()
+
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+	parent.commit = append(parent.commit, commit...)
+	parent.rollback = append(parent.rollback, rollback...)
+}
+
+type txCallbacksKey struct{}
+
+// WithTxCallbacks returns a context whose AfterCommit and AfterRollback register into c.
+// It is set by DoTxContext on the context passed to the transaction function.
+func WithTxCallbacks(ctx context.Context, c *TxCallbacks) context.Context {
+	return context.WithValue(ctx, txCallbacksKey{}, c)
+}
+
+// AfterCommit registers fn to run once the transaction of ctx has committed, e.g. to
+// publish an event or invalidate a cache. Callbacks run in registration order with a
+// context free of the transaction; their errors and panics are reported through the
+// OpAfterCommit hooks of the RDBMS and never change the result of DoTxContext.
+//
+// Within a savepoint (nested DoTxContext), fn is discarded if the savepoint is rolled
+// back and otherwise waits for the outermost commit.
+// Outside a transaction there is nothing to wait for: fn runs immediately and its
+// error (or recovered panic) is returned.
+func AfterCommit(ctx context.Context, fn TxCallback) error {
+	c, ok := ctx.Value(txCallbacksKey{}).(*TxCallbacks)
+	if !ok || c == nil {
+		return RunTxCallback(ctx, fn)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commit = append(c.commit, fn)
+	return nil
+}
+
+// AfterRollback registers fn to run once the transaction of ctx has rolled back, or
+// failed to commit. Within a savepoint, fn runs as soon as the savepoint is rolled back
+// (the outer transaction is still open) and otherwise waits for the outcome of the
+// outermost transaction. Errors and panics are reported like for AfterCommit.
+// Transactions retried by a TxRetryPolicy run the callbacks of every failed attempt.
+//
+// Outside a transaction nothing can be rolled back: fn is discarded.
+func AfterRollback(ctx context.Context, fn TxCallback) {
+	c, ok := ctx.Value(txCallbacksKey{}).(*TxCallbacks)
+	if !ok || c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollback = append(c.rollback, fn)
+}
+
+// RunTxCallback runs fn, converting a panic into an error.
+func RunTxCallback(ctx context.Context, fn TxCallback) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return fn(ctx)
+}
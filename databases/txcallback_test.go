@@ -0,0 +1,64 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAfterCommit_OutsideTx(t *testing.T) {
+	ran := false
+	err := AfterCommit(context.Background(), func(context.Context) error {
+		ran = true
+		return errors.New("boom")
+	})
+	if !ran || err == nil || err.Error() != "boom" {
+		t.Fatalf("AfterCommit() outside tx: ran = %v, err = %v", ran, err)
+	}
+
+	err = AfterCommit(context.Background(), func(context.Context) error { panic("oops") })
+	if err == nil || err.Error() != "panic: oops" {
+		t.Fatalf("AfterCommit() panic err = %v, want panic: oops", err)
+	}
+
+	AfterRollback(context.Background(), func(context.Context) error {
+		t.Fatalf("AfterRollback() outside tx ran")
+		return nil
+	})
+}
+
+func TestTxCallbacks_Scopes(t *testing.T) {
+	var order []string
+	record := func(name string) TxCallback {
+		return func(context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	outer := NewTxCallbacks()
+	ctx := WithTxCallbacks(context.Background(), outer)
+	if err := AfterCommit(ctx, record("outer")); err != nil {
+		t.Fatalf("AfterCommit() error = %v", err)
+	}
+
+	released := NewTxCallbacks()
+	spCtx := WithTxCallbacks(ctx, released)
+	_ = AfterCommit(spCtx, record("released"))
+	AfterRollback(spCtx, record("released-rollback"))
+	released.MergeInto(outer)
+
+	if len(order) != 0 {
+		t.Fatalf("callbacks ran before commit: %v", order)
+	}
+	for _, fn := range outer.OnCommit() {
+		_ = RunTxCallback(ctx, fn)
+	}
+	for _, fn := range outer.OnRollback() {
+		_ = RunTxCallback(ctx, fn)
+	}
+	if want := []string{"outer", "released", "released-rollback"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,137 @@
+package databases
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+)
+
+// DefaultVersionColumn is the version column used by VersionedUpdate when Column is empty.
+const DefaultVersionColumn = "version"
+
+// ErrVersionConflict is the cause of the apperror.Conflict returned by UpdateWithVersion
+// when the row exists but its version no longer matches.
+var ErrVersionConflict = errors.New("version conflict: row was modified concurrently")
+
+// ErrInvalidETag is returned by ParseETag for values that do not hold a Version.
+var ErrInvalidETag = errors.New("invalid etag")
+
+// Version is the optimistic-locking version of an entity, stored in an integer column
+// that is incremented by every UpdateWithVersion.
+type Version int64
+
+// Next returns the version following v.
+func (v Version) Next() Version {
+	return v + 1
+}
+
+// ETag returns v as a strong HTTP entity tag, e.g. "3" (with quotes).
+func (v Version) ETag() string {
+	return strconv.Quote(strconv.FormatInt(int64(v), 10))
+}
+
+// ParseETag parses an entity tag produced by Version.ETag, as received in an
+// If-Match header. Weak tags (W/"3") are accepted.
+func ParseETag(tag string) (Version, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidETag, tag)
+	}
+
+	n, err := strconv.ParseInt(s[1:len(s)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidETag, tag)
+	}
+	return Version(n), nil
+}
+
+// VersionedUpdate describes an UPDATE guarded by a version column, executed with
+// UpdateWithVersion of the sqlx or pgxx RDBMS.
+//
+// Example:
+//
+//	next, err := db.UpdateWithVersion(ctx, databases.VersionedUpdate{
+//	    Table:   "orders",
+//	    Where:   squirrel.Eq{"id": id},
+//	    Set:     map[string]any{"status": "paid"},
+//	    Version: version, // e.g. from the If-Match header
+//	})
+type VersionedUpdate struct {
+	Table   string           // Table to update
+	Where   squirrel.Sqlizer // Identifies the row, e.g. squirrel.Eq{"id": id}
+	Set     map[string]any   // Columns to set; the version column is incremented automatically
+	Version Version          // Version the caller read
+	Column  string           // Version column. Default: DefaultVersionColumn
+}
+
+func (u VersionedUpdate) column() string {
+	if u.Column == "" {
+		return DefaultVersionColumn
+	}
+	return u.Column
+}
+
+// UpdateQuery returns the guarded UPDATE: it sets u.Set, increments the version
+// and only matches the row while its version equals u.Version.
+func (u VersionedUpdate) UpdateQuery(d Dialect) squirrel.UpdateBuilder {
+	column := u.column()
+	query := squirrel.Update(u.Table).
+		SetMap(u.Set).
+		Set(column, squirrel.Expr(column+" + 1")).
+		PlaceholderFormat(d.Placeholder())
+	if u.Where != nil {
+		query = query.Where(u.Where)
+	}
+	return query.Where(squirrel.Eq{column: u.Version})
+}
+
+// ExistsQuery returns the SELECT used after a zero-row update to tell a version
+// conflict apart from a missing row.
+func (u VersionedUpdate) ExistsQuery(d Dialect) squirrel.SelectBuilder {
+	query := squirrel.Select("1").From(u.Table).Limit(1).PlaceholderFormat(d.Placeholder())
+	if u.Where != nil {
+		query = query.Where(u.Where)
+	}
+	return query
+}
+
+// NoRowsError returns the error for an update that affected zero rows: an
+// apperror.Conflict caused by ErrVersionConflict if the row exists, otherwise an
+// apperror.NotFound caused by ErrNoUpdateRow.
+func (u VersionedUpdate) NoRowsError(exists bool) error {
+	if exists {
+		return apperror.Conflict(
+			fmt.Sprintf("%s: version %d is stale", u.Table, u.Version),
+			apperror.WithCause(ErrVersionConflict),
+			apperror.WithPublicMessage("the resource was modified by another request, reload and try again"),
+		)
+	}
+	return apperror.NotFound(
+		fmt.Sprintf("%s: row to update not found", u.Table),
+		apperror.WithCause(ErrNoUpdateRow),
+		apperror.WithPublicMessage("resource not found"),
+	)
+}
+
+// ParseIfMatch parses the If-Match header of a conditional update into the Version the
+// client read. It returns an apperror.BadRequest if the header is missing, is "*" or
+// does not hold a single Version ETag.
+func ParseIfMatch(header string) (Version, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, apperror.BadRequest("missing If-Match header",
+			apperror.WithPublicMessage("If-Match header is required"))
+	}
+
+	v, err := ParseETag(header)
+	if err != nil {
+		return 0, apperror.BadRequest(err.Error(),
+			apperror.WithCause(err),
+			apperror.WithPublicMessage("If-Match header must contain a single ETag"))
+	}
+	return v, nil
+}
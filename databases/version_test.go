@@ -0,0 +1,62 @@
+package databases
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+)
+
+func TestVersion_ETag(t *testing.T) {
+	if got := Version(3).ETag(); got != `"3"` {
+		t.Fatalf("ETag() = %s, want \"3\"", got)
+	}
+	for _, tag := range []string{`"3"`, `W/"3"`, ` "3" `} {
+		if v, err := ParseETag(tag); err != nil || v != 3 {
+			t.Fatalf("ParseETag(%q) = %d, %v; want 3", tag, v, err)
+		}
+	}
+	for _, tag := range []string{`3`, `"x"`, `"-1"`, `*`, `"1", "2"`} {
+		if _, err := ParseETag(tag); !errors.Is(err, ErrInvalidETag) {
+			t.Fatalf("ParseETag(%q) error = %v, want ErrInvalidETag", tag, err)
+		}
+	}
+	if _, err := ParseIfMatch(""); !apperror.IsBadRequest(err) {
+		t.Fatalf("ParseIfMatch(\"\") error = %v, want bad request", err)
+	}
+}
+
+func TestVersionedUpdate_Queries(t *testing.T) {
+	u := VersionedUpdate{
+		Table:   "orders",
+		Where:   squirrel.Eq{"id": 7},
+		Set:     map[string]any{"status": "paid"},
+		Version: 3,
+	}
+
+	sql, args, err := u.UpdateQuery(DialectPostgres).ToSql()
+	if err != nil {
+		t.Fatalf("UpdateQuery().ToSql() error = %v", err)
+	}
+	wantSQL := "UPDATE orders SET status = $1, version = version + 1 WHERE id = $2 AND version = $3"
+	if sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", sql, wantSQL)
+	}
+	if want := []any{"paid", 7, Version(3)}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+
+	sql, _, _ = u.ExistsQuery(DialectMySQL).ToSql()
+	if want := "SELECT 1 FROM orders WHERE id = ? LIMIT 1"; sql != want {
+		t.Fatalf("exists sql = %q, want %q", sql, want)
+	}
+
+	if err := u.NoRowsError(true); !apperror.IsConflict(err) || !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("NoRowsError(true) = %v, want conflict", err)
+	}
+	if err := u.NoRowsError(false); !apperror.IsNotFound(err) || !errors.Is(err, ErrNoUpdateRow) {
+		t.Fatalf("NoRowsError(false) = %v, want not found", err)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/filter"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/validatorx"
 	"github.com/go-playground/validator/v10"
@@ -83,7 +85,8 @@ func Write(w http.ResponseWriter, status int, contentType string, v any) {
 }
 
 // ErrorResponse writes an error response to the context.
-// If the error is of type *apperror.Error, it uses the associated HTTP code.
+// If the error is of type *apperror.Error, it uses the associated HTTP code
+// and returns its violations under "error_validations".
 // Internal server errors are masked with a generic message.
 func ErrorResponse(w http.ResponseWriter, r *http.Request, err error) *http.Request {
 	if err == nil {
@@ -102,10 +105,15 @@ func ErrorResponse(w http.ResponseWriter, r *http.Request, err error) *http.Requ
 		}
 	}
 
-	r = SetError(r, err)
-	Write(w, httpCode, "application/json", map[string]string{
+	body := map[string]any{
 		"message": msg,
-	})
+	}
+	if ok && len(apperr.Violations) > 0 {
+		body["error_validations"] = apperr.Violations
+	}
+
+	r = SetError(r, err)
+	Write(w, httpCode, "application/json", body)
 
 	return r
 }
@@ -187,6 +195,36 @@ func BindToPaginationInput(r *http.Request) primitive.PaginationInput {
 	}
 }
 
+// BindToCursorPaginationInput extracts cursor pagination params from URL query.
+// Defaults: page_size=25, with_total=false.
+// Accepts both "page_size" and "pageSize", and both "with_total" and "withTotal".
+func BindToCursorPaginationInput(r *http.Request) primitive.CursorPaginationInput {
+	const defaultPageSize int64 = 25
+
+	q := r.URL.Query()
+
+	psRaw := q.Get("page_size")
+	if psRaw == "" {
+		psRaw = q.Get("pageSize")
+	}
+	pageSize := parseInt64OrDefault(psRaw, defaultPageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	wtRaw := q.Get("with_total")
+	if wtRaw == "" {
+		wtRaw = q.Get("withTotal")
+	}
+	withTotal, _ := strconv.ParseBool(wtRaw)
+
+	return primitive.CursorPaginationInput{
+		Cursor:    q.Get("cursor"),
+		PageSize:  pageSize,
+		WithTotal: withTotal,
+	}
+}
+
 func parseInt64OrDefault(s string, def int64) int64 {
 	if s == "" {
 		return def
@@ -197,3 +235,22 @@ func parseInt64OrDefault(s string, def int64) int64 {
 	}
 	return n
 }
+
+// BindToFilter parses "filter[field][operator]=value" query parameters into a filter group
+// using schema, which declares the allowed fields, their columns and operators.
+// Invalid parameters are reported as an apperror.BadRequest with violations.
+func BindToFilter(r *http.Request, schema filter.Schema) (*filter.Group, error) {
+	return schema.Parse(r.URL.Query())
+}
+
+// IfMatch reads the entity version the client expects from the If-Match header,
+// for use with UpdateWithVersion of the sqlx/pgxx RDBMS.
+// A missing or invalid header is reported as an apperror.BadRequest.
+func IfMatch(r *http.Request) (databases.Version, error) {
+	return databases.ParseIfMatch(r.Header.Get("If-Match"))
+}
+
+// SetETag sets the ETag response header to the entity version v.
+func SetETag(w http.ResponseWriter, v databases.Version) {
+	w.Header().Set("ETag", v.ETag())
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/filter"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/validatorx"
 	"github.com/go-playground/validator/v10"
@@ -240,6 +242,7 @@ func (h *EchoxHelper) GetFile(c *echo.Context, key string) (primitive.FileUpload
 // Behavior:
 //   - If err is nil, returns nil without writing a response
 //   - If err is *apperror.Error, extracts the HTTP code and public message
+//   - Violations of an *apperror.Error are returned under the validation error key
 //   - For other errors, returns 500 Internal Server Error with a generic message
 //   - Stores the full error details in the Echo context for logging/debugging
 //
@@ -281,17 +284,18 @@ func (h *EchoxHelper) ErrorResponse(c *echo.Context, err error) error {
 		c.Set(errKeyValue, err.Error())
 	}
 
+	body := map[string]any{
+		h.keyJsonMessage: msg,
+	}
+	if ok && len(apperr.Violations) > 0 {
+		body[h.keyErrorValidation] = apperr.Violations
+	}
+
 	if h.DebugMode {
 		stackmsg := apperr.PrettyErrorStack()
-		return c.JSON(httpCode, map[string]any{
-			h.keyJsonMessage: msg,
-			"stack":          stackToSlice(stackmsg),
-		})
-	} else {
-		return c.JSON(httpCode, map[string]string{
-			h.keyJsonMessage: msg,
-		})
+		body["stack"] = stackToSlice(stackmsg)
 	}
+	return c.JSON(httpCode, body)
 }
 
 func stackToSlice(stack string) []string {
@@ -500,3 +504,104 @@ func (h *EchoxHelper) BindToPaginationInput(c *echo.Context) primitive.Paginatio
 
 	return pagination
 }
+
+// BindToCursorPaginationInput extracts cursor (keyset) pagination parameters from query parameters.
+// It parses "cursor", "page_size" and "with_total" query parameters and returns a
+// CursorPaginationInput struct with sensible defaults if they are not provided or invalid.
+//
+// Parameters:
+//   - c: Echo context containing the query parameters
+//
+// Returns:
+//   - primitive.CursorPaginationInput: struct containing cursor, page size and total flag
+//
+// Default Values:
+//   - cursor: empty (first page)
+//   - page_size: MaxPageSize from EchoxHelper config (default 25 if not provided or invalid)
+//   - with_total: false
+//
+// Example:
+//
+//	func ListUsers(c *echo.Context) error {
+//	    pagination := Helper().BindToCursorPaginationInput(c)
+//	    users, page := service.ListUsers(pagination)
+//	    return c.JSON(200, map[string]any{
+//	        "data":        users,
+//	        "next_cursor": page.NextCursor,
+//	        "prev_cursor": page.PrevCursor,
+//	    })
+//	}
+func (h *EchoxHelper) BindToCursorPaginationInput(c *echo.Context) primitive.CursorPaginationInput {
+	pagination := primitive.CursorPaginationInput{
+		Cursor:   c.QueryParam("cursor"),
+		PageSize: h.MaxPageSize,
+	}
+
+	pageSize, _ := strconv.ParseInt(c.QueryParam("page_size"), 10, 64)
+	if pageSize > 0 {
+		pagination.PageSize = pageSize
+	}
+	pagination.WithTotal, _ = strconv.ParseBool(c.QueryParam("with_total"))
+
+	return pagination
+}
+
+// BindToFilter parses "filter[field][operator]=value" query parameters into a filter group
+// using schema, which declares the allowed fields, their columns and operators.
+//
+// Parameters:
+//   - c: Echo context containing the query parameters
+//   - schema: the allowlist of filterable fields
+//
+// Returns:
+//   - *filter.Group: AND group of the parsed filters, empty when no filter is given
+//   - error: apperror.BadRequest with one violation per invalid parameter
+//
+// Example:
+//
+//	// GET /tasks?filter[status][in]=open,closed&filter[name][ilike]=rama
+//	group, err := Helper().BindToFilter(c, taskFilterSchema)
+//	if err != nil {
+//	    return Helper().ErrorResponse(c, err)
+//	}
+//	where, err := group.BuildSquirrel()
+func (h *EchoxHelper) BindToFilter(c *echo.Context, schema filter.Schema) (*filter.Group, error) {
+	group, err := schema.Parse(c.QueryParams())
+	if err != nil {
+		c.Set(errKeyValue, err.Error())
+		return nil, err
+	}
+	return group, nil
+}
+
+// IfMatch reads the entity version the client expects from the If-Match header,
+// for use with UpdateWithVersion of the sqlx/pgxx RDBMS.
+//
+// Returns:
+//   - databases.Version: version parsed from the ETag
+//   - error: apperror.BadRequest if the header is missing or invalid
+//
+// Example:
+//
+//	version, err := Helper().IfMatch(c)
+//	if err != nil {
+//	    return Helper().ErrorResponse(c, err)
+//	}
+//	next, err := db.UpdateWithVersion(ctx, databases.VersionedUpdate{..., Version: version})
+//	if err != nil {
+//	    return Helper().ErrorResponse(c, err) // 409 on a stale version
+//	}
+//	Helper().SetETag(c, next)
+func (h *EchoxHelper) IfMatch(c *echo.Context) (databases.Version, error) {
+	version, err := databases.ParseIfMatch(c.Request().Header.Get("If-Match"))
+	if err != nil {
+		c.Set(errKeyValue, err.Error())
+		return 0, err
+	}
+	return version, nil
+}
+
+// SetETag sets the ETag response header to the entity version v.
+func (h *EchoxHelper) SetETag(c *echo.Context, v databases.Version) {
+	c.Response().Header().Set("ETag", v.ETag())
+}
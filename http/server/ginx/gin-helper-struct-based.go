@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/apperror"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/filter"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/validatorx"
 	"github.com/gin-gonic/gin"
@@ -88,7 +90,8 @@ func (h *GinHelper) MustShouldBind(c *gin.Context, req any) bool {
 }
 
 // ErrorResponse writes an error response to the context.
-// If the error is of type *apperror.Error, it uses the associated HTTP code.
+// If the error is of type *apperror.Error, it uses the associated HTTP code
+// and returns its violations under the validation error key.
 // Internal server errors are masked with a generic message.
 func (h *GinHelper) ErrorResponse(c *gin.Context, err error) {
 	if err == nil {
@@ -105,10 +108,14 @@ func (h *GinHelper) ErrorResponse(c *gin.Context, err error) {
 			msg = apperr.PublicMessage
 		}
 	}
-	_ = c.Error(err)
-	c.JSON(httpCode, map[string]string{
+	body := map[string]any{
 		h.keyJsonMessage: msg,
-	})
+	}
+	if ok && len(apperr.Violations) > 0 {
+		body[h.keyErrorValidation] = apperr.Violations
+	}
+	_ = c.Error(err)
+	c.JSON(httpCode, body)
 }
 
 // ParseQueryToSliceInt64 parses a comma-separated string query value into a slice of int64.
@@ -178,3 +185,40 @@ func (h *GinHelper) BindToPaginationInput(c *gin.Context) primitive.PaginationIn
 
 	return pagination
 }
+
+// BindToCursorPaginationInput extracts cursor pagination parameters from the context.
+// It reads "cursor", "page_size" and "with_total" query parameters.
+// If parameters are not set, it defaults to pageSize=25 without total count.
+func (h *GinHelper) BindToCursorPaginationInput(c *gin.Context) primitive.CursorPaginationInput {
+	pagination := primitive.CursorPaginationInput{
+		Cursor:   c.Query("cursor"),
+		PageSize: 25,
+	}
+
+	pageSize, _ := strconv.ParseInt(c.Query("page_size"), 10, 64)
+	if pageSize > 0 {
+		pagination.PageSize = pageSize
+	}
+	pagination.WithTotal, _ = strconv.ParseBool(c.Query("with_total"))
+
+	return pagination
+}
+
+// BindToFilter parses "filter[field][operator]=value" query parameters into a filter group
+// using schema, which declares the allowed fields, their columns and operators.
+// Invalid parameters are reported as an apperror.BadRequest with violations.
+func (h *GinHelper) BindToFilter(c *gin.Context, schema filter.Schema) (*filter.Group, error) {
+	return schema.Parse(c.Request.URL.Query())
+}
+
+// IfMatch reads the entity version the client expects from the If-Match header,
+// for use with UpdateWithVersion of the sqlx/pgxx RDBMS.
+// A missing or invalid header is reported as an apperror.BadRequest.
+func (h *GinHelper) IfMatch(c *gin.Context) (databases.Version, error) {
+	return databases.ParseIfMatch(c.GetHeader("If-Match"))
+}
+
+// SetETag sets the ETag response header to the entity version v.
+func (h *GinHelper) SetETag(c *gin.Context, v databases.Version) {
+	c.Header("ETag", v.ETag())
+}
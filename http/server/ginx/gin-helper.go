@@ -1,6 +1,8 @@
 package ginx
 
 import (
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/filter"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/utils/primitive"
 	"github.com/gin-gonic/gin"
 )
@@ -52,3 +54,26 @@ func ParseQueryToSliceString(value *string) []string {
 func BindToPaginationInput(c *gin.Context) primitive.PaginationInput {
 	return defaultHelper.BindToPaginationInput(c)
 }
+
+// BindToCursorPaginationInput extracts cursor pagination parameters from the context.
+// If parameters are not set, it defaults to pageSize=25 without total count.
+func BindToCursorPaginationInput(c *gin.Context) primitive.CursorPaginationInput {
+	return defaultHelper.BindToCursorPaginationInput(c)
+}
+
+// BindToFilter parses "filter[field][operator]=value" query parameters into a filter group
+// using schema. Invalid parameters are reported as an apperror.BadRequest with violations.
+func BindToFilter(c *gin.Context, schema filter.Schema) (*filter.Group, error) {
+	return defaultHelper.BindToFilter(c, schema)
+}
+
+// IfMatch reads the entity version the client expects from the If-Match header.
+// A missing or invalid header is reported as an apperror.BadRequest.
+func IfMatch(c *gin.Context) (databases.Version, error) {
+	return defaultHelper.IfMatch(c)
+}
+
+// SetETag sets the ETag response header to the entity version v.
+func SetETag(c *gin.Context, v databases.Version) {
+	defaultHelper.SetETag(c, v)
+}
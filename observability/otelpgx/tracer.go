@@ -13,8 +13,10 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"strings"
 	"time"
 
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -32,6 +34,7 @@ const (
 	tracerName                 = "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx/otelpgx"
 	meterName                  = "github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/pgxx/otelpgx"
 	startTimeCtxKey     ctxKey = "otelpgxStartTime"
+	operationCtxKey     ctxKey = "otelpgxOperation"
 	sqlOperationUnknown        = "UNKNOWN"
 )
 
@@ -42,6 +45,10 @@ const (
 	pgxOperationConnect = "connect"
 	pgxOperationPrepare = "prepare"
 	pgxOperationAcquire = "acquire"
+
+	pgxOperationSavepoint           = "savepoint"
+	pgxOperationRollbackToSavepoint = "rollback_to_savepoint"
+	pgxOperationReleaseSavepoint    = "release_savepoint"
 )
 
 const (
@@ -54,6 +61,8 @@ const (
 	// SQLStateKey represents PostgreSQL error code,
 	// see https://www.postgresql.org/docs/current/errcodes-appendix.html.
 	SQLStateKey = attribute.Key("pgx.sql_state")
+	// TxAttemptKey represents the 1-based attempt of a retried transaction.
+	TxAttemptKey = attribute.Key("pgx.tx.attempt")
 	// PGXOperationTypeKey represents the pgx tracer operation type
 	PGXOperationTypeKey = attribute.Key("pgx.operation.type")
 	// DBClientOperationErrorsKey represents the count of operation errors
@@ -170,7 +179,9 @@ func connectionAttributesFromConfig(config *pgx.ConnConfig) trace.SpanStartOptio
 // TraceQueryStart is called at the beginning of Query, QueryRow, and Exec calls.
 // The returned context is used for the rest of the call and will be passed to TraceQueryEnd.
 func (t *Tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := queryOperation(data.SQL)
 	ctx = context.WithValue(ctx, startTimeCtxKey, time.Now())
+	ctx = context.WithValue(ctx, operationCtxKey, operation)
 
 	if !trace.SpanFromContext(ctx).IsRecording() {
 		return ctx
@@ -180,8 +191,13 @@ func (t *Tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.T
 	opts = append(opts,
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(t.tracerAttrs...),
+		trace.WithAttributes(PGXOperationTypeKey.String(operation)),
 	)
 
+	if attempt, ok := databases.TxAttemptFromContext(ctx); ok {
+		opts = append(opts, trace.WithAttributes(TxAttemptKey.Int(attempt)))
+	}
+
 	if t.logConnectionDetails && conn != nil {
 		opts = append(opts, connectionAttributesFromConfig(conn.Config()))
 	}
@@ -213,9 +229,14 @@ func (t *Tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.T
 
 // TraceQueryEnd is called at the end of Query, QueryRow, and Exec calls.
 func (t *Tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	operation, ok := ctx.Value(operationCtxKey).(string)
+	if !ok {
+		operation = pgxOperationQuery
+	}
+
 	span := trace.SpanFromContext(ctx)
 	recordSpanError(span, data.Err)
-	t.incrementOperationErrorCount(ctx, data.Err, pgxOperationQuery)
+	t.incrementOperationErrorCount(ctx, data.Err, operation)
 
 	if data.Err == nil {
 		span.SetAttributes(RowsAffectedKey.Int64(data.CommandTag.RowsAffected()))
@@ -223,7 +244,24 @@ func (t *Tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQ
 
 	span.End()
 
-	t.recordOperationDuration(ctx, pgxOperationQuery)
+	t.recordOperationDuration(ctx, operation)
+}
+
+// queryOperation classifies a statement executed through Query, QueryRow or Exec.
+// Savepoint statements issued for nested transactions get their own operation type
+// so they can be told apart from regular queries in spans and metrics.
+func queryOperation(sql string) string {
+	stmt := strings.ToUpper(strings.TrimSpace(sql))
+	switch {
+	case strings.HasPrefix(stmt, "SAVEPOINT "):
+		return pgxOperationSavepoint
+	case strings.HasPrefix(stmt, "ROLLBACK TO "):
+		return pgxOperationRollbackToSavepoint
+	case strings.HasPrefix(stmt, "RELEASE "):
+		return pgxOperationReleaseSavepoint
+	default:
+		return pgxOperationQuery
+	}
 }
 
 // TraceCopyFromStart is called at the beginning of CopyFrom calls. The
@@ -33,6 +33,14 @@ func (t *Tracer) createMetrics() {
 	if err != nil {
 		otel.Handle(err)
 	}
+
+	t.stmtCacheLookups, err = t.meter.Int64Counter(
+		string(DBClientStmtCacheLookupsKey),
+		metric.WithDescription("The count of prepared statement cache lookups, by hit or miss"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
 }
 
 // incrementOperationErrorCount will increment the operation error count metric for any provided error
@@ -51,3 +59,10 @@ func (t *Tracer) recordOperationDuration(ctx context.Context, operation string,
 		attribute.NewSet(append(t.meterAttrs, OperationTypeKey.String(operation))...),
 	))
 }
+
+// recordStmtCacheLookup counts a statement cache lookup, labeled by whether it was a hit.
+func (t *Tracer) recordStmtCacheLookup(ctx context.Context, operation string, hit bool) {
+	t.stmtCacheLookups.Add(ctx, 1, metric.WithAttributeSet(
+		attribute.NewSet(append(t.meterAttrs, OperationTypeKey.String(operation), SqlxStmtCacheHit.Bool(hit))...),
+	))
+}
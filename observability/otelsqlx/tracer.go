@@ -8,6 +8,7 @@ import (
 	"runtime/debug"
 	"time"
 
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases"
 	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/databases/sqlx"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -31,7 +32,15 @@ const (
 
 	SqlxInTx        = attribute.Key("sqlx.in_tx")
 	SqlxUsePrepared = attribute.Key("sqlx.use_prepared")
-
+	// SqlxNode represents the node ("primary" or replica name) that served the operation.
+	SqlxNode = attribute.Key("sqlx.node")
+	// SqlxTxAttempt represents the 1-based attempt of a retried transaction.
+	SqlxTxAttempt = attribute.Key("sqlx.tx.attempt")
+
+	// SqlxStmtCacheHit represents whether the prepared statement was served from the statement cache.
+	SqlxStmtCacheHit = attribute.Key("sqlx.stmt_cache.hit")
+	// DBClientStmtCacheLookupsKey represents the count of statement cache lookups
+	DBClientStmtCacheLookupsKey = attribute.Key("db.client.sqlx.stmt_cache.lookups")
 	// OperationTypeKey represents the sqlx tracer operation type
 	OperationTypeKey = attribute.Key("sqlx.operation.type")
 	// DBClientOperationErrorsKey represents the count of operation errors
@@ -48,6 +57,7 @@ type Tracer struct {
 
 	operationDuration metric.Int64Histogram
 	operationErrors   metric.Int64Counter
+	stmtCacheLookups  metric.Int64Counter
 
 	dbSystem            string
 	dbNamespace         string
@@ -163,6 +173,13 @@ func (t *Tracer) Before(ctx context.Context, info *sqlx.HookInfo) context.Contex
 		),
 	)
 
+	if info.Node != "" {
+		opts = append(opts, trace.WithAttributes(SqlxNode.String(info.Node)))
+	}
+	if attempt, ok := databases.TxAttemptFromContext(ctx); ok {
+		opts = append(opts, trace.WithAttributes(SqlxTxAttempt.Int(attempt)))
+	}
+
 	// Add database identity attributes for Service Graph
 	opts = append(opts, trace.WithAttributes(semconv.DBSystemKey.String(t.dbSystem)))
 	if t.dbNamespace != "" {
@@ -224,6 +241,10 @@ func (t *Tracer) After(ctx context.Context, info *sqlx.HookInfo) {
 			RowsAffectedKey.Int64(*info.Rows),
 		)
 	}
+	if info.CacheHit != nil {
+		span.SetAttributes(SqlxStmtCacheHit.Bool(*info.CacheHit))
+		t.recordStmtCacheLookup(ctx, string(info.Op), *info.CacheHit)
+	}
 
 	span.End()
 
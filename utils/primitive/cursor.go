@@ -0,0 +1,368 @@
+package primitive
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/SyaibanAhmadRamadhan/go-foundation-kit/security/signature"
+)
+
+// ErrInvalidCursor is returned when a cursor token is malformed, has been
+// tampered with, or was issued for a different sort specification.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrCursorSecretRequired is returned when cursors are encoded or decoded
+// without a signing secret.
+var ErrCursorSecretRequired = errors.New("cursor secret is required")
+
+// CursorPaginationInput holds the input parameters used for keyset (cursor) pagination.
+//
+// Fields:
+//   - Cursor: opaque token returned as NextCursor/PrevCursor by a previous page (empty for the first page)
+//   - PageSize: the number of items per page
+//   - WithTotal: when true, the total number of rows is counted with the count query
+type CursorPaginationInput struct {
+	Cursor    string
+	PageSize  int64
+	WithTotal bool
+}
+
+// CursorPaginationOutput provides metadata for keyset (cursor) paginated results.
+//
+// Fields:
+//   - PageSize: the number of items per page
+//   - NextCursor: token for the following page (empty when HasNext is false)
+//   - PrevCursor: token for the preceding page (empty when HasPrev is false)
+//   - HasNext: true if more rows exist after this page
+//   - HasPrev: true if rows exist before this page
+//   - Reversed: true if rows were delivered in reverse sort order (backward page);
+//     callers should reverse their collected items, e.g. with generic.ReverseSlice
+//   - TotalData: the total number of rows, only set when CursorPaginationInput.WithTotal is true
+type CursorPaginationOutput struct {
+	PageSize   int64
+	NextCursor string
+	PrevCursor string
+	HasNext    bool
+	HasPrev    bool
+	Reversed   bool
+	TotalData  *int64
+}
+
+// Keyset describes the ordering used for keyset pagination.
+// Sorting defines the ordering columns, and TieBreaker is a unique column
+// (typically the primary key) appended to the ordering so every row has a
+// distinct position. Keyset columns are expected to be NOT NULL.
+// Build it with SortSpec.Keyset when the ordering comes from client input.
+//
+// Example:
+//
+//	keyset := primitive.Keyset{
+//	    Sorting:    primitive.NewSortingFromQueryParams("desc", "created_at"),
+//	    TieBreaker: "id",
+//	}
+type Keyset struct {
+	Sorting    Sorting
+	TieBreaker string
+}
+
+// Cursor is the decoded content of a cursor token.
+//
+// Fields:
+//   - Values: the ordering column values of the boundary row, in keyset column order
+//   - Backward: true if the cursor points to the page before the boundary row
+type Cursor struct {
+	Values   []any
+	Backward bool
+}
+
+type keysetColumn struct {
+	name string
+	desc bool
+}
+
+// columns returns the effective ordering columns, including the tie-breaker.
+func (k Keyset) columns() []keysetColumn {
+	cols := make([]keysetColumn, 0, len(k.Sorting.SortFields)+1)
+	hasTieBreaker := false
+	for i, field := range k.Sorting.SortFields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if field == k.TieBreaker {
+			hasTieBreaker = true
+		}
+		cols = append(cols, keysetColumn{name: field, desc: k.Sorting.isDesc(i)})
+	}
+
+	if k.TieBreaker != "" && !hasTieBreaker {
+		desc := false
+		if len(cols) > 0 {
+			desc = cols[len(cols)-1].desc
+		}
+		cols = append(cols, keysetColumn{name: k.TieBreaker, desc: desc})
+	}
+	return cols
+}
+
+// fingerprint identifies the sort specification a cursor was issued for.
+func (k Keyset) fingerprint() string {
+	parts := make([]string, 0)
+	for _, c := range k.columns() {
+		dir := "asc"
+		if c.desc {
+			dir = "desc"
+		}
+		parts = append(parts, c.name+" "+dir)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, ",")))
+	return base64.RawURLEncoding.EncodeToString(sum[:8])
+}
+
+// BuildSquirrel applies the keyset predicate, ORDER BY and LIMIT to the query.
+// If cursor is nil, the first page is selected. One extra row beyond pageSize
+// is requested so callers can detect whether more rows exist.
+// When cursor.Backward is true, the ordering is flipped, so rows are returned
+// in reverse sort order.
+func (k Keyset) BuildSquirrel(query squirrel.SelectBuilder, cursor *Cursor, pageSize int64) (squirrel.SelectBuilder, error) {
+	cols := k.columns()
+	if len(cols) == 0 {
+		return query, errors.New("keyset requires at least one ordering column")
+	}
+
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		if len(cursor.Values) != len(cols) {
+			return query, ErrInvalidCursor
+		}
+		query = query.Where(keysetPredicate(cols, cursor.Values, backward))
+	}
+
+	for _, c := range cols {
+		dir := "ASC"
+		if c.desc != backward {
+			dir = "DESC"
+		}
+		query = query.OrderBy(fmt.Sprintf("%s %s", c.name, dir))
+	}
+
+	return query.Limit(uint64(pageSize) + 1), nil
+}
+
+// keysetPredicate builds (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ...,
+// using < instead of > for descending columns (inverted when paging backward).
+func keysetPredicate(cols []keysetColumn, values []any, backward bool) squirrel.Sqlizer {
+	or := make(squirrel.Or, 0, len(cols))
+	for i, c := range cols {
+		and := make(squirrel.And, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, squirrel.Eq{cols[j].name: values[j]})
+		}
+		if c.desc != backward {
+			and = append(and, squirrel.Lt{c.name: values[i]})
+		} else {
+			and = append(and, squirrel.Gt{c.name: values[i]})
+		}
+		or = append(or, and)
+	}
+	return or
+}
+
+// NewCursorPaginationOutput builds the pagination metadata for a fetched page.
+//
+// Parameters:
+//   - k: the keyset used for the query
+//   - input: the pagination input of the request
+//   - cursor: the decoded input cursor (nil for the first page)
+//   - keys: ordering values of the delivered rows, in delivery order
+//   - hasMore: true if the query returned more rows than the page size
+//   - secret: the secret used to sign the returned cursors
+func NewCursorPaginationOutput(
+	k Keyset,
+	input CursorPaginationInput,
+	cursor *Cursor,
+	keys [][]any,
+	hasMore bool,
+	secret string,
+) (CursorPaginationOutput, error) {
+	out := CursorPaginationOutput{PageSize: input.PageSize}
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		out.Reversed = true
+		out.HasPrev = hasMore
+		out.HasNext = true
+	} else {
+		out.HasNext = hasMore
+		out.HasPrev = cursor != nil
+	}
+
+	if len(keys) == 0 {
+		return out, nil
+	}
+
+	first, last := keys[0], keys[len(keys)-1]
+	if backward {
+		first, last = last, first
+	}
+
+	var err error
+	if out.HasNext {
+		out.NextCursor, err = EncodeCursor(k, Cursor{Values: last}, secret)
+		if err != nil {
+			return CursorPaginationOutput{}, err
+		}
+	}
+	if out.HasPrev {
+		out.PrevCursor, err = EncodeCursor(k, Cursor{Values: first, Backward: true}, secret)
+		if err != nil {
+			return CursorPaginationOutput{}, err
+		}
+	}
+	return out, nil
+}
+
+type cursorPayload struct {
+	Fingerprint string        `json:"k"`
+	Backward    bool          `json:"b,omitempty"`
+	Values      []cursorValue `json:"v"`
+}
+
+type cursorValue struct {
+	Type  string          `json:"t,omitempty"`
+	Value json.RawMessage `json:"v"`
+}
+
+// EncodeCursor serializes and signs a cursor for the given keyset.
+// The token has the form base64url(payload) + "." + hex(HMAC-SHA256(payload)),
+// and is bound to the keyset so it cannot be replayed against another ordering.
+func EncodeCursor(k Keyset, c Cursor, secret string) (string, error) {
+	if secret == "" {
+		return "", ErrCursorSecretRequired
+	}
+
+	payload := cursorPayload{
+		Fingerprint: k.fingerprint(),
+		Backward:    c.Backward,
+		Values:      make([]cursorValue, len(c.Values)),
+	}
+	for i, v := range c.Values {
+		cv, err := encodeCursorValue(v)
+		if err != nil {
+			return "", err
+		}
+		payload.Values[i] = cv
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshal cursor: %w", err)
+	}
+
+	body := base64.RawURLEncoding.EncodeToString(raw)
+	sig, err := signature.CreateHMAC(body, secret, sha256.New)
+	if err != nil {
+		return "", fmt.Errorf("sign cursor: %w", err)
+	}
+	return body + "." + sig, nil
+}
+
+// DecodeCursor verifies and decodes a cursor token issued by EncodeCursor.
+// It returns nil without error when token is empty (first page).
+// ErrInvalidCursor is returned when the token is malformed, its signature does
+// not match, or it was issued for a different keyset.
+func DecodeCursor(k Keyset, token, secret string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	if secret == "" {
+		return nil, ErrCursorSecretRequired
+	}
+
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	valid, err := signature.VerifyHMAC(body, sig, secret, sha256.New)
+	if err != nil || !valid {
+		return nil, ErrInvalidCursor
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err = json.Unmarshal(raw, &payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if payload.Fingerprint != k.fingerprint() || len(payload.Values) != len(k.columns()) {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{Backward: payload.Backward, Values: make([]any, len(payload.Values))}
+	for i, cv := range payload.Values {
+		c.Values[i], err = decodeCursorValue(cv)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return c, nil
+}
+
+func encodeCursorValue(v any) (cursorValue, error) {
+	var typ string
+	switch t := v.(type) {
+	case time.Time:
+		typ, v = "time", t.Format(time.RFC3339Nano)
+	case *time.Time:
+		if t != nil {
+			typ, v = "time", t.Format(time.RFC3339Nano)
+		}
+	case []byte:
+		typ = "bytes"
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return cursorValue{}, fmt.Errorf("marshal cursor value: %w", err)
+	}
+	return cursorValue{Type: typ, Value: raw}, nil
+}
+
+func decodeCursorValue(cv cursorValue) (any, error) {
+	switch cv.Type {
+	case "time":
+		var s string
+		if err := json.Unmarshal(cv.Value, &s); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case "bytes":
+		var b []byte
+		err := json.Unmarshal(cv.Value, &b)
+		return b, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(cv.Value))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if n, ok := v.(json.Number); ok {
+		if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+			return i, nil
+		}
+		return n.Float64()
+	}
+	return v, nil
+}
@@ -0,0 +1,103 @@
+package primitive
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	keyset := Keyset{
+		Sorting:    NewSortingFromQueryParams("desc", "created_at"),
+		TieBreaker: "id",
+	}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	token, err := EncodeCursor(keyset, Cursor{Values: []any{createdAt, int64(42)}, Backward: true}, "secret")
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	got, err := DecodeCursor(keyset, token, "secret")
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	want := &Cursor{Values: []any{createdAt, int64(42)}, Backward: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DecodeCursor() = %+v, want %+v", got, want)
+	}
+
+	if _, err = DecodeCursor(keyset, token, "other-secret"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("DecodeCursor(wrong secret) error = %v, want ErrInvalidCursor", err)
+	}
+
+	tampered := "x" + token[1:]
+	if _, err = DecodeCursor(keyset, tampered, "secret"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("DecodeCursor(tampered) error = %v, want ErrInvalidCursor", err)
+	}
+
+	other := Keyset{Sorting: NewSortingFromQueryParams("asc", "created_at"), TieBreaker: "id"}
+	if _, err = DecodeCursor(other, token, "secret"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("DecodeCursor(other keyset) error = %v, want ErrInvalidCursor", err)
+	}
+
+	if c, err := DecodeCursor(keyset, "", "secret"); c != nil || err != nil {
+		t.Fatalf("DecodeCursor(empty) = %v, %v, want nil, nil", c, err)
+	}
+}
+
+func TestKeyset_BuildSquirrel(t *testing.T) {
+	keyset := Keyset{
+		Sorting:    NewSortingFromQueryParams("desc,asc", "created_at,name"),
+		TieBreaker: "id",
+	}
+	base := squirrel.Select("id").From("users")
+
+	tests := []struct {
+		name     string
+		cursor   *Cursor
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "first page",
+			wantSQL: "SELECT id FROM users ORDER BY created_at DESC, name ASC, id ASC LIMIT 11",
+		},
+		{
+			name:   "forward",
+			cursor: &Cursor{Values: []any{"t", "n", int64(1)}},
+			wantSQL: "SELECT id FROM users WHERE ((created_at < ?) OR (created_at = ? AND name > ?) OR " +
+				"(created_at = ? AND name = ? AND id > ?)) ORDER BY created_at DESC, name ASC, id ASC LIMIT 11",
+			wantArgs: []any{"t", "t", "n", "t", "n", int64(1)},
+		},
+		{
+			name:   "backward",
+			cursor: &Cursor{Values: []any{"t", "n", int64(1)}, Backward: true},
+			wantSQL: "SELECT id FROM users WHERE ((created_at > ?) OR (created_at = ? AND name < ?) OR " +
+				"(created_at = ? AND name = ? AND id < ?)) ORDER BY created_at ASC, name DESC, id DESC LIMIT 11",
+			wantArgs: []any{"t", "t", "n", "t", "n", int64(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := keyset.BuildSquirrel(base, tt.cursor, 10)
+			if err != nil {
+				t.Fatalf("BuildSquirrel() error = %v", err)
+			}
+			sql, args, err := q.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Fatalf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if len(args) != len(tt.wantArgs) || (len(args) > 0 && !reflect.DeepEqual(args, tt.wantArgs)) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
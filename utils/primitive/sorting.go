@@ -1,34 +1,243 @@
 package primitive
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/Masterminds/squirrel"
 )
 
+// ErrInvalidSort is matched (errors.Is) by every *SortError.
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SortError is returned by SortSpec when a requested sort is rejected.
+//
+// Fields:
+//   - Field: the public sort field (or direction) that was rejected
+//   - Reason: a human readable explanation, safe to return to clients
+type SortError struct {
+	Field  string
+	Reason string
+}
+
+func (e *SortError) Error() string {
+	return fmt.Sprintf("invalid sort field %q: %s", e.Field, e.Reason)
+}
+
+func (e *SortError) Unwrap() error { return ErrInvalidSort }
+
+// NullsOrder controls where NULL values are placed in the ordering.
+type NullsOrder string
+
+const (
+	NullsDefault NullsOrder = ""      // database default
+	NullsFirst   NullsOrder = "first" // NULLS FIRST
+	NullsLast    NullsOrder = "last"  // NULLS LAST
+)
+
 type Sorting struct {
 	SortFields     []string
 	SortDirections []string
+
+	// set by SortSpec.Resolve
+	nulls          []NullsOrder
+	nullsEmulation bool
 }
 
 func (s Sorting) BuildSquirrel(sq squirrel.SelectBuilder) squirrel.SelectBuilder {
 	for i, field := range s.SortFields {
 		dir := "ASC" // default ASC
-		if i < len(s.SortDirections) {
-			d := s.SortDirections[i]
-			if d == "desc" || d == "DESC" {
-				dir = "DESC"
+		if s.isDesc(i) {
+			dir = "DESC"
+		}
+
+		nulls := NullsDefault
+		if i < len(s.nulls) {
+			nulls = s.nulls[i]
+		}
+		switch {
+		case nulls == NullsDefault:
+			sq = sq.OrderBy(fmt.Sprintf("%s %s", field, dir))
+		case s.nullsEmulation:
+			// MySQL has no NULLS FIRST/LAST: sort on "IS NULL" first (false < true).
+			nullsDir := "ASC"
+			if nulls == NullsFirst {
+				nullsDir = "DESC"
 			}
+			sq = sq.OrderBy(fmt.Sprintf("%s IS NULL %s", field, nullsDir), fmt.Sprintf("%s %s", field, dir))
+		default:
+			sq = sq.OrderBy(fmt.Sprintf("%s %s NULLS %s", field, dir, strings.ToUpper(string(nulls))))
 		}
-		sq = sq.OrderBy(fmt.Sprintf("%s %s", field, dir))
 	}
 	return sq
 }
 
+// isDesc reports whether the i-th sort field is ordered descending.
+func (s Sorting) isDesc(i int) bool {
+	if i >= len(s.SortDirections) {
+		return false
+	}
+	d := s.SortDirections[i]
+	return d == "desc" || d == "DESC"
+}
+
+// NewSortingFromQueryParams splits comma separated sort fields and directions.
+// The result is not validated: fields come straight from user input, so resolve it
+// with SortSpec.Resolve before building a query.
 func NewSortingFromQueryParams(SortDirection, sortField string) Sorting {
 	return Sorting{
 		SortFields:     strings.Split(sortField, ","),
 		SortDirections: strings.Split(SortDirection, ","),
 	}
 }
+
+// SortColumn is a vetted column expression a public sort field maps to.
+//
+// Fields:
+//   - Column: the SQL column or expression used in ORDER BY
+//   - Nulls: where NULL values are placed; ignored by keyset pagination,
+//     whose columns must be NOT NULL
+type SortColumn struct {
+	Column string
+	Nulls  NullsOrder
+}
+
+// SortSpec is an allowlist of sortable fields. It maps public field names, as
+// received from clients, to vetted column expressions, so user input never
+// reaches ORDER BY.
+//
+// Fields:
+//   - Fields: public field name to column
+//   - Default: public sort used when the request has no sort fields
+//   - TieBreaker: unique column appended to every ordering (typically the primary key)
+//   - MaxKeys: maximum number of requested sort fields (0 = unlimited)
+//   - NullsEmulation: emulate NULLS FIRST/LAST with an "IS NULL" sort key, for
+//     databases without them such as MySQL (see databases.Dialect)
+//
+// Example:
+//
+//	spec := primitive.SortSpec{
+//	    Fields: map[string]primitive.SortColumn{
+//	        "created_at": {Column: "u.created_at"},
+//	        "last_login": {Column: "u.last_login_at", Nulls: primitive.NullsLast},
+//	    },
+//	    Default:    primitive.NewSortingFromQueryParams("desc", "created_at"),
+//	    TieBreaker: "u.id",
+//	    MaxKeys:    2,
+//	}
+//	sorting, err := spec.FromQueryParams(c.QueryParam("sort_direction"), c.QueryParam("sort_field"))
+//	if err != nil {
+//	    return err // *SortError
+//	}
+//	query = sorting.BuildSquirrel(query)
+type SortSpec struct {
+	Fields         map[string]SortColumn
+	Default        Sorting
+	TieBreaker     string
+	MaxKeys        int
+	NullsEmulation bool
+}
+
+// FromQueryParams resolves comma separated sort fields and directions from a request.
+func (s SortSpec) FromQueryParams(sortDirection, sortField string) (Sorting, error) {
+	return s.Resolve(NewSortingFromQueryParams(sortDirection, sortField))
+}
+
+// Resolve validates a requested sort and maps its public fields to columns,
+// appending the tie-breaker. The Default sort is used when in has no fields.
+// Unknown or duplicated fields, invalid directions and more than MaxKeys fields
+// are rejected with a *SortError.
+func (s SortSpec) Resolve(in Sorting) (Sorting, error) {
+	out, err := s.resolve(in)
+	if err != nil {
+		return Sorting{}, err
+	}
+
+	if s.TieBreaker != "" && !slices.Contains(out.SortFields, s.TieBreaker) {
+		dir := "asc"
+		if n := len(out.SortDirections); n > 0 {
+			dir = out.SortDirections[n-1]
+		}
+		out.SortFields = append(out.SortFields, s.TieBreaker)
+		out.SortDirections = append(out.SortDirections, dir)
+		out.nulls = append(out.nulls, NullsDefault)
+	}
+	return out, nil
+}
+
+// Keyset resolves a requested sort like Resolve and returns it as a keyset for
+// cursor pagination, with the spec's TieBreaker.
+func (s SortSpec) Keyset(in Sorting) (Keyset, error) {
+	out, err := s.resolve(in)
+	if err != nil {
+		return Keyset{}, err
+	}
+	return Keyset{Sorting: out, TieBreaker: s.TieBreaker}, nil
+}
+
+func (s SortSpec) resolve(in Sorting) (Sorting, error) {
+	fields, dirs, err := requestedSort(in)
+	if err != nil {
+		return Sorting{}, err
+	}
+	if len(fields) == 0 {
+		if fields, dirs, err = requestedSort(s.Default); err != nil {
+			return Sorting{}, err
+		}
+	}
+	if s.MaxKeys > 0 && len(fields) > s.MaxKeys {
+		return Sorting{}, &SortError{
+			Field:  strings.Join(fields, ","),
+			Reason: fmt.Sprintf("at most %d sort fields are allowed", s.MaxKeys),
+		}
+	}
+
+	out := Sorting{
+		SortFields:     make([]string, 0, len(fields)+1),
+		SortDirections: make([]string, 0, len(fields)+1),
+		nulls:          make([]NullsOrder, 0, len(fields)+1),
+		nullsEmulation: s.NullsEmulation,
+	}
+	for i, field := range fields {
+		col, ok := s.Fields[field]
+		if !ok {
+			return Sorting{}, &SortError{Field: field, Reason: "unknown sort field"}
+		}
+		if slices.Contains(out.SortFields, col.Column) {
+			return Sorting{}, &SortError{Field: field, Reason: "duplicate sort field"}
+		}
+		out.SortFields = append(out.SortFields, col.Column)
+		out.SortDirections = append(out.SortDirections, dirs[i])
+		out.nulls = append(out.nulls, col.Nulls)
+	}
+	return out, nil
+}
+
+// requestedSort trims the requested fields, skipping empty ones, and normalizes
+// their directions to "asc" or "desc".
+func requestedSort(in Sorting) (fields, dirs []string, err error) {
+	for i, field := range in.SortFields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		dir := ""
+		if i < len(in.SortDirections) {
+			dir = strings.ToLower(strings.TrimSpace(in.SortDirections[i]))
+		}
+		switch dir {
+		case "", "asc":
+			dir = "asc"
+		case "desc":
+		default:
+			return nil, nil, &SortError{Field: field, Reason: fmt.Sprintf("invalid sort direction %q", dir)}
+		}
+
+		fields = append(fields, field)
+		dirs = append(dirs, dir)
+	}
+	return fields, dirs, nil
+}
@@ -0,0 +1,105 @@
+package primitive
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+func testSortSpec(nullsEmulation bool) SortSpec {
+	return SortSpec{
+		Fields: map[string]SortColumn{
+			"created_at": {Column: "u.created_at"},
+			"last_login": {Column: "u.last_login_at", Nulls: NullsLast},
+		},
+		Default:        NewSortingFromQueryParams("desc", "created_at"),
+		TieBreaker:     "u.id",
+		MaxKeys:        2,
+		NullsEmulation: nullsEmulation,
+	}
+}
+
+func TestSortSpec_Resolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		emulate   bool
+		direction string
+		field     string
+		want      string
+	}{
+		{
+			name: "default",
+			want: "SELECT id FROM users u ORDER BY u.created_at DESC, u.id DESC",
+		},
+		{
+			name:      "nulls last postgres",
+			direction: "DESC,asc",
+			field:     "last_login, created_at",
+			want:      "SELECT id FROM users u ORDER BY u.last_login_at DESC NULLS LAST, u.created_at ASC, u.id ASC",
+		},
+		{
+			name:    "nulls last mysql",
+			emulate: true,
+			field:   "last_login",
+			want:    "SELECT id FROM users u ORDER BY u.last_login_at IS NULL ASC, u.last_login_at ASC, u.id ASC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorting, err := testSortSpec(tt.emulate).FromQueryParams(tt.direction, tt.field)
+			if err != nil {
+				t.Fatalf("FromQueryParams() error = %v", err)
+			}
+			sql, _, err := sorting.BuildSquirrel(squirrel.Select("id").From("users u")).ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			if sql != tt.want {
+				t.Fatalf("sql = %q, want %q", sql, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortSpec_Resolve_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction string
+		field     string
+	}{
+		{name: "unknown field", field: "password; DROP TABLE users"},
+		{name: "invalid direction", direction: "sideways", field: "created_at"},
+		{name: "duplicate field", field: "created_at,created_at"},
+		{name: "too many keys", field: "created_at,last_login,created_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testSortSpec(false).FromQueryParams(tt.direction, tt.field)
+			var sortErr *SortError
+			if !errors.As(err, &sortErr) || !errors.Is(err, ErrInvalidSort) {
+				t.Fatalf("FromQueryParams() error = %v, want *SortError", err)
+			}
+		})
+	}
+}
+
+func TestSortSpec_Keyset(t *testing.T) {
+	keyset, err := testSortSpec(false).Keyset(NewSortingFromQueryParams("asc", "created_at"))
+	if err != nil {
+		t.Fatalf("Keyset() error = %v", err)
+	}
+	query, err := keyset.BuildSquirrel(squirrel.Select("id").From("users u"), nil, 10)
+	if err != nil {
+		t.Fatalf("BuildSquirrel() error = %v", err)
+	}
+	sql, _, err := query.ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	if want := "SELECT id FROM users u ORDER BY u.created_at ASC, u.id ASC LIMIT 11"; sql != want {
+		t.Fatalf("sql = %q, want %q", sql, want)
+	}
+}